@@ -0,0 +1,240 @@
+package db
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MigrationTable trùng với `table` trong dbconfig.yml để tương thích với sql-migrate
+const MigrationTable = "migrations"
+
+type Migration struct {
+	Id   string
+	Up   string
+	Down string
+}
+
+type MigrationStatus struct {
+	Id        string     `json:"id"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// LoadMigrations đọc các file *.sql theo định dạng sql-migrate
+// (-- +migrate Up / -- +migrate Down) và sắp xếp theo số thứ tự ở đầu tên file
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	var migrations []Migration
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+		m, err := parseMigration(path.Base(file), string(content))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		vi, vj := migrationVersion(migrations[i].Id), migrationVersion(migrations[j].Id)
+		if vi != vj {
+			return vi < vj
+		}
+		return migrations[i].Id < migrations[j].Id
+	})
+
+	return migrations, nil
+}
+
+func parseMigration(id, content string) (Migration, error) {
+	m := Migration{Id: id}
+	var up, down strings.Builder
+	var current *strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "-- +migrate") {
+			// StatementBegin/StatementEnd chỉ là đánh dấu, cả section được Exec một lần
+			fields := strings.Fields(strings.TrimPrefix(trimmed, "-- +migrate"))
+			if len(fields) > 0 && fields[0] == "Up" {
+				current = &up
+			} else if len(fields) > 0 && fields[0] == "Down" {
+				current = &down
+			}
+			continue
+		}
+		if current != nil {
+			current.WriteString(line)
+			current.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return m, fmt.Errorf("failed to parse migration %s: %w", id, err)
+	}
+	if strings.TrimSpace(up.String()) == "" {
+		return m, fmt.Errorf("migration %s has no Up section", id)
+	}
+
+	m.Up = up.String()
+	m.Down = down.String()
+	return m, nil
+}
+
+func migrationVersion(id string) int {
+	prefix := id
+	if i := strings.IndexFunc(id, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		prefix = id[:i]
+	}
+	v, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+func (s *Sql) ensureMigrationTable() error {
+	query := `CREATE TABLE IF NOT EXISTS ` + MigrationTable + ` (
+		id text PRIMARY KEY,
+		applied_at TIMESTAMPTZ
+	)`
+	if _, err := s.Db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create migration table: %w", err)
+	}
+	return nil
+}
+
+func (s *Sql) appliedMigrations() (map[string]time.Time, error) {
+	if err := s.ensureMigrationTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Db.Query(`SELECT id, applied_at FROM ` + MigrationTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[string]time.Time{}
+	for rows.Next() {
+		var id string
+		var appliedAt time.Time
+		if err := rows.Scan(&id, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan migration: %w", err)
+		}
+		applied[id] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// MigrateUp áp dụng tất cả migration chưa chạy, mỗi migration trong một transaction
+func (s *Sql) MigrateUp(fsys fs.FS) (int, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return 0, err
+	}
+	applied, err := s.appliedMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, m := range migrations {
+		if _, ok := applied[m.Id]; ok {
+			continue
+		}
+		if err := s.runMigration(m.Id, m.Up, true); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// MigrateDown rollback `steps` migration gần nhất (steps <= 0 nghĩa là tất cả)
+func (s *Sql) MigrateDown(fsys fs.FS, steps int) (int, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return 0, err
+	}
+	applied, err := s.appliedMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if steps > 0 && count >= steps {
+			break
+		}
+		m := migrations[i]
+		if _, ok := applied[m.Id]; !ok {
+			continue
+		}
+		if err := s.runMigration(m.Id, m.Down, false); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// MigrationStatus trả về trạng thái của từng migration được nhúng
+func (s *Sql) MigrationStatus(fsys fs.FS) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var status []MigrationStatus
+	for _, m := range migrations {
+		st := MigrationStatus{Id: m.Id}
+		if appliedAt, ok := applied[m.Id]; ok {
+			appliedAt := appliedAt
+			st.AppliedAt = &appliedAt
+		}
+		status = append(status, st)
+	}
+	return status, nil
+}
+
+func (s *Sql) runMigration(id, statements string, up bool) error {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", id, err)
+	}
+	defer tx.Rollback()
+
+	if strings.TrimSpace(statements) != "" {
+		if _, err := tx.Exec(statements); err != nil {
+			return fmt.Errorf("failed to run migration %s: %w", id, err)
+		}
+	}
+
+	if up {
+		_, err = tx.Exec(`INSERT INTO `+MigrationTable+` (id, applied_at) VALUES ($1, $2)`, id, time.Now())
+	} else {
+		_, err = tx.Exec(`DELETE FROM `+MigrationTable+` WHERE id = $1`, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", id, err)
+	}
+
+	return tx.Commit()
+}
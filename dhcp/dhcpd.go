@@ -0,0 +1,391 @@
+package dhcp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// statement là một câu lệnh "a b c;" hoặc một khối "a b { ... }" trong cú pháp của dhcpd
+type statement struct {
+	line     int
+	words    []string
+	block    bool
+	children []statement
+}
+
+// ParseDhcpd đọc dhcpd.leases (lease IPv4, ia-na/ia-ta của IPv6) và khai báo host có fixed-address
+// (trong file lease do OMAPI ghi hoặc trong dhcpd.conf, kể cả trong khối subnet/shared-network/group)
+// như reservation.
+func ParseDhcpd(r io.Reader) ([]Lease, error) {
+	tokens, err := tokenizeDhcpd(r)
+	if err != nil {
+		return nil, err
+	}
+	statements, rest, err := parseStatements(tokens)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("line %d: unexpected }", rest[0].line)
+	}
+
+	table := newLeaseTable()
+	if err := collectDhcpd(statements, table, map[string][]netip.Addr{}); err != nil {
+		return nil, err
+	}
+	return table.list(), nil
+}
+
+// containerBlocks là các khối của dhcpd.conf có thể chứa khai báo host
+var containerBlocks = map[string]bool{
+	"subnet":         true,
+	"subnet6":        true,
+	"shared-network": true,
+	"group":          true,
+	"pool":           true,
+	"pool6":          true,
+}
+
+// collectDhcpd ghi lease và host của statements vào table theo thứ tự trong file, đi vào các khối
+// subnet/shared-network/group. hosts là địa chỉ đã ghi của từng host để khai báo sau cùng tên thay thế.
+func collectDhcpd(statements []statement, table *leaseTable, hosts map[string][]netip.Addr) error {
+	for _, st := range statements {
+		if !st.block || len(st.words) == 0 {
+			continue
+		}
+		if containerBlocks[st.words[0]] {
+			if err := collectDhcpd(st.children, table, hosts); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(st.words) < 2 {
+			continue
+		}
+		switch st.words[0] {
+		case "lease":
+			lease, err := parseDhcpdLease(st)
+			if err != nil {
+				return err
+			}
+			table.put(lease)
+		case "ia-na", "ia-ta":
+			for _, child := range st.children {
+				if !child.block || len(child.words) < 2 || child.words[0] != "iaaddr" {
+					continue
+				}
+				lease, err := parseDhcpdLease(child)
+				if err != nil {
+					return err
+				}
+				if lease.Start == nil {
+					lease.Start = findTime(st.children, "cltt")
+				}
+				table.put(lease)
+			}
+		case "host":
+			name := st.words[1]
+			for _, addr := range hosts[name] {
+				table.remove(addr)
+			}
+			delete(hosts, name)
+			if hasStatement(st.children, "deleted") {
+				continue
+			}
+			for _, lease := range parseDhcpdHost(st) {
+				table.put(lease)
+				hosts[name] = append(hosts[name], lease.Address)
+			}
+		}
+	}
+	return nil
+}
+
+func parseDhcpdLease(st statement) (Lease, error) {
+	addr, err := netip.ParseAddr(st.words[1])
+	if err != nil {
+		return Lease{}, fmt.Errorf("line %d: invalid lease address %q", st.line, st.words[1])
+	}
+	lease := Lease{Line: st.line, Address: addr, State: model.LeaseInactive}
+
+	for _, child := range st.children {
+		if child.block || len(child.words) == 0 {
+			continue
+		}
+		w := child.words
+		switch {
+		case w[0] == "starts":
+			lease.Start, err = parseDhcpdTime(w[1:])
+		case w[0] == "ends":
+			lease.End, err = parseDhcpdTime(w[1:])
+		case w[0] == "binding" && len(w) == 3 && w[1] == "state":
+			lease.State = bindingState(w[2])
+		case w[0] == "hardware" && len(w) == 3:
+			lease.MAC = normalizeMAC(w[2])
+		case w[0] == "client-hostname" && len(w) == 2:
+			lease.Hostname = w[1]
+		}
+		if err != nil {
+			return Lease{}, fmt.Errorf("line %d: %v", child.line, err)
+		}
+	}
+	return lease, nil
+}
+
+func parseDhcpdHost(st statement) []Lease {
+	var mac string
+	var addrs []netip.Addr
+	for _, child := range st.children {
+		w := child.words
+		if child.block || len(w) < 2 {
+			continue
+		}
+		switch w[0] {
+		case "hardware":
+			if len(w) == 3 {
+				mac = normalizeMAC(w[2])
+			}
+		case "fixed-address", "fixed-address6":
+			// fixed-address có thể là danh sách "a, b" hoặc tên miền, tên miền bị bỏ qua
+			for _, s := range strings.Split(strings.Join(w[1:], " "), ",") {
+				if addr, err := netip.ParseAddr(strings.TrimSpace(s)); err == nil {
+					addrs = append(addrs, addr)
+				}
+			}
+		}
+	}
+
+	leases := make([]Lease, 0, len(addrs))
+	for _, addr := range addrs {
+		leases = append(leases, Lease{Line: st.line, Address: addr, MAC: mac, Hostname: st.words[1], State: model.LeaseReserved})
+	}
+	return leases
+}
+
+// bindingState: chỉ active là đang được cấp, free/expired/released/abandoned/backup... là inactive
+func bindingState(s string) string {
+	if s == "active" {
+		return model.LeaseActive
+	}
+	return model.LeaseInactive
+}
+
+// parseDhcpdTime nhận "4 2024/01/11 10:00:00" (UTC), "epoch 1704967200" hoặc "never"
+func parseDhcpdTime(words []string) (*time.Time, error) {
+	switch {
+	case len(words) == 1 && words[0] == "never":
+		return nil, nil
+	case len(words) == 2 && words[0] == "epoch":
+		sec, err := strconv.ParseInt(words[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q", strings.Join(words, " "))
+		}
+		t := time.Unix(sec, 0).UTC()
+		return &t, nil
+	case len(words) == 3:
+		t, err := time.Parse("2006/01/02 15:04:05", words[1]+" "+words[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q", strings.Join(words, " "))
+		}
+		return &t, nil
+	}
+	return nil, fmt.Errorf("invalid time %q", strings.Join(words, " "))
+}
+
+func findTime(statements []statement, keyword string) *time.Time {
+	for _, st := range statements {
+		if !st.block && len(st.words) > 1 && st.words[0] == keyword {
+			if t, err := parseDhcpdTime(st.words[1:]); err == nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+func hasStatement(statements []statement, keyword string) bool {
+	for _, st := range statements {
+		if len(st.words) > 0 && st.words[0] == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeMAC(s string) string {
+	if mac, err := net.ParseMAC(s); err == nil {
+		return mac.String()
+	}
+	return ""
+}
+
+type token struct {
+	line int
+	text string
+	// quoted là chuỗi trong ngoặc kép, không phải ký hiệu { } ;
+	quoted bool
+}
+
+// tokenizeDhcpd tách file thành token: từ, chuỗi trong ngoặc kép (đã bỏ ngoặc), "{", "}", ";".
+// Comment bắt đầu bằng # tới hết dòng.
+func tokenizeDhcpd(r io.Reader) ([]token, error) {
+	reader := bufio.NewReader(r)
+	var tokens []token
+	var cur strings.Builder
+	line, curLine := 1, 1
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, token{line: curLine, text: cur.String()})
+			cur.Reset()
+		}
+	}
+
+	for {
+		ch, err := reader.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read lease file: %w", err)
+		}
+
+		switch {
+		case ch == '#':
+			flush()
+			for ch != '\n' {
+				if ch, err = reader.ReadByte(); err != nil {
+					break
+				}
+			}
+			line++
+		case ch == '"':
+			flush()
+			start := line
+			var s strings.Builder
+			for {
+				ch, err = reader.ReadByte()
+				if err != nil {
+					return nil, fmt.Errorf("line %d: unterminated string", start)
+				}
+				if ch == '"' {
+					break
+				}
+				if ch == '\\' {
+					next, err := reader.ReadByte()
+					if err != nil {
+						return nil, fmt.Errorf("line %d: unterminated string", start)
+					}
+					s.WriteByte('\\')
+					ch = next
+				}
+				if ch == '\n' {
+					line++
+				}
+				s.WriteByte(ch)
+			}
+			tokens = append(tokens, token{line: start, text: unescape(s.String()), quoted: true})
+		case ch == '{' || ch == '}' || ch == ';':
+			flush()
+			tokens = append(tokens, token{line: line, text: string(ch)})
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+			flush()
+			if ch == '\n' {
+				line++
+			}
+		default:
+			if cur.Len() == 0 {
+				curLine = line
+			}
+			cur.WriteByte(ch)
+		}
+	}
+	flush()
+	return tokens, nil
+}
+
+// unescape xử lý \" \\ và mã bát phân \nnn trong chuỗi của dhcpd
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		i++
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// parseStatements dựng cây câu lệnh, trả về phần token còn lại bắt đầu bằng "}" của khối cha
+func parseStatements(tokens []token) ([]statement, []token, error) {
+	var statements []statement
+	var cur statement
+	for len(tokens) > 0 {
+		tok := tokens[0]
+		tokens = tokens[1:]
+		if tok.quoted {
+			if len(cur.words) == 0 {
+				cur.line = tok.line
+			}
+			cur.words = append(cur.words, tok.text)
+			continue
+		}
+
+		switch tok.text {
+		case ";":
+			if len(cur.words) > 0 {
+				statements = append(statements, cur)
+			}
+			cur = statement{}
+		case "{":
+			children, rest, err := parseStatements(tokens)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(rest) == 0 {
+				return nil, nil, fmt.Errorf("line %d: unterminated block", tok.line)
+			}
+			cur.block = true
+			cur.children = children
+			if cur.line == 0 {
+				cur.line = tok.line
+			}
+			statements = append(statements, cur)
+			cur = statement{}
+			tokens = rest[1:]
+		case "}":
+			if len(cur.words) > 0 {
+				return nil, nil, fmt.Errorf("line %d: missing ; before }", tok.line)
+			}
+			return statements, append([]token{tok}, tokens...), nil
+		default:
+			if len(cur.words) == 0 {
+				cur.line = tok.line
+			}
+			cur.words = append(cur.words, tok.text)
+		}
+	}
+	if len(cur.words) > 0 {
+		return nil, nil, fmt.Errorf("line %d: missing ;", cur.line)
+	}
+	return statements, nil, nil
+}
@@ -0,0 +1,164 @@
+package dhcp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// leaseString mô tả lease gọn để so sánh: "địa chỉ state mac hostname start end"
+func leaseString(l Lease) string {
+	format := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%s %s %s %s %s %s", l.Address, l.State, orDash(l.MAC), orDash(l.Hostname), format(l.Start), format(l.End))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func TestParseDhcpd(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+		err   string
+	}{
+		{
+			name: "lease",
+			input: `
+# The format of this file is documented in the dhcpd.leases(5) manual page.
+lease 192.168.1.10 {
+  starts 4 2024/01/11 10:00:00;
+  ends 4 2024/01/11 22:00:00;
+  binding state active;
+  hardware ethernet 00:11:22:AA:BB:CC;
+  client-hostname "laptop-01";
+}
+lease 192.168.1.11 {
+  starts epoch 1704967200;
+  ends never;
+  binding state free;
+}`,
+			want: []string{
+				"192.168.1.10 active 00:11:22:aa:bb:cc laptop-01 2024-01-11T10:00:00Z 2024-01-11T22:00:00Z",
+				"192.168.1.11 inactive - - 2024-01-11T10:00:00Z -",
+			},
+		},
+		{
+			name: "later lease replaces earlier",
+			input: `
+lease 10.0.0.5 { binding state active; client-hostname "old"; }
+lease 10.0.0.5 { binding state released; client-hostname "old"; }`,
+			want: []string{"10.0.0.5 inactive - old - -"},
+		},
+		{
+			name: "ia-na",
+			input: `
+ia-na "\001\000\000\000\000\001" {
+  cltt 4 2024/01/11 10:00:00;
+  iaaddr 2001:db8::10 {
+    binding state active;
+    ends 4 2024/01/11 22:00:00;
+  }
+  iaaddr 2001:db8::11 {
+    binding state expired;
+  }
+}`,
+			want: []string{
+				"2001:db8::10 active - - 2024-01-11T10:00:00Z 2024-01-11T22:00:00Z",
+				"2001:db8::11 inactive - - 2024-01-11T10:00:00Z -",
+			},
+		},
+		{
+			name: "host",
+			input: `
+host printer {
+  hardware ethernet 00:aa:bb:cc:dd:ee;
+  fixed-address 192.168.1.50, 192.168.1.51, printer.example.com;
+}`,
+			want: []string{
+				"192.168.1.50 reserved 00:aa:bb:cc:dd:ee printer - -",
+				"192.168.1.51 reserved 00:aa:bb:cc:dd:ee printer - -",
+			},
+		},
+		{
+			name: "deleted host",
+			input: `
+host printer { hardware ethernet 00:aa:bb:cc:dd:ee; fixed-address 192.168.1.50; }
+host scanner { fixed-address 192.168.1.60; }
+host printer { dynamic; deleted; }`,
+			want: []string{"192.168.1.60 reserved - scanner - -"},
+		},
+		{
+			name: "redeclared host moves address",
+			input: `
+host printer { fixed-address 192.168.1.50; }
+host printer { fixed-address 192.168.1.52; }`,
+			want: []string{"192.168.1.52 reserved - printer - -"},
+		},
+		{
+			name: "hosts inside dhcpd.conf blocks",
+			input: `
+option domain-name "example.com";
+shared-network office {
+  subnet 192.168.1.0 netmask 255.255.255.0 {
+    range 192.168.1.100 192.168.1.200;
+    host nas { hardware ethernet 00:11:22:33:44:55; fixed-address 192.168.1.20; }
+  }
+}
+group {
+  option routers 10.0.0.1;
+  host ap-1 { fixed-address 10.0.0.21; }
+}
+subnet6 2001:db8::/64 {
+  host srv6 { fixed-address6 2001:db8::20; }
+}`,
+			want: []string{
+				"10.0.0.21 reserved - ap-1 - -",
+				"192.168.1.20 reserved 00:11:22:33:44:55 nas - -",
+				"2001:db8::20 reserved - srv6 - -",
+			},
+		},
+		{
+			name:  "invalid lease address",
+			input: `lease 192.168.1.300 { binding state active; }`,
+			err:   `line 1: invalid lease address "192.168.1.300"`,
+		},
+		{
+			name:  "unterminated block",
+			input: "lease 192.168.1.10 {\n  binding state active;\n",
+			err:   "line 1: unterminated block",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leases, err := ParseDhcpd(strings.NewReader(tt.input))
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDhcpd: %v", err)
+			}
+			var got []string
+			for _, l := range leases {
+				got = append(got, leaseString(l))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("leases:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
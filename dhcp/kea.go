@@ -0,0 +1,88 @@
+package dhcp
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// Trạng thái lease của Kea: 0 default, 1 declined, 2 expired-reclaimed, 3 released
+const keaStateDefault = "0"
+
+// ParseKeaCSV đọc lease file CSV của Kea memfile (kea-leases4.csv hoặc kea-leases6.csv) theo tên
+// cột trong header. Lease IPv6 dạng prefix delegation (prefix_len khác 128) bị bỏ qua.
+func ParseKeaCSV(r io.Reader) ([]Lease, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kea lease header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, h := range header {
+		columns[strings.TrimSpace(h)] = i
+	}
+	for _, required := range []string{"address", "valid_lifetime", "expire"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("kea lease file has no %s column", required)
+		}
+	}
+
+	table := newLeaseTable()
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kea lease file: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		if prefixLen := get("prefix_len"); prefixLen != "" && prefixLen != "128" {
+			continue
+		}
+		addr, err := netip.ParseAddr(get("address"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid lease address %q", line, get("address"))
+		}
+		expire, err := strconv.ParseInt(get("expire"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid expire %q", line, get("expire"))
+		}
+		lifetime, err := strconv.ParseInt(get("valid_lifetime"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid valid_lifetime %q", line, get("valid_lifetime"))
+		}
+
+		// Lease được giải phóng có valid_lifetime 0, lease vô hạn có expire là số lớn nhất của uint32
+		end := time.Unix(expire, 0).UTC()
+		start := end.Add(-time.Duration(lifetime) * time.Second)
+		lease := Lease{Line: line, Address: addr, MAC: normalizeMAC(get("hwaddr")), Hostname: strings.TrimSuffix(get("hostname"), "."), Start: &start, End: &end, State: model.LeaseInactive}
+		if expire >= 1<<32-1 {
+			lease.End = nil
+		}
+		if state := get("state"); (state == "" || state == keaStateDefault) && lifetime > 0 {
+			lease.State = model.LeaseActive
+		}
+		table.put(lease)
+	}
+	return table.list(), nil
+}
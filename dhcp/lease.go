@@ -0,0 +1,86 @@
+// Package dhcp đọc lease và reservation của ISC dhcpd (dhcpd.leases, khai báo host) và Kea
+// (lease file CSV của memfile) để đồng bộ vào NetworkAsset.
+package dhcp
+
+import (
+	"net/netip"
+	"sort"
+	"time"
+
+	"github.com/sllpklls/template-backend-go/ipaddr"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+const (
+	FormatDhcpd = "dhcpd"
+	FormatKea   = "kea"
+)
+
+// Lease là trạng thái cuối cùng của một địa chỉ trong file lease
+type Lease struct {
+	Line     int
+	Address  netip.Addr
+	MAC      string
+	Hostname string
+	Start    *time.Time
+	End      *time.Time
+	State    string // model.LeaseActive, model.LeaseInactive hoặc model.LeaseReserved
+}
+
+// leaseTable giữ bản ghi mới nhất của mỗi địa chỉ: file lease là journal ghi nối tiếp,
+// bản ghi sau thay thế bản ghi trước
+type leaseTable struct {
+	leases map[netip.Addr]Lease
+}
+
+func newLeaseTable() *leaseTable {
+	return &leaseTable{leases: map[netip.Addr]Lease{}}
+}
+
+func (t *leaseTable) put(lease Lease) {
+	lease.Address = lease.Address.Unmap()
+	t.leases[lease.Address] = lease
+}
+
+func (t *leaseTable) remove(addr netip.Addr) {
+	delete(t.leases, addr.Unmap())
+}
+
+// list trả về lease theo thứ tự địa chỉ
+func (t *leaseTable) list() []Lease {
+	leases := make([]Lease, 0, len(t.leases))
+	for _, lease := range t.leases {
+		leases = append(leases, lease)
+	}
+	sort.Slice(leases, func(i, j int) bool { return leases[i].Address.Less(leases[j].Address) })
+	return leases
+}
+
+// ImportRows chuyển lease thành dòng import. Lease active đã hết hạn tại now được tính là inactive;
+// dòng inactive chỉ cập nhật asset DHCP đã có của địa chỉ, không tạo asset mới. Cột name để trống,
+// tên asset được gán khi import.
+func ImportRows(leases []Lease, now time.Time) []model.ImportRow {
+	var rows []model.ImportRow
+	for _, lease := range leases {
+		if lease.State == model.LeaseActive && lease.End != nil && !lease.End.After(now) {
+			lease.State = model.LeaseInactive
+		}
+		rows = append(rows, model.ImportRow{Row: lease.Line, Values: map[string]string{
+			"address":      lease.Address.String(),
+			"address_type": ipaddr.Family(lease.Address),
+			"mac_address":  lease.MAC,
+			"system_name":  lease.Hostname,
+			"lease_start":  formatTime(lease.Start),
+			"lease_end":    formatTime(lease.End),
+			"lease_state":  lease.State,
+		}})
+	}
+	return rows
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,133 @@
+package dhcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+// Actor ghi vào LastModifiedBy và lịch sử của asset do đồng bộ định kỳ tạo/sửa
+const Actor = "dhcp-sync"
+
+// Parse đọc file lease theo format (FormatDhcpd hoặc FormatKea)
+func Parse(format string, r io.Reader) ([]Lease, error) {
+	switch format {
+	case FormatDhcpd:
+		return ParseDhcpd(r)
+	case FormatKea:
+		return ParseKeaCSV(r)
+	}
+	return nil, fmt.Errorf("unknown lease format %q", format)
+}
+
+// Source là một file lease trên máy chạy service
+type Source struct {
+	Format string
+	Path   string
+}
+
+// ParseSources nhận danh sách "format:path" phân cách bởi dấu phẩy,
+// ví dụ "dhcpd:/var/lib/dhcp/dhcpd.leases,kea:/var/lib/kea/kea-leases4.csv"
+func ParseSources(s string) ([]Source, error) {
+	var sources []Source
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		format, path, ok := strings.Cut(item, ":")
+		if !ok || path == "" || (format != FormatDhcpd && format != FormatKea) {
+			return nil, fmt.Errorf("invalid lease source %q, expected dhcpd:<path> or kea:<path>", item)
+		}
+		sources = append(sources, Source{Format: format, Path: path})
+	}
+	return sources, nil
+}
+
+// Syncer đồng bộ định kỳ các file lease vào một dataset. Mọi nguồn được gộp trong một lần import
+// để lease của nguồn này không bị coi là mất khi import nguồn khác.
+type Syncer struct {
+	Repo      repository.NetworkAssetRepo
+	Sources   []Source
+	DatasetId int
+
+	running sync.Mutex
+}
+
+// Run đọc mọi nguồn và đồng bộ, bỏ qua nếu lần chạy trước chưa xong
+func (s *Syncer) Run(ctx context.Context) (*model.ImportReport, error) {
+	if !s.running.TryLock() {
+		return nil, fmt.Errorf("dhcp sync is already running")
+	}
+	defer s.running.Unlock()
+
+	leases, err := ReadSources(s.Sources)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := model.ImportOptions{Mode: model.ImportUpsert, DatasetId: s.DatasetId, DeactivateMissing: true}
+	return s.Repo.ImportLeases(ctx, ImportRows(leases, time.Now()), opts, Actor)
+}
+
+// ReadSources đọc mọi file lease và gộp theo địa chỉ, lease của file sau thay thế lease cùng địa chỉ
+// của file trước
+func ReadSources(sources []Source) ([]Lease, error) {
+	table := newLeaseTable()
+	for _, source := range sources {
+		leases, err := parseFile(source)
+		if err != nil {
+			return nil, err
+		}
+		for _, lease := range leases {
+			table.put(lease)
+		}
+	}
+	return table.list(), nil
+}
+
+func parseFile(source Source) ([]Lease, error) {
+	file, err := os.Open(source.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lease file: %w", err)
+	}
+	defer file.Close()
+
+	leases, err := Parse(source.Format, file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source.Path, err)
+	}
+	return leases, nil
+}
+
+// Schedule đồng bộ mỗi interval cho tới khi ctx bị hủy
+func (s *Syncer) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.Run(ctx)
+			if err != nil {
+				log.Errorf("dhcp sync failed: %v", err)
+				continue
+			}
+			if report.Failed > 0 {
+				log.Errorf("dhcp sync failed: %d invalid leases, nothing was written", report.Failed)
+				continue
+			}
+			log.Infof("dhcp sync: %d created, %d updated, %d unchanged, %d deactivated",
+				report.Created, report.Updated, report.Skipped, report.Deactivated)
+		}
+	}
+}
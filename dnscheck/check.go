@@ -0,0 +1,146 @@
+// Package dnscheck kiểm tra DNS thuận/nghịch của NetworkAsset: DNSHostName tra A/AAAA phải ra Address,
+// Address tra PTR phải ra DNSHostName; asset đã xóa mà DNS vẫn trỏ tới thì là bản ghi stale.
+package dnscheck
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// Check kiểm tra một asset, lỗi tra cứu được ghi vào Issues chứ không trả về
+func Check(ctx context.Context, resolver Resolver, asset model.NetworkAsset) model.DNSCheckResult {
+	result := model.DNSCheckResult{
+		AssetName:        asset.Name,
+		DNSHostName:      asset.DNSHostName,
+		Address:          asset.Address,
+		MarkAsDeleted:    asset.MarkAsDeleted,
+		Issues:           model.DNSIssues{},
+		ForwardAddresses: model.StringList{},
+		ReverseNames:     model.StringList{},
+		CheckedAt:        time.Now(),
+	}
+
+	host := normalizeHost(asset.DNSHostName)
+	addr, _ := netip.ParseAddr(asset.Address)
+	add := func(issueType, format string, args ...interface{}) {
+		result.Issues = append(result.Issues, model.DNSIssue{Type: issueType, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if host != "" {
+		addrs, err := resolver.LookupHost(ctx, host)
+		switch {
+		case err == nil:
+			for _, a := range addrs {
+				result.ForwardAddresses = append(result.ForwardAddresses, canonicalAddr(a))
+			}
+			pointsToAsset := addr.IsValid() && contains(result.ForwardAddresses, addr.String())
+			if asset.MarkAsDeleted {
+				if pointsToAsset || !addr.IsValid() {
+					add(model.DNSIssueStaleForward, "%s still resolves to %s", host, strings.Join(result.ForwardAddresses, ", "))
+				}
+			} else if addr.IsValid() && !pointsToAsset {
+				add(model.DNSIssueForwardMismatch, "%s resolves to %s, expected %s", host, strings.Join(result.ForwardAddresses, ", "), addr)
+			}
+		case isNotFound(err):
+			if !asset.MarkAsDeleted {
+				add(model.DNSIssueForwardMissing, "%s has no A/AAAA record", host)
+			}
+		default:
+			add(model.DNSIssueLookupError, "lookup %s: %v", host, err)
+		}
+	}
+
+	if addr.IsValid() {
+		names, err := resolver.LookupAddr(ctx, addr.String())
+		switch {
+		case err == nil:
+			for _, n := range names {
+				result.ReverseNames = append(result.ReverseNames, normalizeHost(n))
+			}
+			pointsToAsset := host != "" && matchesHost(result.ReverseNames, host)
+			if asset.MarkAsDeleted {
+				if pointsToAsset || host == "" {
+					add(model.DNSIssueStaleReverse, "%s still has PTR %s", addr, strings.Join(result.ReverseNames, ", "))
+				}
+			} else if host != "" && !pointsToAsset {
+				add(model.DNSIssueReverseMismatch, "PTR of %s is %s, expected %s", addr, strings.Join(result.ReverseNames, ", "), host)
+			}
+		case isNotFound(err):
+			if !asset.MarkAsDeleted {
+				add(model.DNSIssueReverseMissing, "%s has no PTR record", addr)
+			}
+		default:
+			add(model.DNSIssueLookupError, "reverse lookup %s: %v", addr, err)
+		}
+	}
+
+	result.Status = status(result.Issues)
+	return result
+}
+
+// status lấy trạng thái nghiêm trọng nhất trong các issue
+func status(issues model.DNSIssues) string {
+	rank := map[string]int{
+		model.DNSCheckOK:       0,
+		model.DNSCheckMissing:  1,
+		model.DNSCheckMismatch: 2,
+		model.DNSCheckStale:    3,
+		model.DNSCheckError:    4,
+	}
+	result := model.DNSCheckOK
+	for _, issue := range issues {
+		s := issueStatus(issue.Type)
+		if rank[s] > rank[result] {
+			result = s
+		}
+	}
+	return result
+}
+
+func issueStatus(issueType string) string {
+	switch issueType {
+	case model.DNSIssueForwardMissing, model.DNSIssueReverseMissing:
+		return model.DNSCheckMissing
+	case model.DNSIssueForwardMismatch, model.DNSIssueReverseMismatch:
+		return model.DNSCheckMismatch
+	case model.DNSIssueStaleForward, model.DNSIssueStaleReverse:
+		return model.DNSCheckStale
+	}
+	return model.DNSCheckError
+}
+
+// normalizeHost: chữ thường, bỏ dấu chấm cuối của FQDN
+func normalizeHost(s string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), ".")
+}
+
+// matchesHost: PTR khớp khi bằng host hoặc host là tên ngắn của PTR (srv01 khớp srv01.corp.local)
+func matchesHost(names []string, host string) bool {
+	for _, n := range names {
+		if n == host || strings.HasPrefix(n, host+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func canonicalAddr(s string) string {
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return addr.Unmap().String()
+	}
+	return s
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
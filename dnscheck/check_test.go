@@ -0,0 +1,231 @@
+package dnscheck
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// stubServer là DNS server UDP trên 127.0.0.1 trả lời từ bảng bản ghi cố định.
+// Tên không có bản ghi nào trả về NXDOMAIN, tên có bản ghi khác loại trả về NOERROR rỗng.
+type stubServer struct {
+	conn    net.PacketConn
+	forward map[string][]netip.Addr // tên FQDN chữ thường -> địa chỉ A/AAAA
+	reverse map[string][]string     // tên in-addr.arpa/ip6.arpa -> PTR
+}
+
+func startStub(t *testing.T, forward map[string][]string, reverse map[string][]string) *stubServer {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &stubServer{conn: conn, forward: map[string][]netip.Addr{}, reverse: map[string][]string{}}
+	for host, addrs := range forward {
+		for _, a := range addrs {
+			s.forward[strings.ToLower(host)+"."] = append(s.forward[strings.ToLower(host)+"."], netip.MustParseAddr(a))
+		}
+	}
+	for addr, names := range reverse {
+		arpa, err := dnsmessage.NewName(reverseName(netip.MustParseAddr(addr)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.reverse[arpa.String()] = names
+	}
+	go s.serve()
+	t.Cleanup(func() { conn.Close() })
+	return s
+}
+
+func (s *stubServer) addr() string { return s.conn.LocalAddr().String() }
+
+func (s *stubServer) serve() {
+	buf := make([]byte, 512)
+	for {
+		n, from, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		var req dnsmessage.Message
+		if err := req.Unpack(buf[:n]); err != nil || len(req.Questions) != 1 {
+			continue
+		}
+		msg := s.answer(req)
+		resp, err := msg.Pack()
+		if err != nil {
+			continue
+		}
+		s.conn.WriteTo(resp, from)
+	}
+}
+
+func (s *stubServer) answer(req dnsmessage.Message) dnsmessage.Message {
+	q := req.Questions[0]
+	name := strings.ToLower(q.Name.String())
+	resp := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: req.ID, Response: true, Authoritative: true, RecursionDesired: req.RecursionDesired},
+		Questions: req.Questions,
+	}
+	hdr := dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET, TTL: 60}
+
+	addrs, hasForward := s.forward[name]
+	ptrs, hasReverse := s.reverse[name]
+	if !hasForward && !hasReverse {
+		resp.RCode = dnsmessage.RCodeNameError
+		return resp
+	}
+
+	switch q.Type {
+	case dnsmessage.TypeA:
+		for _, a := range addrs {
+			if a.Is4() {
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: hdr, Body: &dnsmessage.AResource{A: a.As4()}})
+			}
+		}
+	case dnsmessage.TypeAAAA:
+		for _, a := range addrs {
+			if a.Is6() {
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: hdr, Body: &dnsmessage.AAAAResource{AAAA: a.As16()}})
+			}
+		}
+	case dnsmessage.TypePTR:
+		for _, p := range ptrs {
+			resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: hdr, Body: &dnsmessage.PTRResource{PTR: dnsmessage.MustNewName(p + ".")}})
+		}
+	}
+	return resp
+}
+
+// reverseName trả về tên PTR của địa chỉ, ví dụ 1.0.0.10.in-addr.arpa.
+func reverseName(addr netip.Addr) string {
+	if addr.Is4() {
+		b := addr.As4()
+		return net.IPv4(b[3], b[2], b[1], b[0]).String() + ".in-addr.arpa."
+	}
+	const hex = "0123456789abcdef"
+	b := addr.As16()
+	var sb strings.Builder
+	for i := len(b) - 1; i >= 0; i-- {
+		sb.WriteByte(hex[b[i]&0x0f])
+		sb.WriteByte('.')
+		sb.WriteByte(hex[b[i]>>4])
+		sb.WriteByte('.')
+	}
+	return sb.String() + "ip6.arpa."
+}
+
+func TestCheck(t *testing.T) {
+	stub := startStub(t,
+		map[string][]string{
+			"web.corp.test":   {"10.0.0.1", "2001:db8::1"},
+			"web":             {"10.0.0.1"},
+			"noptr.corp.test": {"10.0.0.3"},
+			"moved.corp.test": {"10.0.0.99"},
+			"old.corp.test":   {"10.0.0.5"},
+		},
+		map[string][]string{
+			"10.0.0.1":    {"web.corp.test"},
+			"2001:db8::1": {"web.corp.test"},
+			"10.0.0.2":    {"nohost.corp.test"},
+			"10.0.0.4":    {"other.corp.test"},
+			"10.0.0.5":    {"old.corp.test"},
+		},
+	)
+	resolver := NewResolver(stub.addr(), 2*time.Second)
+
+	tests := []struct {
+		name   string
+		asset  model.NetworkAsset
+		status string
+		issues []string
+	}{
+		{
+			name:   "ok",
+			asset:  model.NetworkAsset{Name: "web", DNSHostName: "web.corp.test", Address: "10.0.0.1"},
+			status: model.DNSCheckOK,
+		},
+		{
+			name:   "ok ipv6 and fqdn with trailing dot",
+			asset:  model.NetworkAsset{Name: "web6", DNSHostName: "WEB.corp.test.", Address: "2001:db8::1"},
+			status: model.DNSCheckOK,
+		},
+		{
+			name:   "short host name matches ptr",
+			asset:  model.NetworkAsset{Name: "web-short", DNSHostName: "web", Address: "10.0.0.1"},
+			status: model.DNSCheckOK,
+		},
+		{
+			name:   "missing A/AAAA",
+			asset:  model.NetworkAsset{Name: "nohost", DNSHostName: "nohost.corp.test", Address: "10.0.0.2"},
+			status: model.DNSCheckMissing,
+			issues: []string{model.DNSIssueForwardMissing},
+		},
+		{
+			name:   "missing PTR",
+			asset:  model.NetworkAsset{Name: "noptr", DNSHostName: "noptr.corp.test", Address: "10.0.0.3"},
+			status: model.DNSCheckMissing,
+			issues: []string{model.DNSIssueReverseMissing},
+		},
+		{
+			name:   "forward and reverse mismatch",
+			asset:  model.NetworkAsset{Name: "moved", DNSHostName: "moved.corp.test", Address: "10.0.0.4"},
+			status: model.DNSCheckMismatch,
+			issues: []string{model.DNSIssueForwardMismatch, model.DNSIssueReverseMismatch},
+		},
+		{
+			name:   "stale records of deleted asset",
+			asset:  model.NetworkAsset{Name: "old", DNSHostName: "old.corp.test", Address: "10.0.0.5", MarkAsDeleted: true},
+			status: model.DNSCheckStale,
+			issues: []string{model.DNSIssueStaleForward, model.DNSIssueStaleReverse},
+		},
+		{
+			name:   "deleted asset without records",
+			asset:  model.NetworkAsset{Name: "gone", DNSHostName: "gone.corp.test", Address: "10.0.0.6", MarkAsDeleted: true},
+			status: model.DNSCheckOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result := Check(ctx, resolver, tt.asset)
+			if result.Status != tt.status {
+				t.Errorf("status = %q, want %q (issues %+v)", result.Status, tt.status, result.Issues)
+			}
+			var got []string
+			for _, issue := range result.Issues {
+				got = append(got, issue.Type)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.issues, ",") {
+				t.Errorf("issues = %v, want %v", got, tt.issues)
+			}
+		})
+	}
+}
+
+func TestCheckLookupError(t *testing.T) {
+	// Server không trả lời: lỗi tra cứu được ghi vào issue, không phải missing
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	resolver := NewResolver(conn.LocalAddr().String(), 200*time.Millisecond)
+	result := Check(ctx, resolver, model.NetworkAsset{Name: "web", DNSHostName: "web.corp.test", Address: "10.0.0.1"})
+	if result.Status != model.DNSCheckError {
+		t.Errorf("status = %q, want %q (issues %+v)", result.Status, model.DNSCheckError, result.Issues)
+	}
+}
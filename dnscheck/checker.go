@@ -0,0 +1,95 @@
+package dnscheck
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+type Checker struct {
+	Repo     repository.DNSCheckRepo
+	Resolver Resolver
+	// Workers là số asset được tra cứu song song, mặc định 8
+	Workers int
+	// Timeout cho mỗi asset, mặc định 5 giây
+	Timeout time.Duration
+
+	running sync.Mutex
+}
+
+// Run kiểm tra các asset và lưu kết quả, chỉ một lần chạy tại một thời điểm
+func (c *Checker) Run(ctx context.Context, names []string, datasetId int) (model.DNSCheckSummary, error) {
+	if !c.running.TryLock() {
+		return model.DNSCheckSummary{}, errors.DNSCheckRunning
+	}
+	defer c.running.Unlock()
+
+	summary := model.DNSCheckSummary{StartedAt: time.Now(), Results: []model.DNSCheckResult{}}
+	assets, err := c.Repo.GetAssetsForDNSCheck(ctx, names, datasetId)
+	if err != nil {
+		return summary, err
+	}
+
+	workers := c.Workers
+	if workers <= 0 {
+		workers = 8
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	jobs := make(chan int)
+	results := make([]model.DNSCheckResult, len(assets))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				assetCtx, cancel := context.WithTimeout(ctx, timeout)
+				results[i] = Check(assetCtx, c.Resolver, assets[i])
+				cancel()
+			}
+		}()
+	}
+	for i := range assets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := c.Repo.SaveDNSCheckResults(ctx, results); err != nil {
+		return summary, err
+	}
+	for _, result := range results {
+		summary.Add(result)
+	}
+	summary.FinishedAt = time.Now()
+	return summary, nil
+}
+
+// Schedule kiểm tra toàn bộ asset mỗi interval cho tới khi ctx bị hủy
+func (c *Checker) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			summary, err := c.Run(ctx, nil, 0)
+			if err != nil {
+				log.Errorf("scheduled dns check failed: %v", err)
+				continue
+			}
+			log.Infof("scheduled dns check: %d assets, %d ok, %d missing, %d mismatch, %d stale, %d error",
+				summary.Total, summary.OK, summary.Missing, summary.Mismatch, summary.Stale, summary.Error)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package dnscheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// Resolver tra cứu DNS, *net.Resolver thỏa mãn interface này. Test có thể dùng NewResolver trỏ tới
+// một stub DNS server chạy local, hoặc tự cài đặt Resolver.
+type Resolver interface {
+	// LookupHost trả về các địa chỉ A/AAAA của host
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	// LookupAddr trả về các tên PTR của địa chỉ
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+// NewResolver trả về resolver của hệ thống nếu server rỗng, ngược lại mọi truy vấn được gửi tới
+// server (host:port), ví dụ DNS nội bộ hoặc stub DNS server khi test
+func NewResolver(server string, timeout time.Duration) Resolver {
+	if server == "" {
+		return net.DefaultResolver
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// isNotFound: tên/địa chỉ không có bản ghi (NXDOMAIN hoặc không có bản ghi loại cần tìm)
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,8 @@
+package errors
+
+import "errors"
+
+var (
+	CINotFound = errors.New("CI not found")
+	CIConflict = errors.New("CI already exists")
+)
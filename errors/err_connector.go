@@ -0,0 +1,8 @@
+package errors
+
+import "errors"
+
+var (
+	ConnectorRunning       = errors.New("connector is already running")
+	ConnectorNotConfigured = errors.New("connector is not configured")
+)
@@ -0,0 +1,7 @@
+package errors
+
+import "errors"
+
+var (
+	DNSCheckRunning = errors.New("DNS check is already running")
+)
@@ -0,0 +1,10 @@
+package errors
+
+import "errors"
+
+var (
+	EtlJobNotFound = errors.New("ETL job not found")
+	EtlJobConflict = errors.New("ETL job already exists")
+	EtlJobRunning  = errors.New("ETL job is already running")
+	EtlRunNotFound = errors.New("ETL run not found")
+)
@@ -0,0 +1,8 @@
+package errors
+
+import "errors"
+
+var (
+	MappingNotFound = errors.New("mapping not found")
+	MappingInvalid  = errors.New("invalid mapping")
+)
@@ -0,0 +1,10 @@
+package errors
+
+import "errors"
+
+var (
+	NetworkAssetConflict = errors.New("Network asset already exists")
+	AddressUnavailable   = errors.New("Requested address is not available")
+	SubnetFull           = errors.New("Not enough free addresses in subnet")
+	AddressConflict      = errors.New("Address is already used by another active network asset")
+)
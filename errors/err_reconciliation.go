@@ -0,0 +1,9 @@
+package errors
+
+import "errors"
+
+var (
+	ReconciliationJobNotFound = errors.New("Reconciliation job not found")
+	ReconciliationJobConflict = errors.New("Reconciliation job already exists")
+	ReconciliationRunNotFound = errors.New("Reconciliation run not found")
+)
@@ -0,0 +1,9 @@
+package errors
+
+import "errors"
+
+var (
+	RelationshipNotFound = errors.New("Relationship not found")
+	RelationshipConflict = errors.New("Relationship already exists")
+	UnknownCIClass       = errors.New("Unknown CI class")
+)
@@ -0,0 +1,10 @@
+package errors
+
+import "errors"
+
+var (
+	SubnetNotFound      = errors.New("Subnet not found")
+	SubnetConflict      = errors.New("Subnet already exists")
+	ReservationNotFound = errors.New("Reservation not found")
+	ReservationConflict = errors.New("Reservation overlaps an existing reservation")
+)
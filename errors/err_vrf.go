@@ -0,0 +1,13 @@
+package errors
+
+import "errors"
+
+var (
+	VrfNotFound     = errors.New("VRF not found")
+	VrfConflict     = errors.New("VRF already exists")
+	VrfInUse        = errors.New("VRF is still used by VLANs, subnets or network assets")
+	VlanNotFound    = errors.New("VLAN not found")
+	VlanConflict    = errors.New("VLAN already exists")
+	VlanInUse       = errors.New("VLAN is still used by subnets or network assets")
+	VlanVrfMismatch = errors.New("VLAN belongs to a different VRF")
+)
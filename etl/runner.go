@@ -0,0 +1,120 @@
+package etl
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+// Source đọc dữ liệu của một loại nguồn và ghi vào dataset đích của job
+type Source interface {
+	// Validate kiểm tra config khi tạo/sửa job
+	Validate(config model.EtlConfig) []model.FieldError
+	// Run chạy job và cộng số liệu, lỗi từng bản ghi vào run; error là lỗi làm hỏng cả lần chạy
+	Run(ctx context.Context, job model.EtlJob, run *model.EtlRun) error
+}
+
+// Actor ghi vào LastModifiedBy và lịch sử của asset do job ghi
+func Actor(job model.EtlJob) string {
+	return "etl:" + job.Name
+}
+
+// Runner chạy job và lưu mọi lần chạy vào EtlRuns, mỗi job chỉ có một lần chạy tại một thời điểm
+type Runner struct {
+	Repo    repository.EtlRepo
+	Sources map[string]Source
+
+	mu      sync.Mutex
+	running map[int64]bool
+}
+
+// Start tạo bản ghi lần chạy và chạy job trong nền, trả về ngay bản ghi ở trạng thái Running
+func (r *Runner) Start(ctx context.Context, job model.EtlJob, triggeredBy string) (model.EtlRun, error) {
+	run, err := r.begin(ctx, job, triggeredBy)
+	if err != nil {
+		return run, err
+	}
+
+	go func() {
+		if _, err := r.execute(context.Background(), job, run); err != nil {
+			log.Errorf("etl job %s failed: %v", job.Name, err)
+		}
+	}()
+	return run, nil
+}
+
+// Run chạy job và chờ tới khi xong
+func (r *Runner) Run(ctx context.Context, job model.EtlJob, triggeredBy string) (model.EtlRun, error) {
+	run, err := r.begin(ctx, job, triggeredBy)
+	if err != nil {
+		return run, err
+	}
+	return r.execute(ctx, job, run)
+}
+
+func (r *Runner) begin(ctx context.Context, job model.EtlJob, triggeredBy string) (model.EtlRun, error) {
+	r.mu.Lock()
+	if r.running == nil {
+		r.running = map[int64]bool{}
+	}
+	if r.running[job.Id] {
+		r.mu.Unlock()
+		return model.EtlRun{}, errors.EtlJobRunning
+	}
+	r.running[job.Id] = true
+	r.mu.Unlock()
+
+	run, err := r.Repo.CreateRun(ctx, model.EtlRun{
+		JobId:       job.Id,
+		Status:      model.EtlRunning,
+		TriggeredBy: triggeredBy,
+	})
+	if err != nil {
+		r.release(job.Id)
+	}
+	return run, err
+}
+
+func (r *Runner) release(jobId int64) {
+	r.mu.Lock()
+	delete(r.running, jobId)
+	r.mu.Unlock()
+}
+
+// execute chạy job đã có bản ghi lần chạy, lần chạy luôn được ghi kết quả kể cả khi lỗi
+func (r *Runner) execute(ctx context.Context, job model.EtlJob, run model.EtlRun) (model.EtlRun, error) {
+	defer r.release(job.Id)
+
+	runErr := r.run(ctx, job, &run)
+	if runErr != nil {
+		run.Status = model.EtlFailed
+		run.ErrorMessage = runErr.Error()
+	} else {
+		run.Status = model.EtlSuccess
+	}
+
+	if err := r.Repo.FinishRun(ctx, run); err != nil {
+		return run, err
+	}
+	return run, runErr
+}
+
+func (r *Runner) run(ctx context.Context, job model.EtlJob, run *model.EtlRun) (err error) {
+	source, ok := r.Sources[job.SourceType]
+	if !ok {
+		return fmt.Errorf("unknown source type %s", job.SourceType)
+	}
+
+	// Lỗi lập trình trong một source không được làm chết scheduler
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("source %s panicked: %v", job.SourceType, p)
+		}
+	}()
+	return source.Run(ctx, job, run)
+}
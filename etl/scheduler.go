@@ -0,0 +1,103 @@
+package etl
+
+import (
+	"context"
+	"sync"
+
+	"github.com/labstack/gommon/log"
+	"github.com/robfig/cron/v3"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+// SchedulerActor là TriggeredBy của lần chạy do scheduler khởi động
+const SchedulerActor = "scheduler"
+
+// ParseSchedule kiểm tra biểu thức cron 5 trường, hỗ trợ @daily, @every 15m và tiền tố CRON_TZ=
+func ParseSchedule(spec string) error {
+	_, err := cron.ParseStandard(spec)
+	return err
+}
+
+// Scheduler chạy các job có Schedule trong server. Lịch được đọc lại từ DB mỗi khi job thay đổi
+// (Reload), và job được đọc lại ngay trước mỗi lần chạy nên luôn dùng config mới nhất.
+type Scheduler struct {
+	Repo   repository.EtlRepo
+	Runner *Runner
+
+	mu   sync.Mutex
+	cron *cron.Cron
+}
+
+// Start đánh dấu lỗi các lần chạy dở từ lần khởi động trước, nạp lịch và chạy tới khi ctx bị hủy
+func (s *Scheduler) Start(ctx context.Context) error {
+	if err := s.Repo.AbortRunningRuns(ctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.cron = cron.New()
+	s.mu.Unlock()
+	if err := s.Reload(ctx); err != nil {
+		return err
+	}
+	s.cron.Start()
+
+	go func() {
+		<-ctx.Done()
+		s.cron.Stop()
+	}()
+	return nil
+}
+
+// Reload thay toàn bộ lịch bằng các job đang bật trong DB, không làm gì nếu scheduler chưa Start
+func (s *Scheduler) Reload(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cron == nil {
+		return nil
+	}
+
+	jobs, err := s.Repo.GetJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range s.cron.Entries() {
+		s.cron.Remove(entry.ID)
+	}
+	for _, job := range jobs {
+		if job.Disabled || job.Schedule == "" {
+			continue
+		}
+		id := job.Id
+		if _, err := s.cron.AddFunc(job.Schedule, func() { s.runScheduled(id) }); err != nil {
+			log.Errorf("etl job %s has invalid schedule %q: %v", job.Name, job.Schedule, err)
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) runScheduled(id int64) {
+	ctx := context.Background()
+	job, err := s.Repo.GetJobById(ctx, id)
+	if err != nil {
+		log.Errorf("etl job %d: %v", id, err)
+		return
+	}
+	if job.Disabled {
+		return
+	}
+
+	run, err := s.Runner.Run(ctx, *job, SchedulerActor)
+	if err == errors.EtlJobRunning {
+		log.Warnf("etl job %s skipped: previous run is still running", job.Name)
+		return
+	}
+	if err != nil {
+		log.Errorf("etl job %s failed: %v", job.Name, err)
+		return
+	}
+	log.Infof("etl job %s: %d read, %d inserted, %d updated, %d skipped, %d failed",
+		job.Name, run.Read, run.Inserted, run.Updated, run.Skipped, run.Failed)
+}
@@ -0,0 +1,623 @@
+package etl
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sllpklls/template-backend-go/dhcp"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/importer"
+	"github.com/sllpklls/template-backend-go/mapping"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/pipeline"
+	"github.com/sllpklls/template-backend-go/remedy"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+// RemedyPasswordEnvPrefix là tiền tố bắt buộc của password_env trong job Remedy, để job không đọc
+// được biến môi trường khác của server (DB_PASSWORD, SECRET_KEY...)
+const RemedyPasswordEnvPrefix = "REMEDY_"
+
+// DefaultSources trả về các nguồn có sẵn. Các nguồn đọc file chỉ được đọc file nằm trong fileRoot
+// (đường dẫn tương đối tính từ fileRoot), fileRoot rỗng thì không dùng được. Job Remedy chỉ được gọi
+// các URL trong remedyURLs. Config "mapping" của mọi nguồn là id file mapping trong mappings.
+func DefaultSources(assets repository.NetworkAssetRepo, connectors repository.ConnectorRepo, fileRoot string, remedyURLs []string, mappings *mapping.Store) map[string]Source {
+	files := fileAccess{root: fileRoot}
+	allowed := map[string]bool{}
+	for _, u := range remedyURLs {
+		if u = normalizeURL(u); u != "" {
+			allowed[u] = true
+		}
+	}
+	return map[string]Source{
+		model.EtlSourceCSV:    &csvSource{repo: assets, files: files, mappings: mappings},
+		model.EtlSourceZone:   &zoneSource{repo: assets, files: files, mappings: mappings},
+		model.EtlSourceDHCP:   &dhcpSource{repo: assets, files: files, mappings: mappings},
+		model.EtlSourceNmap:   &nmapSource{repo: assets, files: files, mappings: mappings},
+		model.EtlSourceRemedy: &remedySource{repo: assets, state: connectors, mappings: mappings, allowedURLs: allowed},
+	}
+}
+
+type fileAccess struct {
+	root string
+}
+
+// resolve trả về đường dẫn thật của file (đã giải symlink), từ chối file nằm ngoài root
+func (f fileAccess) resolve(path string) (string, error) {
+	if f.root == "" {
+		return "", fmt.Errorf("file sources are disabled, ETL_FILE_ROOT is not set")
+	}
+	if path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	root, err := filepath.EvalSymlinks(f.root)
+	if err != nil {
+		return "", fmt.Errorf("invalid ETL_FILE_ROOT: %w", err)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(f.root, path)
+	}
+	resolved, err := evalSymlinks(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s: %w", path, err)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s is outside %s", path, f.root)
+	}
+	return resolved, nil
+}
+
+// evalSymlinks giải symlink của path. File chưa tồn tại (job tạo trước khi có file) thì giải thư mục
+// cha gần nhất đang tồn tại, phần còn lại chưa có trên đĩa nên không thể là symlink.
+func evalSymlinks(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err == nil || !os.IsNotExist(err) {
+		return resolved, err
+	}
+	parent := filepath.Dir(path)
+	if parent == path {
+		return "", err
+	}
+	resolvedParent, err := evalSymlinks(parent)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(resolvedParent, filepath.Base(path)), nil
+}
+
+func (f fileAccess) open(path string) (*os.File, error) {
+	resolved, err := f.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// fileConfig là config chung của các nguồn đọc một file
+type fileConfig struct {
+	Path string `json:"path"`
+	// Mode mặc định là upsert, replace-dataset xóa mềm asset của dataset đích không có trong file
+	Mode      string `json:"mode"`
+	RequestId string `json:"request_id"`
+	// AllowDuplicateAddress cho phép ghi địa chỉ trùng asset khác trong dataset
+	AllowDuplicateAddress bool   `json:"allow_duplicate_address"`
+	Mapping               string `json:"mapping"`
+}
+
+func (c fileConfig) importOptions(job model.EtlJob) model.ImportOptions {
+	mode := c.Mode
+	if mode == "" {
+		mode = model.ImportUpsert
+	}
+	return model.ImportOptions{
+		Mode:                  mode,
+		DatasetId:             job.TargetDatasetId,
+		RequestId:             c.RequestId,
+		AllowDuplicateAddress: c.AllowDuplicateAddress,
+	}
+}
+
+func (c fileConfig) validate(files fileAccess) []model.FieldError {
+	var errs []model.FieldError
+	if _, err := files.resolve(c.Path); err != nil {
+		errs = append(errs, model.FieldError{Field: "config.path", Message: err.Error()})
+	}
+	if c.Mode != "" && !model.IsValidImportMode(c.Mode) {
+		errs = append(errs, model.FieldError{Field: "config.mode", Message: "must be insert, upsert or replace-dataset"})
+	}
+	return errs
+}
+
+// loadMapping đọc mapping theo id trong config, nil nếu job không dùng mapping
+func loadMapping(mappings *mapping.Store, id string) (*mapping.Mapping, error) {
+	if id == "" {
+		return nil, nil
+	}
+	if mappings == nil {
+		return nil, errors.MappingNotFound
+	}
+	return mappings.Get(id)
+}
+
+func validateMapping(mappings *mapping.Store, id string) []model.FieldError {
+	if _, err := loadMapping(mappings, id); err != nil {
+		return []model.FieldError{{Field: "config.mapping", Message: err.Error()}}
+	}
+	return nil
+}
+
+// finishMapping ghi các dòng không áp được mapping vào run, khi đó không dòng nào được import
+func finishMapping(run *model.EtlRun, mapped int, failed []model.ImportRowResult) error {
+	if len(failed) == 0 {
+		return nil
+	}
+	report := &model.ImportReport{}
+	for _, result := range failed {
+		report.Add(result)
+	}
+	run.AddReport(report)
+	run.Read += mapped
+	return fmt.Errorf("%d rows failed mapping, nothing was written", len(failed))
+}
+
+func decodeConfig(config model.EtlConfig, v interface{}) []model.FieldError {
+	if err := config.Decode(v); err != nil {
+		return []model.FieldError{{Field: "config", Message: err.Error()}}
+	}
+	return nil
+}
+
+// finishImport cộng report vào run; import có dòng lỗi không ghi gì nên cả lần chạy là lỗi
+func finishImport(run *model.EtlRun, report *model.ImportReport) error {
+	run.AddReport(report)
+	if !report.Committed {
+		return fmt.Errorf("%d invalid rows, nothing was written", report.Failed)
+	}
+	return nil
+}
+
+// csvSource: file CSV có header như import CSV, config {"path", "mode", "request_id", "allow_duplicate_address",
+// "mapping", "batch_size"}. Có mapping thì header là tên cột của nguồn. batch_size > 0 thì file được
+// đọc và ghi theo lô qua pipeline: dòng lỗi được bỏ qua thay vì làm hỏng cả file.
+type csvSource struct {
+	repo     repository.NetworkAssetRepo
+	files    fileAccess
+	mappings *mapping.Store
+}
+
+type csvConfig struct {
+	fileConfig
+	BatchSize int `json:"batch_size"`
+}
+
+func (s *csvSource) Validate(config model.EtlConfig) []model.FieldError {
+	var c csvConfig
+	if errs := decodeConfig(config, &c); errs != nil {
+		return errs
+	}
+	errs := append(c.validate(s.files), validateMapping(s.mappings, c.Mapping)...)
+	if c.BatchSize < 0 {
+		errs = append(errs, model.FieldError{Field: "config.batch_size", Message: "must not be negative"})
+	}
+	if c.BatchSize > 0 && c.Mode == model.ImportReplaceDataset {
+		errs = append(errs, model.FieldError{Field: "config.mode", Message: "replace-dataset cannot be used with batch_size"})
+	}
+	return errs
+}
+
+func (s *csvSource) Run(ctx context.Context, job model.EtlJob, run *model.EtlRun) error {
+	var c csvConfig
+	if err := job.Config.Decode(&c); err != nil {
+		return err
+	}
+	m, err := loadMapping(s.mappings, c.Mapping)
+	if err != nil {
+		return err
+	}
+	file, err := s.files.open(c.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if c.BatchSize > 0 {
+		return s.stream(ctx, job, c, m, file, run)
+	}
+
+	parse := importer.ParseNetworkAssetCSV
+	if m != nil {
+		parse = importer.ParseSourceCSV
+	}
+	rows, headerErrs, err := parse(file)
+	if err != nil {
+		return err
+	}
+	if len(headerErrs) > 0 {
+		for _, e := range headerErrs {
+			run.AddError(model.EtlError{Field: e.Field, Message: e.Message})
+		}
+		return fmt.Errorf("invalid csv header")
+	}
+	if m != nil {
+		var failed []model.ImportRowResult
+		rows, failed = m.ApplyRows(rows, false)
+		if err := finishMapping(run, len(rows), failed); err != nil {
+			return err
+		}
+	}
+
+	report, err := s.repo.ImportNetworkAssets(ctx, rows, c.importOptions(job), Actor(job))
+	if err != nil {
+		return err
+	}
+	return finishImport(run, report)
+}
+
+func (s *csvSource) stream(ctx context.Context, job model.EtlJob, c csvConfig, m *mapping.Mapping, file io.Reader, run *model.EtlRun) error {
+	p := &pipeline.Pipeline{
+		Extractor: pipeline.CSVExtractor(file),
+		Transformers: []pipeline.Transformer{
+			pipeline.Trim(),
+			pipeline.LowercaseHostname(),
+			pipeline.DeriveAddressType(),
+		},
+		Loader:    &pipeline.RepoLoader{Repo: s.repo, Options: c.importOptions(job), Actor: Actor(job)},
+		BatchSize: c.BatchSize,
+	}
+	if m != nil {
+		p.Extractor = pipeline.SourceCSVExtractor(file)
+		p.Transformers = append([]pipeline.Transformer{pipeline.MapFields(m)}, p.Transformers...)
+	}
+
+	stats, err := p.Run(ctx)
+	addStats(run, stats)
+
+	var headerErr *pipeline.HeaderError
+	if stderrors.As(err, &headerErr) {
+		for _, e := range headerErr.Errors {
+			run.AddError(model.EtlError{Field: e.Field, Message: e.Message})
+		}
+		return fmt.Errorf("invalid csv header")
+	}
+	return err
+}
+
+// addStats cộng kết quả của pipeline vào lần chạy
+func addStats(run *model.EtlRun, stats *pipeline.Stats) {
+	run.Read += stats.Read
+	run.Inserted += stats.Created
+	run.Updated += stats.Updated
+	run.Skipped += stats.Skipped
+	run.Deleted += stats.Deleted
+	run.Failed += stats.Failed
+	for _, e := range stats.Errors {
+		run.AddError(e)
+	}
+}
+
+// zoneSource: zone file BIND, config như csv cộng "origin". Mapping ghi đè lên field sinh từ zone.
+type zoneSource struct {
+	repo     repository.NetworkAssetRepo
+	files    fileAccess
+	mappings *mapping.Store
+}
+
+type zoneConfig struct {
+	fileConfig
+	Origin string `json:"origin"`
+}
+
+func (s *zoneSource) Validate(config model.EtlConfig) []model.FieldError {
+	var c zoneConfig
+	if errs := decodeConfig(config, &c); errs != nil {
+		return errs
+	}
+	return append(c.validate(s.files), validateMapping(s.mappings, c.Mapping)...)
+}
+
+func (s *zoneSource) Run(ctx context.Context, job model.EtlJob, run *model.EtlRun) error {
+	var c zoneConfig
+	if err := job.Config.Decode(&c); err != nil {
+		return err
+	}
+	m, err := loadMapping(s.mappings, c.Mapping)
+	if err != nil {
+		return err
+	}
+	file, err := s.files.open(c.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rows, err := importer.ParseZone(file, c.Origin)
+	if err != nil {
+		return err
+	}
+	if m != nil {
+		var failed []model.ImportRowResult
+		rows, failed = m.ApplyRows(rows, true)
+		if err := finishMapping(run, len(rows), failed); err != nil {
+			return err
+		}
+	}
+
+	report, err := s.repo.ImportZone(ctx, rows, c.importOptions(job), Actor(job))
+	if err != nil {
+		return err
+	}
+	return finishImport(run, report)
+}
+
+// dhcpSource: các file lease được gộp trong một lần đồng bộ như DHCP_LEASE_FILES,
+// config {"files": [{"format": "dhcpd", "path": "..."}], "request_id", "mapping"}
+type dhcpSource struct {
+	repo     repository.NetworkAssetRepo
+	files    fileAccess
+	mappings *mapping.Store
+}
+
+type dhcpConfig struct {
+	Files []struct {
+		Format string `json:"format"`
+		Path   string `json:"path"`
+	} `json:"files"`
+	RequestId string `json:"request_id"`
+	Mapping   string `json:"mapping"`
+}
+
+func (s *dhcpSource) Validate(config model.EtlConfig) []model.FieldError {
+	var c dhcpConfig
+	if errs := decodeConfig(config, &c); errs != nil {
+		return errs
+	}
+	errs := validateMapping(s.mappings, c.Mapping)
+	if len(c.Files) == 0 {
+		errs = append(errs, model.FieldError{Field: "config.files", Message: "required"})
+	}
+	for i, f := range c.Files {
+		if f.Format != dhcp.FormatDhcpd && f.Format != dhcp.FormatKea {
+			errs = append(errs, model.FieldError{Field: fmt.Sprintf("config.files[%d].format", i), Message: "must be dhcpd or kea"})
+		}
+		if _, err := s.files.resolve(f.Path); err != nil {
+			errs = append(errs, model.FieldError{Field: fmt.Sprintf("config.files[%d].path", i), Message: err.Error()})
+		}
+	}
+	return errs
+}
+
+func (s *dhcpSource) Run(ctx context.Context, job model.EtlJob, run *model.EtlRun) error {
+	var c dhcpConfig
+	if err := job.Config.Decode(&c); err != nil {
+		return err
+	}
+	m, err := loadMapping(s.mappings, c.Mapping)
+	if err != nil {
+		return err
+	}
+	var sources []dhcp.Source
+	for _, f := range c.Files {
+		path, err := s.files.resolve(f.Path)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, dhcp.Source{Format: f.Format, Path: path})
+	}
+
+	leases, err := dhcp.ReadSources(sources)
+	if err != nil {
+		return err
+	}
+
+	rows := dhcp.ImportRows(leases, time.Now())
+	if m != nil {
+		var failed []model.ImportRowResult
+		rows, failed = m.ApplyRows(rows, true)
+		if err := finishMapping(run, len(rows), failed); err != nil {
+			return err
+		}
+	}
+
+	// Mọi file của job được gộp trong một lần import như dhcp.Syncer
+	opts := model.ImportOptions{Mode: model.ImportUpsert, DatasetId: job.TargetDatasetId, RequestId: c.RequestId, DeactivateMissing: true}
+	report, err := s.repo.ImportLeases(ctx, rows, opts, Actor(job))
+	if err != nil {
+		return err
+	}
+	return finishImport(run, report)
+}
+
+// nmapSource: output XML của nmap, config như csv
+type nmapSource struct {
+	repo     repository.NetworkAssetRepo
+	files    fileAccess
+	mappings *mapping.Store
+}
+
+func (s *nmapSource) Validate(config model.EtlConfig) []model.FieldError {
+	var c fileConfig
+	if errs := decodeConfig(config, &c); errs != nil {
+		return errs
+	}
+	return append(c.validate(s.files), validateMapping(s.mappings, c.Mapping)...)
+}
+
+func (s *nmapSource) Run(ctx context.Context, job model.EtlJob, run *model.EtlRun) error {
+	var c fileConfig
+	if err := job.Config.Decode(&c); err != nil {
+		return err
+	}
+	m, err := loadMapping(s.mappings, c.Mapping)
+	if err != nil {
+		return err
+	}
+	file, err := s.files.open(c.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hosts, err := importer.ParseNmapXML(file)
+	if err != nil {
+		return err
+	}
+	if m != nil {
+		var failed []model.ImportRowResult
+		hosts, failed = m.ApplyHosts(hosts)
+		if err := finishMapping(run, len(hosts), failed); err != nil {
+			return err
+		}
+	}
+
+	report, err := s.repo.ImportScan(ctx, hosts, c.importOptions(job), Actor(job))
+	if err != nil {
+		return err
+	}
+	return finishImport(run, report)
+}
+
+// remedySource: connector Remedy với high-water mark riêng của job. Mật khẩu không lưu trong config
+// mà đọc từ biến môi trường có tên password_env (phải bắt đầu bằng RemedyPasswordEnvPrefix), url phải
+// nằm trong danh sách server cho phép để mật khẩu không bị gửi tới host khác.
+type remedySource struct {
+	repo        repository.NetworkAssetRepo
+	state       repository.ConnectorRepo
+	mappings    *mapping.Store
+	allowedURLs map[string]bool // URL đã chuẩn hóa bằng normalizeURL
+}
+
+type remedyConfig struct {
+	URL         string `json:"url"`
+	Username    string `json:"username"`
+	PasswordEnv string `json:"password_env"`
+	Form        string `json:"form"`
+	Dataset     string `json:"dataset"`
+	PageSize    int    `json:"page_size"`
+	// Mapping thay cho remedy.DefaultMapping, phải ghi instance_id
+	Mapping string `json:"mapping"`
+	// Full đọc lại toàn bộ mỗi lần chạy thay vì từ high-water mark
+	Full bool `json:"full"`
+}
+
+func (s *remedySource) Validate(config model.EtlConfig) []model.FieldError {
+	var c remedyConfig
+	if errs := decodeConfig(config, &c); errs != nil {
+		return errs
+	}
+	errs := validateMapping(s.mappings, c.Mapping)
+	if c.URL == "" {
+		errs = append(errs, model.FieldError{Field: "config.url", Message: "required"})
+	} else if err := s.checkURL(c.URL); err != nil {
+		errs = append(errs, model.FieldError{Field: "config.url", Message: err.Error()})
+	}
+	if c.Username == "" {
+		errs = append(errs, model.FieldError{Field: "config.username", Message: "required"})
+	}
+	if c.PasswordEnv == "" {
+		errs = append(errs, model.FieldError{Field: "config.password_env", Message: "required"})
+	} else if err := checkPasswordEnv(c.PasswordEnv); err != nil {
+		errs = append(errs, model.FieldError{Field: "config.password_env", Message: err.Error()})
+	}
+	if c.PageSize < 0 {
+		errs = append(errs, model.FieldError{Field: "config.page_size", Message: "must not be negative"})
+	}
+	return errs
+}
+
+func (s *remedySource) Run(ctx context.Context, job model.EtlJob, run *model.EtlRun) error {
+	var c remedyConfig
+	if err := job.Config.Decode(&c); err != nil {
+		return err
+	}
+	// Kiểm tra lại vì job có thể được tạo trước khi danh sách URL thay đổi
+	if err := s.checkURL(c.URL); err != nil {
+		return err
+	}
+	if err := checkPasswordEnv(c.PasswordEnv); err != nil {
+		return err
+	}
+	password := os.Getenv(c.PasswordEnv)
+	if password == "" {
+		return fmt.Errorf("environment variable %s is not set", c.PasswordEnv)
+	}
+
+	m, err := loadMapping(s.mappings, c.Mapping)
+	if err != nil {
+		return err
+	}
+
+	connector := &remedy.Connector{
+		Client:    &remedy.Client{BaseURL: c.URL, Username: c.Username, Password: password},
+		Repo:      s.repo,
+		State:     s.state,
+		Name:      fmt.Sprintf("etl-job-%d", job.Id),
+		Form:      c.Form,
+		Dataset:   c.Dataset,
+		DatasetId: job.TargetDatasetId,
+		Mapping:   m,
+		PageSize:  c.PageSize,
+		Actor:     Actor(job),
+	}
+	result, err := connector.Run(ctx, c.Full)
+	if result != nil {
+		run.Read += result.Read
+		run.Inserted += result.Created
+		run.Updated += result.Updated
+		run.Skipped += result.Skipped
+		run.Deleted += result.Deleted
+		run.Failed += result.Failed
+		for _, e := range result.Errors {
+			run.AddError(model.EtlError{Field: e.Field, Message: e.Message})
+		}
+	}
+	return err
+}
+
+// checkURL từ chối URL không nằm trong danh sách ETL_REMEDY_URLS
+func (s *remedySource) checkURL(u string) error {
+	if len(s.allowedURLs) == 0 {
+		return fmt.Errorf("remedy sources are disabled, ETL_REMEDY_URLS is not set")
+	}
+	if !s.allowedURLs[normalizeURL(u)] {
+		return fmt.Errorf("url %s is not in ETL_REMEDY_URLS", u)
+	}
+	return nil
+}
+
+// checkPasswordEnv chỉ cho phép biến môi trường REMEDY_<TÊN>
+func checkPasswordEnv(name string) error {
+	rest := strings.TrimPrefix(name, RemedyPasswordEnvPrefix)
+	if rest == name || rest == "" {
+		return fmt.Errorf("must be %s followed by a name", RemedyPasswordEnvPrefix)
+	}
+	for _, r := range rest {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return fmt.Errorf("must contain only A-Z, 0-9 and _")
+		}
+	}
+	return nil
+}
+
+// normalizeURL bỏ khoảng trắng và "/" cuối, chữ thường scheme và host
+func normalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimRight(u.Path, "/")
+	return u.String()
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// Writer ghi từng NetworkAsset ra output ngay khi nhận được, không giữ toàn bộ kết quả trong bộ nhớ
+type Writer interface {
+	Write(asset *model.NetworkAsset) error
+	// Flush đẩy dữ liệu đang đệm xuống output
+	Flush() error
+	// Close ghi phần kết thúc của file (nếu có), không đóng output
+	Close() error
+}
+
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+	newWriter   func(w io.Writer) (Writer, error)
+}
+
+var formats = map[string]Format{
+	"csv": {
+		Name:        "csv",
+		ContentType: "text/csv; charset=utf-8",
+		Extension:   "csv",
+		newWriter:   newCSVWriter,
+	},
+	"jsonl": {
+		Name:        "jsonl",
+		ContentType: "application/x-ndjson",
+		Extension:   "jsonl",
+		newWriter:   newJSONLWriter,
+	},
+	"xlsx": {
+		Name:        "xlsx",
+		ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		Extension:   "xlsx",
+		newWriter:   newXLSXWriter,
+	},
+}
+
+func LookupFormat(name string) (Format, bool) {
+	f, ok := formats[name]
+	return f, ok
+}
+
+func (f Format) NewWriter(w io.Writer) (Writer, error) {
+	if f.newWriter == nil {
+		return nil, fmt.Errorf("unknown export format %s", f.Name)
+	}
+	return f.newWriter(w)
+}
+
+// columns là header của file export: mọi field của NetworkAsset theo tên json
+func columns() []string {
+	fields := model.NetworkAssetFields()
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+func values(asset *model.NetworkAsset) []string {
+	fields := model.NetworkAssetFields()
+	row := make([]string, len(fields))
+	for i, f := range fields {
+		row[i] = asset.GetField(f.Name)
+	}
+	return row
+}
@@ -0,0 +1,32 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// jsonlWriter ghi mỗi asset là một dòng JSON với đầy đủ field như API
+type jsonlWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) (Writer, error) {
+	buf := bufio.NewWriter(w)
+	return &jsonlWriter{buf: buf, enc: json.NewEncoder(buf)}, nil
+}
+
+func (j *jsonlWriter) Write(asset *model.NetworkAsset) error {
+	return j.enc.Encode(asset)
+}
+
+func (j *jsonlWriter) Flush() error {
+	return j.buf.Flush()
+}
+
+func (j *jsonlWriter) Close() error {
+	return j.Flush()
+}
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"net/netip"
+
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/zonefile"
+)
+
+// ZoneRecords sinh bản ghi cho zone origin từ các asset: A/AAAA nếu là forward zone, PTR nếu là
+// reverse zone. Asset có hostname không hợp lệ, nằm ngoài zone hoặc địa chỉ ngoài dải bị bỏ qua.
+func ZoneRecords(origin string, assets []model.NetworkAsset) []zonefile.Record {
+	origin = zonefile.Fqdn(origin)
+	prefix, reverse := zonefile.ReversePrefix(origin)
+
+	var records []zonefile.Record
+	for _, asset := range assets {
+		addr, err := netip.ParseAddr(asset.Address)
+		if err != nil {
+			continue
+		}
+		addr = addr.Unmap()
+		host := zonefile.Fqdn(asset.DNSHostName)
+		if !zonefile.ValidName(host) {
+			continue
+		}
+
+		if reverse {
+			// PTR phải trỏ tới FQDN, hostname ngắn (không có dấu chấm) không dùng được
+			if !prefix.Contains(addr) || !hasDot(host) {
+				continue
+			}
+			records = append(records, zonefile.Record{Name: zonefile.ReverseName(addr), Class: "IN", Type: "PTR", Data: []string{host}})
+			continue
+		}
+
+		if !zonefile.InZone(host, origin) {
+			continue
+		}
+		recordType := "A"
+		if addr.Is6() {
+			recordType = "AAAA"
+		}
+		records = append(records, zonefile.Record{Name: host, Class: "IN", Type: recordType, Data: []string{addr.String()}})
+	}
+	return records
+}
+
+// hasDot cho biết FQDN có ít nhất hai label ("srv01." là tên ngắn)
+func hasDot(fqdn string) bool {
+	for i := 0; i < len(fqdn)-1; i++ {
+		if fqdn[i] == '.' {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+// CIHandler xử lý CRUD/search cho mọi class trong registry. Mỗi method trả về
+// echo.HandlerFunc gắn với một class, router sẽ sinh route /api/v1/{class.Path}.
+type CIHandler struct {
+	Registry *model.CIRegistry
+	CIRepo   repository.CIRepo
+}
+
+func (h *CIHandler) GetClasses(c echo.Context) error {
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy danh sách CI class thành công",
+		Data:       h.Registry.Classes(),
+	})
+}
+
+func (h *CIHandler) GetAll(class *model.CIClass) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		filter := model.CIFilter{Page: 1, Limit: 10}
+
+		if p, err := strconv.Atoi(c.QueryParam("page")); err == nil && p > 0 {
+			filter.Page = p
+		}
+		if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 && l <= 100 {
+			filter.Limit = l
+		}
+		query := map[string]string{}
+		for _, f := range class.Fields {
+			if v := c.QueryParam(f.Name); v != "" {
+				query[f.Name] = v
+			}
+		}
+		fields, fieldErrs := class.ParseFilter(query)
+		if len(fieldErrs) > 0 {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Validation failed",
+				Data:       fieldErrs,
+			})
+		}
+		filter.Fields = fields
+
+		records, err := h.CIRepo.GetAllCIs(c.Request().Context(), class, filter)
+		if err != nil {
+			log.Error(err.Error())
+			return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "Failed to get " + class.Name,
+				Data:       nil,
+			})
+		}
+
+		total, err := h.CIRepo.GetTotalCIs(c.Request().Context(), class, filter)
+		if err != nil {
+			log.Error(err.Error())
+			return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "Failed to get total count",
+				Data:       nil,
+			})
+		}
+
+		return c.JSON(http.StatusOK, model.ListResponseAsset{
+			StatusCode: http.StatusOK,
+			Message:    "Lấy danh sách " + class.Name + " thành công",
+			Data:       records,
+			Total:      total,
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+		})
+	}
+}
+
+func (h *CIHandler) GetByName(class *model.CIClass) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		record, err := h.CIRepo.GetCIByName(c.Request().Context(), class, c.Param("name"))
+		if err != nil {
+			return h.errorResponse(c, class, err)
+		}
+
+		return c.JSON(http.StatusOK, model.ResponseAsset{
+			StatusCode: http.StatusOK,
+			Message:    "Lấy thông tin " + class.Name + " thành công",
+			Data:       record,
+		})
+	}
+}
+
+func (h *CIHandler) Create(class *model.CIClass) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Chỉ bind body: bind vào map sẽ lẫn cả path param và query param
+		var body model.CIRecord
+		if err := (&echo.DefaultBinder{}).BindBody(c, &body); err != nil {
+			log.Error(err.Error())
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Invalid JSON format",
+				Data:       nil,
+			})
+		}
+
+		record, fieldErrs := class.Validate(body, false)
+		if len(fieldErrs) > 0 {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Validation failed",
+				Data:       fieldErrs,
+			})
+		}
+
+		if err := h.CIRepo.CreateCI(c.Request().Context(), class, record); err != nil {
+			return h.errorResponse(c, class, err)
+		}
+
+		return c.JSON(http.StatusCreated, model.ResponseAsset{
+			StatusCode: http.StatusCreated,
+			Message:    "Tạo " + class.Name + " thành công",
+			Data:       record,
+		})
+	}
+}
+
+func (h *CIHandler) Update(class *model.CIClass) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Chỉ bind body: bind vào map sẽ lẫn cả path param và query param
+		var body model.CIRecord
+		if err := (&echo.DefaultBinder{}).BindBody(c, &body); err != nil {
+			log.Error(err.Error())
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Invalid JSON format",
+				Data:       nil,
+			})
+		}
+
+		record, fieldErrs := class.Validate(body, true)
+		if len(fieldErrs) > 0 {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Validation failed",
+				Data:       fieldErrs,
+			})
+		}
+
+		if err := h.CIRepo.UpdateCI(c.Request().Context(), class, c.Param("name"), record); err != nil {
+			return h.errorResponse(c, class, err)
+		}
+
+		return c.JSON(http.StatusOK, model.ResponseAsset{
+			StatusCode: http.StatusOK,
+			Message:    "Cập nhật " + class.Name + " thành công",
+			Data:       record,
+		})
+	}
+}
+
+func (h *CIHandler) Delete(class *model.CIClass) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := h.CIRepo.DeleteCI(c.Request().Context(), class, c.Param("name")); err != nil {
+			return h.errorResponse(c, class, err)
+		}
+
+		return c.JSON(http.StatusOK, model.ResponseAsset{
+			StatusCode: http.StatusOK,
+			Message:    "Xóa " + class.Name + " thành công",
+			Data:       nil,
+		})
+	}
+}
+
+func (h *CIHandler) errorResponse(c echo.Context, class *model.CIClass, err error) error {
+	switch err {
+	case errors.CINotFound:
+		return c.JSON(http.StatusNotFound, model.ResponseAsset{
+			StatusCode: http.StatusNotFound,
+			Message:    class.Name + " not found",
+			Data:       nil,
+		})
+	case errors.CIConflict:
+		return c.JSON(http.StatusConflict, model.ResponseAsset{
+			StatusCode: http.StatusConflict,
+			Message:    class.Name + " already exists",
+			Data:       nil,
+		})
+	}
+
+	log.Error(err.Error())
+	return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+		StatusCode: http.StatusInternalServerError,
+		Message:    "Failed to process " + class.Name,
+		Data:       nil,
+	})
+}
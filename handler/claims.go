@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// getClaims lấy claims do middleware.JWTMiddleware gắn vào context, nil nếu route không yêu cầu JWT
+func getClaims(c echo.Context) *model.JwtCustomClaims {
+	token, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return nil
+	}
+	claims, ok := token.Claims.(*model.JwtCustomClaims)
+	if !ok {
+		return nil
+	}
+	return claims
+}
+
+// getActor trả về UserId của người gọi API
+func getActor(c echo.Context) string {
+	if claims := getClaims(c); claims != nil {
+		return claims.UserId
+	}
+	return ""
+}
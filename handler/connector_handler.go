@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/remedy"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+type ConnectorHandler struct {
+	ConnectorRepo repository.ConnectorRepo
+	// Remedy nil nếu chưa cấu hình REMEDY_URL
+	Remedy *remedy.Connector
+}
+
+// RunRemedy đồng bộ ngay các BMC_IPEndpoint thay đổi từ lần chạy trước, full=true đọc lại toàn bộ
+func (h *ConnectorHandler) RunRemedy(c echo.Context) error {
+	if h.Remedy == nil {
+		return c.JSON(http.StatusServiceUnavailable, model.ResponseAsset{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    errors.ConnectorNotConfigured.Error(),
+			Data:       nil,
+		})
+	}
+	full, _ := strconv.ParseBool(c.QueryParam("full"))
+
+	run, err := h.Remedy.Run(c.Request().Context(), full)
+	if err != nil {
+		if err == errors.ConnectorRunning {
+			return c.JSON(http.StatusConflict, model.ResponseAsset{
+				StatusCode: http.StatusConflict,
+				Message:    err.Error(),
+				Data:       nil,
+			})
+		}
+		log.Error(err.Error())
+		// Các trang đã ghi trước khi lỗi vẫn được giữ, run cho biết đã đồng bộ tới đâu
+		return c.JSON(http.StatusBadGateway, model.ResponseAsset{
+			StatusCode: http.StatusBadGateway,
+			Message:    "Remedy sync failed",
+			Data:       run,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Đồng bộ Remedy thành công",
+		Data:       run,
+	})
+}
+
+// GetRemedyState trả về high-water mark, thời điểm và lỗi của lần chạy gần nhất
+func (h *ConnectorHandler) GetRemedyState(c echo.Context) error {
+	name := remedy.DefaultName
+	if h.Remedy != nil && h.Remedy.Name != "" {
+		name = h.Remedy.Name
+	}
+
+	state, err := h.ConnectorRepo.GetConnectorState(c.Request().Context(), name)
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to get connector state",
+			Data:       nil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy trạng thái connector thành công",
+		Data:       state,
+	})
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/dhcp"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// ImportLeases nhận file lease DHCP (multipart field "file"), format=dhcpd (dhcpd.leases, dhcpd.conf)
+// hoặc kea (lease file CSV). Lease đang dùng được upsert vào dataset_id; deactivate_missing=true chuyển asset
+// DHCP của dataset không có trong file sang inactive (chỉ dùng khi file chứa mọi lease của dataset). dry_run=true chỉ trả về report. mapping=<id> áp file mapping
+// lên các field sinh từ lease (address, mac_address, system_name, lease_*), kết quả ghi đè field đó.
+func (h *NetworkAssetHandler) ImportLeases(c echo.Context) error {
+	format := c.QueryParam("format")
+	opts := model.ImportOptions{
+		Mode:      model.ImportUpsert,
+		RequestId: c.QueryParam("request_id"),
+	}
+	opts.DryRun, _ = strconv.ParseBool(c.QueryParam("dry_run"))
+	opts.DeactivateMissing, _ = strconv.ParseBool(c.QueryParam("deactivate_missing"))
+
+	var paramErrs []model.FieldError
+	if format != dhcp.FormatDhcpd && format != dhcp.FormatKea {
+		paramErrs = append(paramErrs, model.FieldError{Field: "format", Message: "must be dhcpd or kea"})
+	}
+	if datasetStr := c.QueryParam("dataset_id"); datasetStr != "" {
+		id, err := strconv.Atoi(datasetStr)
+		if err != nil {
+			paramErrs = append(paramErrs, model.FieldError{Field: "dataset_id", Message: "must be an integer"})
+		}
+		opts.DatasetId = id
+	}
+	if len(paramErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       paramErrs,
+		})
+	}
+
+	m, err := h.importMapping(c)
+	if err != nil {
+		return mappingErrorResponse(c, err)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Lease file is required",
+			Data:       nil,
+		})
+	}
+	if fileHeader.Size > maxImportSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, model.ResponseAsset{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Message:    "Lease file is too large",
+			Data:       nil,
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Failed to read lease file",
+			Data:       nil,
+		})
+	}
+	defer file.Close()
+
+	leases, err := dhcp.Parse(format, file)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid lease file",
+			Data:       []model.FieldError{{Field: "file", Message: err.Error()}},
+		})
+	}
+
+	rows := dhcp.ImportRows(leases, time.Now())
+	var mappingFailed []model.ImportRowResult
+	if m != nil {
+		rows, mappingFailed = m.ApplyRows(rows, true)
+		if len(mappingFailed) > 0 && !opts.DryRun {
+			return mappingFailedResponse(c, opts, mappingFailed)
+		}
+	}
+
+	report, err := h.NetworkAssetRepo.ImportLeases(c.Request().Context(), rows, opts, getActor(c))
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to import leases",
+			Data:       nil,
+		})
+	}
+	addFailedRows(report, mappingFailed)
+
+	if report.Failed > 0 && !opts.DryRun {
+		return c.JSON(http.StatusUnprocessableEntity, model.ResponseAsset{
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    "Import failed, no rows were written",
+			Data:       report,
+		})
+	}
+
+	message := "Import lease DHCP thành công"
+	if opts.DryRun {
+		message = "Dry run import lease DHCP thành công"
+	}
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    message,
+		Data:       report,
+	})
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/dnscheck"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/model/req"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+type DNSCheckHandler struct {
+	DNSCheckRepo repository.DNSCheckRepo
+	Checker      *dnscheck.Checker
+}
+
+// RunDNSCheck kiểm tra DNS thuận/nghịch ngay, trả về tổng hợp và các asset có vấn đề
+func (h *DNSCheckHandler) RunDNSCheck(c echo.Context) error {
+	var request req.ReqDNSCheck
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&request); err != nil {
+			log.Error(err.Error())
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Invalid JSON format",
+				Data:       nil,
+			})
+		}
+	}
+
+	summary, err := h.Checker.Run(c.Request().Context(), request.Names, request.DatasetId)
+	if err != nil {
+		if err == errors.DNSCheckRunning {
+			return c.JSON(http.StatusConflict, model.ResponseAsset{
+				StatusCode: http.StatusConflict,
+				Message:    err.Error(),
+				Data:       nil,
+			})
+		}
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to run DNS check",
+			Data:       nil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Kiểm tra DNS thành công",
+		Data:       summary,
+	})
+}
+
+// GetDNSCheckResults trả về kết quả kiểm tra mới nhất của từng asset, lọc theo status
+func (h *DNSCheckHandler) GetDNSCheckResults(c echo.Context) error {
+	status := c.QueryParam("status")
+	page := 1
+	limit := 10
+	if p, err := strconv.Atoi(c.QueryParam("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	total, err := h.DNSCheckRepo.GetTotalDNSCheckResults(c.Request().Context(), status)
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to get total count",
+			Data:       nil,
+		})
+	}
+
+	results, err := h.DNSCheckRepo.GetDNSCheckResults(c.Request().Context(), status, page, limit)
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to get DNS check results",
+			Data:       nil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ListResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy kết quả kiểm tra DNS thành công",
+		Data:       results,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+	})
+}
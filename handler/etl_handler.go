@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/etl"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+type EtlHandler struct {
+	EtlRepo   repository.EtlRepo
+	Runner    *etl.Runner
+	Scheduler *etl.Scheduler
+}
+
+func (h *EtlHandler) GetJobs(c echo.Context) error {
+	jobs, err := h.EtlRepo.GetJobs(c.Request().Context())
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to get ETL jobs",
+			Data:       nil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy danh sách ETL job thành công",
+		Data:       jobs,
+	})
+}
+
+func (h *EtlHandler) GetJob(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.EtlJobNotFound)
+	}
+
+	job, err := h.EtlRepo.GetJobById(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy thông tin ETL job thành công",
+		Data:       job,
+	})
+}
+
+func (h *EtlHandler) CreateJob(c echo.Context) error {
+	var job model.EtlJob
+	if err := c.Bind(&job); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+
+	if fieldErrs := h.validateJob(job); len(fieldErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       fieldErrs,
+		})
+	}
+
+	job, err := h.EtlRepo.CreateJob(c.Request().Context(), job)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+	h.reloadSchedule(c)
+
+	return c.JSON(http.StatusCreated, model.ResponseAsset{
+		StatusCode: http.StatusCreated,
+		Message:    "Tạo ETL job thành công",
+		Data:       job,
+	})
+}
+
+func (h *EtlHandler) UpdateJob(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.EtlJobNotFound)
+	}
+
+	var job model.EtlJob
+	if err := c.Bind(&job); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+
+	if fieldErrs := h.validateJob(job); len(fieldErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       fieldErrs,
+		})
+	}
+
+	if err := h.EtlRepo.UpdateJob(c.Request().Context(), id, job); err != nil {
+		return h.errorResponse(c, err)
+	}
+	job.Id = id
+	h.reloadSchedule(c)
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Cập nhật ETL job thành công",
+		Data:       job,
+	})
+}
+
+func (h *EtlHandler) DeleteJob(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.EtlJobNotFound)
+	}
+
+	if err := h.EtlRepo.DeleteJob(c.Request().Context(), id); err != nil {
+		return h.errorResponse(c, err)
+	}
+	h.reloadSchedule(c)
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Xóa ETL job thành công",
+		Data:       nil,
+	})
+}
+
+// RunJob chạy job trong nền và trả về 202 cùng lần chạy, theo dõi kết quả qua /etl/runs/:id
+func (h *EtlHandler) RunJob(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.EtlJobNotFound)
+	}
+
+	job, err := h.EtlRepo.GetJobById(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	run, err := h.Runner.Start(c.Request().Context(), *job, getActor(c))
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, model.ResponseAsset{
+		StatusCode: http.StatusAccepted,
+		Message:    "Đã bắt đầu chạy ETL job",
+		Data:       run,
+	})
+}
+
+func (h *EtlHandler) GetRunsByJob(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.EtlJobNotFound)
+	}
+
+	runs, err := h.EtlRepo.GetRunsByJob(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy lịch sử chạy ETL job thành công",
+		Data:       runs,
+	})
+}
+
+func (h *EtlHandler) GetRun(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.EtlRunNotFound)
+	}
+
+	run, err := h.EtlRepo.GetRunById(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy thông tin ETL run thành công",
+		Data:       run,
+	})
+}
+
+// reloadSchedule nạp lại lịch sau khi job thay đổi, lỗi chỉ được ghi log vì job đã được lưu
+func (h *EtlHandler) reloadSchedule(c echo.Context) {
+	if h.Scheduler == nil {
+		return
+	}
+	if err := h.Scheduler.Reload(c.Request().Context()); err != nil {
+		log.Errorf("failed to reload ETL schedule: %v", err)
+	}
+}
+
+func (h *EtlHandler) errorResponse(c echo.Context, err error) error {
+	switch err {
+	case errors.EtlJobNotFound, errors.EtlRunNotFound:
+		return c.JSON(http.StatusNotFound, model.ResponseAsset{
+			StatusCode: http.StatusNotFound,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	case errors.EtlJobConflict, errors.EtlJobRunning:
+		return c.JSON(http.StatusConflict, model.ResponseAsset{
+			StatusCode: http.StatusConflict,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	}
+
+	log.Error(err.Error())
+	return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+		StatusCode: http.StatusInternalServerError,
+		Message:    "Failed to process ETL request",
+		Data:       nil,
+	})
+}
+
+func (h *EtlHandler) validateJob(job model.EtlJob) []model.FieldError {
+	var errs []model.FieldError
+
+	validate := validator.New()
+	if err := validate.Struct(job); err != nil {
+		if verrs, ok := err.(validator.ValidationErrors); ok {
+			for _, e := range verrs {
+				errs = append(errs, model.FieldError{Field: e.Field(), Message: e.Tag()})
+			}
+		}
+	}
+
+	if job.Schedule != "" {
+		if err := etl.ParseSchedule(job.Schedule); err != nil {
+			errs = append(errs, model.FieldError{Field: "schedule", Message: err.Error()})
+		}
+	}
+	if job.SourceType != "" {
+		source, ok := h.Runner.Sources[job.SourceType]
+		if !ok {
+			errs = append(errs, model.FieldError{Field: "source_type", Message: "unknown source type " + job.SourceType})
+		} else {
+			errs = append(errs, source.Validate(job.Config)...)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/dhcp"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/importer"
+	"github.com/sllpklls/template-backend-go/mapping"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/zonefile"
+)
+
+// Số dòng mặc định và tối đa trả về khi preview mapping
+const (
+	defaultPreviewLimit = 20
+	maxPreviewLimit     = 500
+)
+
+type MappingHandler struct {
+	Store *mapping.Store
+}
+
+func (h *MappingHandler) GetMappings(c echo.Context) error {
+	mappings, err := h.Store.List()
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to get mappings",
+			Data:       nil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy danh sách mapping thành công",
+		Data:       mappings,
+	})
+}
+
+func (h *MappingHandler) GetMapping(c echo.Context) error {
+	m, err := h.Store.Get(c.Param("id"))
+	if err != nil {
+		return mappingErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy thông tin mapping thành công",
+		Data:       m,
+	})
+}
+
+// PreviewMapping áp mapping cho file mẫu (multipart field "file") và trả về giá trị NetworkAsset của
+// limit dòng đầu (mặc định 20), không ghi gì vào DB. format=csv (mặc định), zone (kèm origin),
+// dhcpd, kea hoặc nmap.
+func (h *MappingHandler) PreviewMapping(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "csv"
+	}
+	limit := defaultPreviewLimit
+
+	var paramErrs []model.FieldError
+	switch format {
+	case "csv", "zone", dhcp.FormatDhcpd, dhcp.FormatKea, "nmap":
+	default:
+		paramErrs = append(paramErrs, model.FieldError{Field: "format", Message: "must be csv, zone, dhcpd, kea or nmap"})
+	}
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 || l > maxPreviewLimit {
+			paramErrs = append(paramErrs, model.FieldError{Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", maxPreviewLimit)})
+		}
+		limit = l
+	}
+	if len(paramErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       paramErrs,
+		})
+	}
+
+	m, err := h.Store.Get(c.Param("id"))
+	if err != nil {
+		return mappingErrorResponse(c, err)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Sample file is required",
+			Data:       nil,
+		})
+	}
+	if fileHeader.Size > maxImportSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, model.ResponseAsset{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Message:    "Sample file is too large",
+			Data:       nil,
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Failed to read sample file",
+			Data:       nil,
+		})
+	}
+	defer file.Close()
+
+	rows, overlay, fileErrs := previewSource(format, file, c.QueryParam("origin"))
+	if len(fileErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid sample file",
+			Data:       fileErrs,
+		})
+	}
+
+	preview := model.MappingPreview{Mapping: m.Id, Format: format, Total: len(rows), Rows: []model.MappingPreviewRow{}}
+	for _, row := range rows {
+		values, errs := m.Apply(row.Values)
+		if overlay {
+			for k, v := range row.Values {
+				if _, ok := values[k]; !ok {
+					values[k] = v
+				}
+			}
+		}
+		if len(errs) > 0 {
+			preview.Failed++
+		}
+		if len(preview.Rows) < limit {
+			preview.Rows = append(preview.Rows, model.MappingPreviewRow{Row: row.Row, Source: row.Values, Values: values, Errors: errs})
+		}
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Preview mapping thành công",
+		Data:       preview,
+	})
+}
+
+// previewSource đọc file mẫu thành các bản ghi nguồn giống như khi import với mapping, overlay=true nếu
+// kết quả mapping ghi đè lên bản ghi nguồn (zone, dhcp, nmap)
+func previewSource(format string, r io.Reader, origin string) ([]model.ImportRow, bool, []model.FieldError) {
+	switch format {
+	case "zone":
+		rows, err := importer.ParseZone(r, origin)
+		if err != nil {
+			var parseErr *zonefile.ParseError
+			if stderrors.As(err, &parseErr) {
+				return nil, true, []model.FieldError{{Field: fmt.Sprintf("line %d", parseErr.Line), Message: parseErr.Message}}
+			}
+			return nil, true, []model.FieldError{{Field: "file", Message: err.Error()}}
+		}
+		return rows, true, nil
+	case dhcp.FormatDhcpd, dhcp.FormatKea:
+		leases, err := dhcp.Parse(format, r)
+		if err != nil {
+			return nil, true, []model.FieldError{{Field: "file", Message: err.Error()}}
+		}
+		return dhcp.ImportRows(leases, time.Now()), true, nil
+	case "nmap":
+		hosts, err := importer.ParseNmapXML(r)
+		if err != nil {
+			return nil, true, []model.FieldError{{Field: "file", Message: err.Error()}}
+		}
+		rows := make([]model.ImportRow, 0, len(hosts))
+		for _, host := range hosts {
+			rows = append(rows, model.ImportRow{Row: host.Row, Values: mapping.HostRecord(host)})
+		}
+		return rows, true, nil
+	}
+
+	rows, headerErrs, err := importer.ParseSourceCSV(r)
+	if err != nil {
+		return nil, false, []model.FieldError{{Field: "file", Message: err.Error()}}
+	}
+	return rows, false, headerErrs
+}
+
+// importMapping đọc mapping theo tham số mapping của các API import, nil nếu không có tham số
+func (h *NetworkAssetHandler) importMapping(c echo.Context) (*mapping.Mapping, error) {
+	id := c.QueryParam("mapping")
+	if id == "" {
+		return nil, nil
+	}
+	if h.Mappings == nil {
+		return nil, errors.MappingNotFound
+	}
+	return h.Mappings.Get(id)
+}
+
+// mappingFailedResponse: có dòng không áp được mapping, import không ghi gì
+func mappingFailedResponse(c echo.Context, opts model.ImportOptions, failed []model.ImportRowResult) error {
+	report := &model.ImportReport{Mode: opts.Mode, DryRun: opts.DryRun}
+	addFailedRows(report, failed)
+	return c.JSON(http.StatusUnprocessableEntity, model.ResponseAsset{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "Mapping failed, no rows were written",
+		Data:       report,
+	})
+}
+
+// addFailedRows thêm dòng lỗi mapping vào report của dry run
+func addFailedRows(report *model.ImportReport, failed []model.ImportRowResult) {
+	for _, result := range failed {
+		report.Add(result)
+	}
+}
+
+func mappingErrorResponse(c echo.Context, err error) error {
+	var mappingErr *mapping.Error
+	switch {
+	case err == errors.MappingNotFound:
+		return c.JSON(http.StatusNotFound, model.ResponseAsset{
+			StatusCode: http.StatusNotFound,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	case stderrors.As(err, &mappingErr):
+		return c.JSON(http.StatusUnprocessableEntity, model.ResponseAsset{
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    errors.MappingInvalid.Error(),
+			Data:       mappingErr.Errors,
+		})
+	}
+
+	log.Error(err.Error())
+	return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+		StatusCode: http.StatusInternalServerError,
+		Message:    "Failed to read mapping",
+		Data:       nil,
+	})
+}
@@ -1,18 +1,35 @@
 package handler
 
 import (
+	stderrors "errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	validator "github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/export"
+	"github.com/sllpklls/template-backend-go/importer"
+	"github.com/sllpklls/template-backend-go/mapping"
 	"github.com/sllpklls/template-backend-go/model"
 	"github.com/sllpklls/template-backend-go/repository"
 )
 
+// Kích thước tối đa của file import
+const maxImportSize = 10 << 20
+
+// Số dòng export giữa hai lần flush response
+const exportFlushEvery = 500
+
 type NetworkAssetHandler struct {
 	NetworkAssetRepo repository.NetworkAssetRepo
+	// Số ngày tối thiểu kể từ khi MarkAsDeleted trước khi asset được purge
+	PurgeRetentionDays int
+	// Mappings chứa các file mapping dùng với tham số mapping của API import
+	Mappings *mapping.Store
 }
 
 func NewNetworkAssetHandler(networkAssetRepo repository.NetworkAssetRepo) *NetworkAssetHandler {
@@ -41,8 +58,10 @@ func (h *NetworkAssetHandler) GetAllNetworkAssets(c echo.Context) error {
 		}
 	}
 
+	includeDeleted, _ := strconv.ParseBool(c.QueryParam("include_deleted"))
+
 	// Get assets
-	assets, err := h.NetworkAssetRepo.GetAllNetworkAssets(c.Request().Context(), page, limit)
+	assets, err := h.NetworkAssetRepo.GetAllNetworkAssets(c.Request().Context(), page, limit, includeDeleted)
 	if err != nil {
 		log.Error(err.Error())
 		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
@@ -53,7 +72,7 @@ func (h *NetworkAssetHandler) GetAllNetworkAssets(c echo.Context) error {
 	}
 
 	// Get total count
-	total, err := h.NetworkAssetRepo.GetTotalNetworkAssets(c.Request().Context())
+	total, err := h.NetworkAssetRepo.GetTotalNetworkAssets(c.Request().Context(), includeDeleted)
 	if err != nil {
 		log.Error(err.Error())
 		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
@@ -84,7 +103,25 @@ func (h *NetworkAssetHandler) GetNetworkAssetByName(c echo.Context) error {
 		})
 	}
 
-	asset, err := h.NetworkAssetRepo.GetNetworkAssetByName(c.Request().Context(), name)
+	includeDeleted, _ := strconv.ParseBool(c.QueryParam("include_deleted"))
+
+	var asset *model.NetworkAsset
+	var asOf time.Time
+	var err error
+	if asOfStr := c.QueryParam("as_of"); asOfStr != "" {
+		var parseErr error
+		asOf, parseErr = time.Parse(time.RFC3339, asOfStr)
+		if parseErr != nil {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "as_of must be an RFC3339 timestamp",
+				Data:       nil,
+			})
+		}
+		asset, err = h.NetworkAssetRepo.GetNetworkAssetByNameAsOf(c.Request().Context(), name, asOf, includeDeleted)
+	} else {
+		asset, err = h.NetworkAssetRepo.GetNetworkAssetByName(c.Request().Context(), name, includeDeleted)
+	}
 	if err != nil {
 		log.Error(err.Error())
 		return c.JSON(http.StatusNotFound, model.ResponseAsset{
@@ -94,10 +131,21 @@ func (h *NetworkAssetHandler) GetNetworkAssetByName(c echo.Context) error {
 		})
 	}
 
+	// Kèm các địa chỉ IPv4/IPv6 khác của cùng SystemName
+	dualStack, err := h.NetworkAssetRepo.GetDualStackAddresses(c.Request().Context(), asset, asOf)
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to get dual-stack addresses",
+			Data:       nil,
+		})
+	}
+
 	return c.JSON(http.StatusOK, model.ResponseAsset{
 		StatusCode: http.StatusOK,
 		Message:    "Lấy thông tin network asset thành công",
-		Data:       asset,
+		Data:       model.NetworkAssetDetail{NetworkAsset: *asset, DualStack: dualStack},
 	})
 }
 
@@ -131,8 +179,10 @@ func (h *NetworkAssetHandler) SearchByDNSHostName(c echo.Context) error {
 		}
 	}
 
+	includeDeleted, _ := strconv.ParseBool(c.QueryParam("include_deleted"))
+
 	// Get assets by DNS hostname
-	assets, err := h.NetworkAssetRepo.GetNetworkAssetsByDNSHostName(c.Request().Context(), dnsHostName, page, limit)
+	assets, err := h.NetworkAssetRepo.GetNetworkAssetsByDNSHostName(c.Request().Context(), dnsHostName, page, limit, includeDeleted)
 	if err != nil {
 		log.Error(err.Error())
 		return c.JSON(http.StatusInternalServerError, model.Response{
@@ -143,7 +193,7 @@ func (h *NetworkAssetHandler) SearchByDNSHostName(c echo.Context) error {
 	}
 
 	// Get total count
-	total, err := h.NetworkAssetRepo.GetTotalNetworkAssetsByDNSHostName(c.Request().Context(), dnsHostName)
+	total, err := h.NetworkAssetRepo.GetTotalNetworkAssetsByDNSHostName(c.Request().Context(), dnsHostName, includeDeleted)
 	if err != nil {
 		log.Error(err.Error())
 		return c.JSON(http.StatusInternalServerError, model.Response{
@@ -211,6 +261,14 @@ func (h *NetworkAssetHandler) SearchNetworkAssets(c echo.Context) error {
 		})
 	}
 
+	if fieldErrs := filter.Validate(); len(fieldErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       fieldErrs,
+		})
+	}
+
 	// Set default values
 	if filter.Page <= 0 {
 		filter.Page = 1
@@ -277,17 +335,31 @@ func (h *NetworkAssetHandler) CreateNetworkAsset(c echo.Context) error {
 		})
 	}
 
-	// Validate required fields
-	if asset.Name == "" || asset.Address == "" {
+	// Validate và chuẩn hóa address/subnet mask/address type
+	if fieldErrs := asset.Validate(); len(fieldErrs) > 0 {
 		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
 			StatusCode: http.StatusBadRequest,
-			Message:    "Name and Address are required",
-			Data:       nil,
+			Message:    "Validation failed",
+			Data:       fieldErrs,
 		})
 	}
 
-	if err := h.NetworkAssetRepo.CreateNetworkAsset(c.Request().Context(), asset); err != nil {
+	if err := h.NetworkAssetRepo.CreateNetworkAsset(c.Request().Context(), asset, getActor(c), allowDuplicateAddress(c)); err != nil {
 		log.Error(err.Error())
+		if stderrors.Is(err, errors.AddressConflict) || stderrors.Is(err, errors.NetworkAssetConflict) {
+			return c.JSON(http.StatusConflict, model.ResponseAsset{
+				StatusCode: http.StatusConflict,
+				Message:    err.Error(),
+				Data:       nil,
+			})
+		}
+		if err == errors.VrfNotFound || err == errors.VlanNotFound || err == errors.VlanVrfMismatch {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    err.Error(),
+				Data:       nil,
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
 			StatusCode: http.StatusInternalServerError,
 			Message:    "Failed to create network asset",
@@ -315,8 +387,32 @@ func (h *NetworkAssetHandler) UpdateNetworkAsset(c echo.Context) error {
 		})
 	}
 
-	if err := h.NetworkAssetRepo.UpdateNetworkAsset(c.Request().Context(), name, asset); err != nil {
+	// Update không đổi tên asset
+	asset.Name = name
+	if fieldErrs := asset.Validate(); len(fieldErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       fieldErrs,
+		})
+	}
+
+	if err := h.NetworkAssetRepo.UpdateNetworkAsset(c.Request().Context(), name, asset, getActor(c), allowDuplicateAddress(c)); err != nil {
 		log.Error(err.Error())
+		if stderrors.Is(err, errors.AddressConflict) {
+			return c.JSON(http.StatusConflict, model.ResponseAsset{
+				StatusCode: http.StatusConflict,
+				Message:    err.Error(),
+				Data:       nil,
+			})
+		}
+		if err == errors.VrfNotFound || err == errors.VlanNotFound || err == errors.VlanVrfMismatch {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    err.Error(),
+				Data:       nil,
+			})
+		}
 		if err.Error() == "network asset not found" {
 			return c.JSON(http.StatusNotFound, model.ResponseAsset{
 				StatusCode: http.StatusNotFound,
@@ -338,10 +434,19 @@ func (h *NetworkAssetHandler) UpdateNetworkAsset(c echo.Context) error {
 	})
 }
 
+// allowDuplicateAddress đọc query param allow_duplicate_address: bỏ qua kiểm tra địa chỉ trùng
+// với asset khác trong cùng dataset (ví dụ VIP, anycast)
+func allowDuplicateAddress(c echo.Context) bool {
+	allow, _ := strconv.ParseBool(c.QueryParam("allow_duplicate_address"))
+	return allow
+}
+
 func (h *NetworkAssetHandler) DeleteNetworkAsset(c echo.Context) error {
 	name := c.Param("name")
+	// Không có body: số phiếu thay đổi để ghi lịch sử lấy từ query param
+	requestId := c.QueryParam("request_id")
 
-	if err := h.NetworkAssetRepo.DeleteNetworkAsset(c.Request().Context(), name); err != nil {
+	if err := h.NetworkAssetRepo.DeleteNetworkAsset(c.Request().Context(), name, getActor(c), requestId); err != nil {
 		log.Error(err.Error())
 		if err.Error() == "network asset not found" {
 			return c.JSON(http.StatusNotFound, model.ResponseAsset{
@@ -363,3 +468,362 @@ func (h *NetworkAssetHandler) DeleteNetworkAsset(c echo.Context) error {
 		Data:       nil,
 	})
 }
+
+func (h *NetworkAssetHandler) RestoreNetworkAsset(c echo.Context) error {
+	name := c.Param("name")
+	requestId := c.QueryParam("request_id")
+
+	if err := h.NetworkAssetRepo.RestoreNetworkAsset(c.Request().Context(), name, getActor(c), requestId, allowDuplicateAddress(c)); err != nil {
+		log.Error(err.Error())
+		if stderrors.Is(err, errors.AddressConflict) {
+			return c.JSON(http.StatusConflict, model.ResponseAsset{
+				StatusCode: http.StatusConflict,
+				Message:    err.Error(),
+				Data:       nil,
+			})
+		}
+		if err.Error() == "network asset not found" {
+			return c.JSON(http.StatusNotFound, model.ResponseAsset{
+				StatusCode: http.StatusNotFound,
+				Message:    "Deleted network asset not found",
+				Data:       nil,
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to restore network asset",
+			Data:       nil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Khôi phục network asset thành công",
+		Data:       nil,
+	})
+}
+
+// PurgeNetworkAssets xóa hẳn các asset đã MarkAsDeleted quá PurgeRetentionDays ngày (chỉ ADMIN).
+// Có thể truyền retention_days lớn hơn cấu hình để purge ít hơn.
+func (h *NetworkAssetHandler) PurgeNetworkAssets(c echo.Context) error {
+	retentionDays := h.PurgeRetentionDays
+	if daysStr := c.QueryParam("retention_days"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < h.PurgeRetentionDays {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "retention_days must be an integer >= " + strconv.Itoa(h.PurgeRetentionDays),
+				Data:       nil,
+			})
+		}
+		retentionDays = days
+	}
+
+	deletedBefore := time.Now().AddDate(0, 0, -retentionDays)
+	names, err := h.NetworkAssetRepo.PurgeNetworkAssets(c.Request().Context(), deletedBefore, getActor(c))
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to purge network assets",
+			Data:       nil,
+		})
+	}
+
+	log.Infof("user %s purged %d network assets deleted before %s", getActor(c), len(names), deletedBefore.Format(time.RFC3339))
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Purge network assets thành công",
+		Data:       names,
+	})
+}
+
+// GetNetworkAssetHistory trả về lịch sử thay đổi từng field của asset, mới nhất trước.
+// Lịch sử vẫn còn sau khi asset bị xóa hoặc purge.
+func (h *NetworkAssetHandler) GetNetworkAssetHistory(c echo.Context) error {
+	name := c.Param("name")
+
+	page := 1
+	limit := 10
+	if p, err := strconv.Atoi(c.QueryParam("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	total, err := h.NetworkAssetRepo.GetTotalNetworkAssetHistory(c.Request().Context(), name)
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to get total count",
+			Data:       nil,
+		})
+	}
+	if total == 0 {
+		return c.JSON(http.StatusNotFound, model.ResponseAsset{
+			StatusCode: http.StatusNotFound,
+			Message:    "Network asset history not found",
+			Data:       nil,
+		})
+	}
+
+	history, err := h.NetworkAssetRepo.GetNetworkAssetHistory(c.Request().Context(), name, page, limit)
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to get network asset history",
+			Data:       nil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ListResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy lịch sử network asset thành công",
+		Data:       history,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+	})
+}
+
+// ImportNetworkAssets nhận file CSV (multipart field "file") với header là tên field của NetworkAsset,
+// hoặc tên cột bất kỳ nếu có mapping=<id> (file mapping YAML chuyển cột nguồn thành field).
+// mode: insert (mặc định), upsert, replace-dataset; dry_run=true chỉ trả về report, không ghi.
+func (h *NetworkAssetHandler) ImportNetworkAssets(c echo.Context) error {
+	opts, paramErrs := importOptions(c, model.ImportInsert)
+	if len(paramErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       paramErrs,
+		})
+	}
+
+	m, err := h.importMapping(c)
+	if err != nil {
+		return mappingErrorResponse(c, err)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "CSV file is required",
+			Data:       nil,
+		})
+	}
+	if fileHeader.Size > maxImportSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, model.ResponseAsset{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Message:    "CSV file is too large",
+			Data:       nil,
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Failed to read CSV file",
+			Data:       nil,
+		})
+	}
+	defer file.Close()
+
+	parse := importer.ParseNetworkAssetCSV
+	if m != nil {
+		parse = importer.ParseSourceCSV
+	}
+	rows, headerErrs, err := parse(file)
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid CSV format",
+			Data:       nil,
+		})
+	}
+	if len(headerErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid CSV header",
+			Data:       headerErrs,
+		})
+	}
+
+	var mappingFailed []model.ImportRowResult
+	if m != nil {
+		rows, mappingFailed = m.ApplyRows(rows, false)
+		if len(mappingFailed) > 0 && !opts.DryRun {
+			return mappingFailedResponse(c, opts, mappingFailed)
+		}
+	}
+
+	report, err := h.NetworkAssetRepo.ImportNetworkAssets(c.Request().Context(), rows, opts, getActor(c))
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to import network assets",
+			Data:       nil,
+		})
+	}
+	addFailedRows(report, mappingFailed)
+
+	if report.Failed > 0 && !opts.DryRun {
+		return c.JSON(http.StatusUnprocessableEntity, model.ResponseAsset{
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    "Import failed, no rows were written",
+			Data:       report,
+		})
+	}
+
+	message := "Import network assets thành công"
+	if opts.DryRun {
+		message = "Dry run import network assets thành công"
+	}
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    message,
+		Data:       report,
+	})
+}
+
+// importOptions đọc các tham số chung của mọi loại import: mode, dataset_id, request_id, dry_run
+// và allow_duplicate_address
+func importOptions(c echo.Context, defaultMode string) (model.ImportOptions, []model.FieldError) {
+	opts := model.ImportOptions{
+		Mode:      c.QueryParam("mode"),
+		RequestId: c.QueryParam("request_id"),
+	}
+	if opts.Mode == "" {
+		opts.Mode = defaultMode
+	}
+	opts.DryRun, _ = strconv.ParseBool(c.QueryParam("dry_run"))
+	opts.AllowDuplicateAddress = allowDuplicateAddress(c)
+
+	var paramErrs []model.FieldError
+	if !model.IsValidImportMode(opts.Mode) {
+		paramErrs = append(paramErrs, model.FieldError{Field: "mode", Message: "must be insert, upsert or replace-dataset"})
+	}
+	if datasetStr := c.QueryParam("dataset_id"); datasetStr != "" {
+		id, err := strconv.Atoi(datasetStr)
+		if err != nil {
+			paramErrs = append(paramErrs, model.FieldError{Field: "dataset_id", Message: "must be an integer"})
+		}
+		opts.DatasetId = id
+	}
+	if opts.Mode == model.ImportReplaceDataset && opts.DatasetId == 0 {
+		paramErrs = append(paramErrs, model.FieldError{Field: "dataset_id", Message: "required for replace-dataset"})
+	}
+	return opts, paramErrs
+}
+
+// ExportNetworkAssets stream toàn bộ asset khớp filter (cùng tham số với /search, không phân trang)
+// ra csv, jsonl hoặc xlsx. Header đã gửi trước khi đọc DB nên lỗi giữa chừng chỉ được ghi log.
+func (h *NetworkAssetHandler) ExportNetworkAssets(c echo.Context) error {
+	formatName := c.QueryParam("format")
+	if formatName == "" {
+		formatName = "csv"
+	}
+	format, ok := export.LookupFormat(formatName)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "format must be csv, jsonl or xlsx",
+			Data:       nil,
+		})
+	}
+
+	var filter model.NetworkAssetFilter
+	if err := c.Bind(&filter); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid query parameters",
+			Data:       nil,
+		})
+	}
+	if fieldErrs := filter.Validate(); len(fieldErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       fieldErrs,
+		})
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, format.ContentType)
+	res.Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="network-assets-%s.%s"`, time.Now().Format("20060102-150405"), format.Extension))
+	res.WriteHeader(http.StatusOK)
+
+	writer, err := format.NewWriter(res)
+	if err != nil {
+		log.Error(err.Error())
+		return nil
+	}
+
+	count := 0
+	err = h.NetworkAssetRepo.StreamNetworkAssetsByFilter(c.Request().Context(), filter, func(asset *model.NetworkAsset) error {
+		if err := writer.Write(asset); err != nil {
+			return err
+		}
+		count++
+		if count%exportFlushEvery == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			res.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		log.Errorf("export network assets failed after %d rows: %s", count, err.Error())
+		return nil
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Error(err.Error())
+		return nil
+	}
+	res.Flush()
+	return nil
+}
+
+// GetIPConflicts liệt kê các địa chỉ đang bị nhiều asset trong cùng dataset sử dụng,
+// nhóm theo địa chỉ kèm các asset liên quan
+func (h *NetworkAssetHandler) GetIPConflicts(c echo.Context) error {
+	datasetId := 0
+	if datasetStr := c.QueryParam("dataset_id"); datasetStr != "" {
+		id, err := strconv.Atoi(datasetStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "dataset_id must be an integer",
+				Data:       nil,
+			})
+		}
+		datasetId = id
+	}
+
+	conflicts, err := h.NetworkAssetRepo.GetIPConflicts(c.Request().Context(), datasetId)
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to get ip conflicts",
+			Data:       nil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy báo cáo trùng địa chỉ IP thành công",
+		Data:       conflicts,
+	})
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/importer"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// ImportNmap nhận output XML của nmap (-oX, multipart field "file") và tạo/cập nhật asset cho các host
+// đang up vào dataset_id (bắt buộc, dataset dành cho discovery). Port mở được lưu theo asset,
+// LastSeen là thời điểm scan thấy host. mode, dry_run, allow_duplicate_address giống import CSV,
+// mode mặc định là upsert. mapping=<id> áp file mapping lên address, dns_host_name, mac_address, address_type
+// của từng host.
+func (h *NetworkAssetHandler) ImportNmap(c echo.Context) error {
+	opts, paramErrs := importOptions(c, model.ImportUpsert)
+	if c.QueryParam("dataset_id") == "" {
+		paramErrs = append(paramErrs, model.FieldError{Field: "dataset_id", Message: "required"})
+	}
+	if len(paramErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       paramErrs,
+		})
+	}
+
+	m, err := h.importMapping(c)
+	if err != nil {
+		return mappingErrorResponse(c, err)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Nmap XML file is required",
+			Data:       nil,
+		})
+	}
+	if fileHeader.Size > maxImportSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, model.ResponseAsset{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Message:    "Nmap XML file is too large",
+			Data:       nil,
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Failed to read nmap XML file",
+			Data:       nil,
+		})
+	}
+	defer file.Close()
+
+	hosts, err := importer.ParseNmapXML(file)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid nmap XML file",
+			Data:       []model.FieldError{{Field: "file", Message: err.Error()}},
+		})
+	}
+
+	var mappingFailed []model.ImportRowResult
+	if m != nil {
+		hosts, mappingFailed = m.ApplyHosts(hosts)
+		if len(mappingFailed) > 0 && !opts.DryRun {
+			return mappingFailedResponse(c, opts, mappingFailed)
+		}
+	}
+
+	report, err := h.NetworkAssetRepo.ImportScan(c.Request().Context(), hosts, opts, getActor(c))
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to import nmap scan",
+			Data:       nil,
+		})
+	}
+	addFailedRows(report, mappingFailed)
+
+	if report.Failed > 0 && !opts.DryRun {
+		return c.JSON(http.StatusUnprocessableEntity, model.ResponseAsset{
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    "Import failed, no rows were written",
+			Data:       report,
+		})
+	}
+
+	message := "Import kết quả nmap thành công"
+	if opts.DryRun {
+		message = "Dry run import kết quả nmap thành công"
+	}
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    message,
+		Data:       report,
+	})
+}
+
+// GetNetworkAssetPorts trả về các port đã phát hiện trên asset
+func (h *NetworkAssetHandler) GetNetworkAssetPorts(c echo.Context) error {
+	name := c.Param("name")
+	if _, err := h.NetworkAssetRepo.GetNetworkAssetByName(c.Request().Context(), name, true); err != nil {
+		return c.JSON(http.StatusNotFound, model.ResponseAsset{
+			StatusCode: http.StatusNotFound,
+			Message:    "Network asset not found",
+			Data:       nil,
+		})
+	}
+
+	ports, err := h.NetworkAssetRepo.GetNetworkAssetPorts(c.Request().Context(), name)
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to get network asset ports",
+			Data:       nil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy danh sách port thành công",
+		Data:       ports,
+	})
+}
@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/reconciliation"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+type ReconciliationHandler struct {
+	ReconciliationRepo repository.ReconciliationRepo
+	Engine             *reconciliation.Engine
+}
+
+func (h *ReconciliationHandler) GetJobs(c echo.Context) error {
+	jobs, err := h.ReconciliationRepo.GetJobs(c.Request().Context())
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to get reconciliation jobs",
+			Data:       nil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy danh sách reconciliation job thành công",
+		Data:       jobs,
+	})
+}
+
+func (h *ReconciliationHandler) GetJob(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.ReconciliationJobNotFound)
+	}
+
+	job, err := h.ReconciliationRepo.GetJobById(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy thông tin reconciliation job thành công",
+		Data:       job,
+	})
+}
+
+func (h *ReconciliationHandler) CreateJob(c echo.Context) error {
+	var job model.ReconciliationJob
+	if err := c.Bind(&job); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+
+	if fieldErrs := validateReconciliationJob(job); len(fieldErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       fieldErrs,
+		})
+	}
+
+	job, err := h.ReconciliationRepo.CreateJob(c.Request().Context(), job)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, model.ResponseAsset{
+		StatusCode: http.StatusCreated,
+		Message:    "Tạo reconciliation job thành công",
+		Data:       job,
+	})
+}
+
+func (h *ReconciliationHandler) UpdateJob(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.ReconciliationJobNotFound)
+	}
+
+	var job model.ReconciliationJob
+	if err := c.Bind(&job); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+
+	if fieldErrs := validateReconciliationJob(job); len(fieldErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       fieldErrs,
+		})
+	}
+
+	if err := h.ReconciliationRepo.UpdateJob(c.Request().Context(), id, job); err != nil {
+		return h.errorResponse(c, err)
+	}
+	job.Id = id
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Cập nhật reconciliation job thành công",
+		Data:       job,
+	})
+}
+
+func (h *ReconciliationHandler) DeleteJob(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.ReconciliationJobNotFound)
+	}
+
+	if err := h.ReconciliationRepo.DeleteJob(c.Request().Context(), id); err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Xóa reconciliation job thành công",
+		Data:       nil,
+	})
+}
+
+func (h *ReconciliationHandler) RunJob(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.ReconciliationJobNotFound)
+	}
+
+	job, err := h.ReconciliationRepo.GetJobById(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	run, err := h.Engine.Run(c.Request().Context(), *job, getActor(c))
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Reconciliation failed",
+			Data:       run,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Reconciliation thành công",
+		Data:       run,
+	})
+}
+
+func (h *ReconciliationHandler) GetRunsByJob(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.ReconciliationJobNotFound)
+	}
+
+	runs, err := h.ReconciliationRepo.GetRunsByJob(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy lịch sử reconciliation thành công",
+		Data:       runs,
+	})
+}
+
+func (h *ReconciliationHandler) GetRun(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.ReconciliationRunNotFound)
+	}
+
+	run, err := h.ReconciliationRepo.GetRunById(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy thông tin reconciliation run thành công",
+		Data:       run,
+	})
+}
+
+func (h *ReconciliationHandler) errorResponse(c echo.Context, err error) error {
+	switch err {
+	case errors.ReconciliationJobNotFound, errors.ReconciliationRunNotFound:
+		return c.JSON(http.StatusNotFound, model.ResponseAsset{
+			StatusCode: http.StatusNotFound,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	case errors.ReconciliationJobConflict:
+		return c.JSON(http.StatusConflict, model.ResponseAsset{
+			StatusCode: http.StatusConflict,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	}
+
+	log.Error(err.Error())
+	return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+		StatusCode: http.StatusInternalServerError,
+		Message:    "Failed to process reconciliation request",
+		Data:       nil,
+	})
+}
+
+func validateReconciliationJob(job model.ReconciliationJob) []model.FieldError {
+	var errs []model.FieldError
+
+	validate := validator.New()
+	if err := validate.Struct(job); err != nil {
+		if verrs, ok := err.(validator.ValidationErrors); ok {
+			for _, e := range verrs {
+				errs = append(errs, model.FieldError{Field: e.Field(), Message: e.Tag()})
+			}
+		}
+	}
+
+	for _, id := range job.SourceDatasets {
+		if id == job.TargetDatasetId {
+			errs = append(errs, model.FieldError{Field: "source_datasets", Message: "must not contain target_dataset_id"})
+		}
+	}
+	for _, rule := range job.IdentificationRules {
+		if len(rule) == 0 {
+			errs = append(errs, model.FieldError{Field: "identification_rules", Message: "rule must not be empty"})
+		}
+		for _, field := range rule {
+			if _, ok := model.LookupNetworkAssetField(field); !ok {
+				errs = append(errs, model.FieldError{Field: "identification_rules", Message: "unknown field " + field})
+			}
+		}
+	}
+	for field := range job.PrecedenceRules {
+		if f, ok := model.LookupNetworkAssetField(field); (!ok || f.Name != field) && field != "*" {
+			errs = append(errs, model.FieldError{Field: "precedence_rules", Message: "unknown field " + field})
+		}
+	}
+
+	return errs
+}
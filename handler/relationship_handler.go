@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/model/req"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+const (
+	defaultImpactDepth = 3
+	maxImpactDepth     = 10
+	// Số đường đi tối đa đọc từ recursive query, vượt quá thì trả về truncated = true
+	maxImpactPaths   = 1000
+	impactQueryLimit = 10 * time.Second
+)
+
+// RelationshipHandler xử lý /{ci}/:name/relationships, class của CI được truyền khi đăng ký route
+type RelationshipHandler struct {
+	RelationshipRepo repository.RelationshipRepo
+}
+
+func (h *RelationshipHandler) GetRelationships(class string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		name := c.Param("name")
+
+		exists, err := h.RelationshipRepo.CIExists(c.Request().Context(), class, name)
+		if err != nil {
+			log.Error(err.Error())
+			return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "Failed to get relationships",
+				Data:       nil,
+			})
+		}
+		if !exists {
+			return c.JSON(http.StatusNotFound, model.ResponseAsset{
+				StatusCode: http.StatusNotFound,
+				Message:    class + " not found",
+				Data:       nil,
+			})
+		}
+
+		relationships, err := h.RelationshipRepo.GetRelationshipsByCI(c.Request().Context(), class, name)
+		if err != nil {
+			log.Error(err.Error())
+			return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "Failed to get relationships",
+				Data:       nil,
+			})
+		}
+
+		return c.JSON(http.StatusOK, model.ResponseAsset{
+			StatusCode: http.StatusOK,
+			Message:    "Lấy danh sách relationship thành công",
+			Data:       relationships,
+		})
+	}
+}
+
+func (h *RelationshipHandler) CreateRelationship(class string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		name := c.Param("name")
+
+		request := req.ReqCreateRelationship{}
+		if err := c.Bind(&request); err != nil {
+			log.Error(err.Error())
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Invalid JSON format",
+				Data:       nil,
+			})
+		}
+
+		validate := validator.New()
+		if err := validate.Struct(request); err != nil {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    err.Error(),
+				Data:       nil,
+			})
+		}
+
+		relType := model.RelationshipType(request.Type)
+		if !relType.IsValid() {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "type must be one of HostedOn, DependsOn, MemberOf, ConnectedTo",
+				Data:       nil,
+			})
+		}
+
+		otherClass := request.OtherClass
+		if otherClass == "" {
+			otherClass = model.NetworkAssetClass
+		}
+
+		rel := model.Relationship{
+			Type:        relType,
+			SourceClass: class,
+			SourceName:  name,
+			TargetClass: otherClass,
+			TargetName:  request.OtherName,
+			Description: request.Description,
+			CreatedBy:   getActor(c),
+		}
+		if request.Direction == "incoming" {
+			rel.SourceClass, rel.TargetClass = rel.TargetClass, rel.SourceClass
+			rel.SourceName, rel.TargetName = rel.TargetName, rel.SourceName
+		}
+
+		if rel.SourceClass == rel.TargetClass && rel.SourceName == rel.TargetName {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "A CI cannot be related to itself",
+				Data:       nil,
+			})
+		}
+
+		for _, ci := range [][2]string{{class, name}, {otherClass, request.OtherName}} {
+			exists, err := h.RelationshipRepo.CIExists(c.Request().Context(), ci[0], ci[1])
+			if err == errors.UnknownCIClass {
+				return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+					StatusCode: http.StatusBadRequest,
+					Message:    "Unknown CI class " + ci[0],
+					Data:       nil,
+				})
+			}
+			if err != nil {
+				log.Error(err.Error())
+				return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+					StatusCode: http.StatusInternalServerError,
+					Message:    "Failed to create relationship",
+					Data:       nil,
+				})
+			}
+			if !exists {
+				return c.JSON(http.StatusNotFound, model.ResponseAsset{
+					StatusCode: http.StatusNotFound,
+					Message:    ci[0] + " " + ci[1] + " not found",
+					Data:       nil,
+				})
+			}
+		}
+
+		rel, err := h.RelationshipRepo.CreateRelationship(c.Request().Context(), rel)
+		if err != nil {
+			if err == errors.RelationshipConflict {
+				return c.JSON(http.StatusConflict, model.ResponseAsset{
+					StatusCode: http.StatusConflict,
+					Message:    err.Error(),
+					Data:       nil,
+				})
+			}
+			log.Error(err.Error())
+			return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "Failed to create relationship",
+				Data:       nil,
+			})
+		}
+
+		return c.JSON(http.StatusCreated, model.ResponseAsset{
+			StatusCode: http.StatusCreated,
+			Message:    "Tạo relationship thành công",
+			Data:       rel,
+		})
+	}
+}
+
+func (h *RelationshipHandler) DeleteRelationship(class string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Invalid relationship id",
+				Data:       nil,
+			})
+		}
+
+		if err := h.RelationshipRepo.DeleteRelationship(c.Request().Context(), class, c.Param("name"), id); err != nil {
+			if err == errors.RelationshipNotFound {
+				return c.JSON(http.StatusNotFound, model.ResponseAsset{
+					StatusCode: http.StatusNotFound,
+					Message:    err.Error(),
+					Data:       nil,
+				})
+			}
+			log.Error(err.Error())
+			return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "Failed to delete relationship",
+				Data:       nil,
+			})
+		}
+
+		return c.JSON(http.StatusOK, model.ResponseAsset{
+			StatusCode: http.StatusOK,
+			Message:    "Xóa relationship thành công",
+			Data:       nil,
+		})
+	}
+}
+
+func (h *RelationshipHandler) GetImpact(class string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		name := c.Param("name")
+
+		direction := c.QueryParam("direction")
+		if direction == "" {
+			direction = model.ImpactDownstream
+		}
+		if direction != model.ImpactDownstream && direction != model.ImpactUpstream {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "direction must be downstream or upstream",
+				Data:       nil,
+			})
+		}
+
+		depth := defaultImpactDepth
+		if depthStr := c.QueryParam("depth"); depthStr != "" {
+			d, err := strconv.Atoi(depthStr)
+			if err != nil || d <= 0 || d > maxImpactDepth {
+				return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+					StatusCode: http.StatusBadRequest,
+					Message:    "depth must be between 1 and " + strconv.Itoa(maxImpactDepth),
+					Data:       nil,
+				})
+			}
+			depth = d
+		}
+
+		exists, err := h.RelationshipRepo.CIExists(c.Request().Context(), class, name)
+		if err != nil {
+			log.Error(err.Error())
+			return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "Failed to analyze impact",
+				Data:       nil,
+			})
+		}
+		if !exists {
+			return c.JSON(http.StatusNotFound, model.ResponseAsset{
+				StatusCode: http.StatusNotFound,
+				Message:    class + " not found",
+				Data:       nil,
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request().Context(), impactQueryLimit)
+		defer cancel()
+
+		result, err := h.RelationshipRepo.GetImpact(ctx, class, name, direction, depth, maxImpactPaths)
+		if err != nil {
+			log.Error(err.Error())
+			return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "Failed to analyze impact",
+				Data:       nil,
+			})
+		}
+
+		return c.JSON(http.StatusOK, model.ResponseAsset{
+			StatusCode: http.StatusOK,
+			Message:    "Phân tích ảnh hưởng thành công",
+			Data:       result,
+		})
+	}
+}
@@ -0,0 +1,387 @@
+package handler
+
+import (
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/ipaddr"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/model/req"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+type SubnetHandler struct {
+	SubnetRepo repository.SubnetRepo
+}
+
+// subnetRef lấy tham số :cidr (id hoặc CIDR). Echo không giải mã %2F trong path param
+// nên CIDR phải được unescape tại đây: /subnets/10.0.0.0%2F24. CIDR thuộc VRF khác global
+// cần thêm query param vrf=<tên VRF>.
+func subnetRef(c echo.Context) string {
+	ref := c.Param("cidr")
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		return unescaped
+	}
+	return ref
+}
+
+func (h *SubnetHandler) GetSubnets(c echo.Context) error {
+	var filter model.SubnetFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid query parameters",
+			Data:       nil,
+		})
+	}
+
+	subnets, err := h.SubnetRepo.GetSubnets(c.Request().Context(), filter)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy danh sách subnet thành công",
+		Data:       subnets,
+	})
+}
+
+func (h *SubnetHandler) GetSubnetTree(c echo.Context) error {
+	var filter model.SubnetFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid query parameters",
+			Data:       nil,
+		})
+	}
+
+	subnets, err := h.SubnetRepo.GetSubnets(c.Request().Context(), filter)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy cây subnet thành công",
+		Data:       model.BuildSubnetTree(subnets),
+	})
+}
+
+func (h *SubnetHandler) GetSubnet(c echo.Context) error {
+	subnet, err := h.SubnetRepo.GetSubnet(c.Request().Context(), subnetRef(c), c.QueryParam("vrf"))
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy thông tin subnet thành công",
+		Data:       subnet,
+	})
+}
+
+func (h *SubnetHandler) CreateSubnet(c echo.Context) error {
+	var subnet model.Subnet
+	if err := c.Bind(&subnet); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+
+	if fieldErrs := validateSubnet(&subnet); len(fieldErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       fieldErrs,
+		})
+	}
+	subnet.LastModifiedBy = getActor(c)
+
+	subnet, err := h.SubnetRepo.CreateSubnet(c.Request().Context(), subnet)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, model.ResponseAsset{
+		StatusCode: http.StatusCreated,
+		Message:    "Tạo subnet thành công",
+		Data:       subnet,
+	})
+}
+
+func (h *SubnetHandler) UpdateSubnet(c echo.Context) error {
+	current, err := h.SubnetRepo.GetSubnet(c.Request().Context(), subnetRef(c), c.QueryParam("vrf"))
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	var subnet model.Subnet
+	if err := c.Bind(&subnet); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+
+	if fieldErrs := validateSubnet(&subnet); len(fieldErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       fieldErrs,
+		})
+	}
+	subnet.LastModifiedBy = getActor(c)
+
+	if err := h.SubnetRepo.UpdateSubnet(c.Request().Context(), current.Id, subnet); err != nil {
+		return h.errorResponse(c, err)
+	}
+	subnet.Id = current.Id
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Cập nhật subnet thành công",
+		Data:       subnet,
+	})
+}
+
+func (h *SubnetHandler) DeleteSubnet(c echo.Context) error {
+	subnet, err := h.SubnetRepo.GetSubnet(c.Request().Context(), subnetRef(c), c.QueryParam("vrf"))
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	if err := h.SubnetRepo.DeleteSubnet(c.Request().Context(), subnet.Id); err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Xóa subnet thành công",
+		Data:       nil,
+	})
+}
+
+func (h *SubnetHandler) CreateReservation(c echo.Context) error {
+	subnet, err := h.SubnetRepo.GetSubnet(c.Request().Context(), subnetRef(c), c.QueryParam("vrf"))
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	var reservation model.SubnetReservation
+	if err := c.Bind(&reservation); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+
+	if fieldErrs := validateReservation(subnet, &reservation); len(fieldErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       fieldErrs,
+		})
+	}
+	reservation.SubnetId = subnet.Id
+	reservation.CreatedBy = getActor(c)
+
+	reservation, err = h.SubnetRepo.CreateReservation(c.Request().Context(), reservation)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, model.ResponseAsset{
+		StatusCode: http.StatusCreated,
+		Message:    "Tạo reservation thành công",
+		Data:       reservation,
+	})
+}
+
+func (h *SubnetHandler) DeleteReservation(c echo.Context) error {
+	subnet, err := h.SubnetRepo.GetSubnet(c.Request().Context(), subnetRef(c), c.QueryParam("vrf"))
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.ReservationNotFound)
+	}
+
+	if err := h.SubnetRepo.DeleteReservation(c.Request().Context(), subnet.Id, id); err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Xóa reservation thành công",
+		Data:       nil,
+	})
+}
+
+// AllocateAddresses cấp địa chỉ trống tiếp theo trong subnet (bỏ qua network, broadcast, gateway,
+// reservation và địa chỉ đã có asset) và tạo network asset cho địa chỉ đó
+func (h *SubnetHandler) AllocateAddresses(c echo.Context) error {
+	subnet, err := h.SubnetRepo.GetSubnet(c.Request().Context(), subnetRef(c), c.QueryParam("vrf"))
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	var body req.ReqAllocateAddress
+	if err := c.Bind(&body); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(body); err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	}
+	if body.Count == 0 {
+		body.Count = 1
+	}
+
+	template := model.NetworkAsset{
+		Name:             body.Name,
+		SystemName:       body.SystemName,
+		ShortDescription: body.ShortDescription,
+		Description:      body.Description,
+		DNSHostName:      body.DNSHostName,
+		ProtocolType:     body.ProtocolType,
+		DatasetId:        body.DatasetId,
+		VlanId:           body.VlanId,
+		RequestId:        body.RequestId,
+		LastModifiedBy:   getActor(c),
+	}
+	if template.DatasetId == 0 {
+		template.DatasetId = subnet.DatasetId
+	}
+
+	assets, err := h.SubnetRepo.AllocateAddresses(c.Request().Context(), subnet.Id, body.Address, body.Count, template, getActor(c))
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, model.ResponseAsset{
+		StatusCode: http.StatusCreated,
+		Message:    "Cấp phát địa chỉ thành công",
+		Data:       assets,
+	})
+}
+
+func (h *SubnetHandler) errorResponse(c echo.Context, err error) error {
+	switch err {
+	case errors.SubnetNotFound, errors.ReservationNotFound:
+		return c.JSON(http.StatusNotFound, model.ResponseAsset{
+			StatusCode: http.StatusNotFound,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	case errors.VrfNotFound, errors.VlanNotFound, errors.VlanVrfMismatch:
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	case errors.SubnetConflict, errors.ReservationConflict, errors.NetworkAssetConflict,
+		errors.AddressUnavailable, errors.SubnetFull:
+		return c.JSON(http.StatusConflict, model.ResponseAsset{
+			StatusCode: http.StatusConflict,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	}
+
+	log.Error(err.Error())
+	return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+		StatusCode: http.StatusInternalServerError,
+		Message:    "Failed to process subnet request",
+		Data:       nil,
+	})
+}
+
+// validateSubnet kiểm tra và chuẩn hóa cidr, gateway
+func validateSubnet(subnet *model.Subnet) []model.FieldError {
+	var errs []model.FieldError
+
+	prefix, err := ipaddr.ParseCIDR(subnet.Cidr)
+	if err != nil {
+		return append(errs, model.FieldError{Field: "cidr", Message: err.Error()})
+	}
+	subnet.Cidr = prefix.String()
+
+	if subnet.Gateway != "" {
+		gateway, err := netip.ParseAddr(subnet.Gateway)
+		if err != nil {
+			errs = append(errs, model.FieldError{Field: "gateway", Message: "invalid IP address"})
+		} else if !hostInPrefix(prefix, gateway) {
+			errs = append(errs, model.FieldError{Field: "gateway", Message: "must be a host address inside " + subnet.Cidr})
+		} else {
+			subnet.Gateway = gateway.String()
+		}
+	}
+
+	return errs
+}
+
+func validateReservation(subnet *model.Subnet, reservation *model.SubnetReservation) []model.FieldError {
+	var errs []model.FieldError
+
+	prefix, err := netip.ParsePrefix(subnet.Cidr)
+	if err != nil {
+		return append(errs, model.FieldError{Field: "cidr", Message: err.Error()})
+	}
+
+	start, err := netip.ParseAddr(reservation.StartAddress)
+	if err != nil || !hostInPrefix(prefix, start) {
+		errs = append(errs, model.FieldError{Field: "start_address", Message: "must be a host address inside " + subnet.Cidr})
+	}
+	end, err := netip.ParseAddr(reservation.EndAddress)
+	if err != nil || !hostInPrefix(prefix, end) {
+		errs = append(errs, model.FieldError{Field: "end_address", Message: "must be a host address inside " + subnet.Cidr})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if end.Less(start) {
+		return append(errs, model.FieldError{Field: "end_address", Message: "must not be before start_address"})
+	}
+	reservation.StartAddress = start.String()
+	reservation.EndAddress = end.String()
+	return nil
+}
+
+// hostInPrefix: địa chỉ nằm trong prefix và không phải network/broadcast của IPv4
+func hostInPrefix(prefix netip.Prefix, addr netip.Addr) bool {
+	if !prefix.Contains(addr) {
+		return false
+	}
+	if ipaddr.HasNetworkBroadcast(prefix) {
+		return addr != prefix.Addr() && addr != ipaddr.LastAddr(prefix)
+	}
+	return true
+}
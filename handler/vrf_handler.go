@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+type VrfHandler struct {
+	VrfRepo repository.VrfRepo
+}
+
+func (h *VrfHandler) GetVrfs(c echo.Context) error {
+	vrfs, err := h.VrfRepo.GetVrfs(c.Request().Context())
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy danh sách VRF thành công",
+		Data:       vrfs,
+	})
+}
+
+func (h *VrfHandler) GetVrf(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.VrfNotFound)
+	}
+
+	vrf, err := h.VrfRepo.GetVrf(c.Request().Context(), id)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy thông tin VRF thành công",
+		Data:       vrf,
+	})
+}
+
+func (h *VrfHandler) CreateVrf(c echo.Context) error {
+	var vrf model.Vrf
+	if err := c.Bind(&vrf); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+	if msg := validateVrf(&vrf); msg != "" {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    msg,
+			Data:       nil,
+		})
+	}
+	vrf.LastModifiedBy = getActor(c)
+
+	vrf, err := h.VrfRepo.CreateVrf(c.Request().Context(), vrf)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, model.ResponseAsset{
+		StatusCode: http.StatusCreated,
+		Message:    "Tạo VRF thành công",
+		Data:       vrf,
+	})
+}
+
+func (h *VrfHandler) UpdateVrf(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.VrfNotFound)
+	}
+
+	var vrf model.Vrf
+	if err := c.Bind(&vrf); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+	if msg := validateVrf(&vrf); msg != "" {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    msg,
+			Data:       nil,
+		})
+	}
+	vrf.LastModifiedBy = getActor(c)
+
+	if err := h.VrfRepo.UpdateVrf(c.Request().Context(), id, vrf); err != nil {
+		return h.errorResponse(c, err)
+	}
+	vrf.Id = id
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Cập nhật VRF thành công",
+		Data:       vrf,
+	})
+}
+
+func (h *VrfHandler) DeleteVrf(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, errors.VrfNotFound)
+	}
+
+	if err := h.VrfRepo.DeleteVrf(c.Request().Context(), id); err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Xóa VRF thành công",
+		Data:       nil,
+	})
+}
+
+// validateVrf trả về thông báo lỗi, rỗng nếu hợp lệ
+func validateVrf(vrf *model.Vrf) string {
+	vrf.Name = strings.TrimSpace(vrf.Name)
+	if err := validator.New().Struct(vrf); err != nil {
+		return err.Error()
+	}
+	// "global" dùng trong filter để chỉ bảng định tuyến global
+	if strings.EqualFold(vrf.Name, model.GlobalVrf) {
+		return "VRF name " + model.GlobalVrf + " is reserved"
+	}
+	return ""
+}
+
+func (h *VrfHandler) GetVlans(c echo.Context) error {
+	vlans, err := h.VrfRepo.GetVlans(c.Request().Context(), c.QueryParam("vrf"))
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy danh sách VLAN thành công",
+		Data:       vlans,
+	})
+}
+
+func (h *VrfHandler) GetVlan(c echo.Context) error {
+	vlanId, err := strconv.Atoi(c.Param("vlan_id"))
+	if err != nil {
+		return h.errorResponse(c, errors.VlanNotFound)
+	}
+
+	vlan, err := h.VrfRepo.GetVlan(c.Request().Context(), vlanId)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Lấy thông tin VLAN thành công",
+		Data:       vlan,
+	})
+}
+
+func (h *VrfHandler) CreateVlan(c echo.Context) error {
+	var vlan model.Vlan
+	if err := c.Bind(&vlan); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+	if err := validator.New().Struct(vlan); err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	}
+	vlan.LastModifiedBy = getActor(c)
+
+	vlan, err := h.VrfRepo.CreateVlan(c.Request().Context(), vlan)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, model.ResponseAsset{
+		StatusCode: http.StatusCreated,
+		Message:    "Tạo VLAN thành công",
+		Data:       vlan,
+	})
+}
+
+func (h *VrfHandler) UpdateVlan(c echo.Context) error {
+	vlanId, err := strconv.Atoi(c.Param("vlan_id"))
+	if err != nil {
+		return h.errorResponse(c, errors.VlanNotFound)
+	}
+
+	var vlan model.Vlan
+	if err := c.Bind(&vlan); err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid JSON format",
+			Data:       nil,
+		})
+	}
+	// VLAN ID là khóa, không đổi được
+	vlan.VlanId = vlanId
+	if err := validator.New().Struct(vlan); err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	}
+	vlan.LastModifiedBy = getActor(c)
+
+	if err := h.VrfRepo.UpdateVlan(c.Request().Context(), vlanId, vlan); err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Cập nhật VLAN thành công",
+		Data:       vlan,
+	})
+}
+
+func (h *VrfHandler) DeleteVlan(c echo.Context) error {
+	vlanId, err := strconv.Atoi(c.Param("vlan_id"))
+	if err != nil {
+		return h.errorResponse(c, errors.VlanNotFound)
+	}
+
+	if err := h.VrfRepo.DeleteVlan(c.Request().Context(), vlanId); err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    "Xóa VLAN thành công",
+		Data:       nil,
+	})
+}
+
+func (h *VrfHandler) errorResponse(c echo.Context, err error) error {
+	switch err {
+	case errors.VrfNotFound, errors.VlanNotFound:
+		return c.JSON(http.StatusNotFound, model.ResponseAsset{
+			StatusCode: http.StatusNotFound,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	case errors.VrfConflict, errors.VrfInUse, errors.VlanConflict, errors.VlanInUse, errors.VlanVrfMismatch:
+		return c.JSON(http.StatusConflict, model.ResponseAsset{
+			StatusCode: http.StatusConflict,
+			Message:    err.Error(),
+			Data:       nil,
+		})
+	}
+
+	log.Error(err.Error())
+	return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+		StatusCode: http.StatusInternalServerError,
+		Message:    "Failed to process VRF request",
+		Data:       nil,
+	})
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/export"
+	"github.com/sllpklls/template-backend-go/importer"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/zonefile"
+)
+
+// TTL mặc định của zone file export
+const defaultZoneTTL = 3600
+
+// ImportZone nhận zone file BIND (multipart field "file") và upsert asset từ các bản ghi A, AAAA, PTR.
+// origin là $ORIGIN ban đầu nếu file dùng tên tương đối mà không khai báo $ORIGIN.
+// mode, dataset_id, dry_run, allow_duplicate_address, mapping giống import CSV, mode mặc định là upsert.
+// Với mapping, bản ghi nguồn là các field sinh từ zone (dns_host_name, address, address_type, system_name).
+func (h *NetworkAssetHandler) ImportZone(c echo.Context) error {
+	opts, paramErrs := importOptions(c, model.ImportUpsert)
+	if len(paramErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       paramErrs,
+		})
+	}
+
+	m, err := h.importMapping(c)
+	if err != nil {
+		return mappingErrorResponse(c, err)
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Zone file is required",
+			Data:       nil,
+		})
+	}
+	if fileHeader.Size > maxImportSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, model.ResponseAsset{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Message:    "Zone file is too large",
+			Data:       nil,
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Failed to read zone file",
+			Data:       nil,
+		})
+	}
+	defer file.Close()
+
+	rows, err := importer.ParseZone(file, c.QueryParam("origin"))
+	if err != nil {
+		var parseErr *zonefile.ParseError
+		if stderrors.As(err, &parseErr) {
+			return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Invalid zone file",
+				Data:       []model.FieldError{{Field: fmt.Sprintf("line %d", parseErr.Line), Message: parseErr.Message}},
+			})
+		}
+		log.Error(err.Error())
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Failed to read zone file",
+			Data:       nil,
+		})
+	}
+
+	var mappingFailed []model.ImportRowResult
+	if m != nil {
+		rows, mappingFailed = m.ApplyRows(rows, true)
+		if len(mappingFailed) > 0 && !opts.DryRun {
+			return mappingFailedResponse(c, opts, mappingFailed)
+		}
+	}
+
+	report, err := h.NetworkAssetRepo.ImportZone(c.Request().Context(), rows, opts, getActor(c))
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to import zone file",
+			Data:       nil,
+		})
+	}
+	addFailedRows(report, mappingFailed)
+
+	if report.Failed > 0 && !opts.DryRun {
+		return c.JSON(http.StatusUnprocessableEntity, model.ResponseAsset{
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    "Import failed, no rows were written",
+			Data:       report,
+		})
+	}
+
+	message := "Import zone file thành công"
+	if opts.DryRun {
+		message = "Dry run import zone file thành công"
+	}
+	return c.JSON(http.StatusOK, model.ResponseAsset{
+		StatusCode: http.StatusOK,
+		Message:    message,
+		Data:       report,
+	})
+}
+
+// ExportZone sinh zone file cho origin: bản ghi A/AAAA của các asset có hostname thuộc zone, hoặc
+// PTR nếu origin là reverse zone (x.x.x.in-addr.arpa., ip6.arpa.). SOA và NS lấy từ tham số ns (bắt buộc)
+// và hostmaster (mặc định hostmaster.<origin>); serial là thời điểm export (unix time). ns nằm trong zone
+// phải có bản ghi A/AAAA trong chính zone đó, nếu không BIND từ chối nạp zone.
+func (h *NetworkAssetHandler) ExportZone(c echo.Context) error {
+	origin := zonefile.Fqdn(c.QueryParam("origin"))
+	ns := zonefile.Fqdn(c.QueryParam("ns"))
+	hostmaster := zonefile.Fqdn(c.QueryParam("hostmaster"))
+	if hostmaster == "" {
+		hostmaster = "hostmaster." + origin
+	}
+
+	var paramErrs []model.FieldError
+	filter := model.ZoneFilter{}
+	if origin == "" || !zonefile.ValidName(origin) {
+		paramErrs = append(paramErrs, model.FieldError{Field: "origin", Message: "must be a valid domain name"})
+	} else if zonefile.IsReverseZone(origin) {
+		prefix, ok := zonefile.ReversePrefix(origin)
+		if !ok {
+			paramErrs = append(paramErrs, model.FieldError{Field: "origin", Message: "reverse zone must be on an octet (IPv4) or nibble (IPv6) boundary"})
+		}
+		filter.Cidr = prefix.String()
+	} else {
+		filter.Domain = strings.TrimSuffix(origin, ".")
+	}
+	if ns == "" {
+		paramErrs = append(paramErrs, model.FieldError{Field: "ns", Message: "required"})
+	} else if !zonefile.ValidName(ns) {
+		paramErrs = append(paramErrs, model.FieldError{Field: "ns", Message: "must be a valid domain name"})
+	}
+	if !zonefile.ValidName(hostmaster) {
+		paramErrs = append(paramErrs, model.FieldError{Field: "hostmaster", Message: "must be a valid domain name"})
+	}
+	ttl := uint32(defaultZoneTTL)
+	if ttlStr := c.QueryParam("ttl"); ttlStr != "" {
+		t, err := zonefile.ParseTTL(ttlStr)
+		if err != nil {
+			paramErrs = append(paramErrs, model.FieldError{Field: "ttl", Message: err.Error()})
+		}
+		ttl = t
+	}
+	if datasetStr := c.QueryParam("dataset_id"); datasetStr != "" {
+		id, err := strconv.Atoi(datasetStr)
+		if err != nil {
+			paramErrs = append(paramErrs, model.FieldError{Field: "dataset_id", Message: "must be an integer"})
+		}
+		filter.DatasetId = id
+	}
+	if len(paramErrs) > 0 {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data:       paramErrs,
+		})
+	}
+
+	assets, err := h.NetworkAssetRepo.GetAssetsForZone(c.Request().Context(), filter)
+	if err != nil {
+		log.Error(err.Error())
+		return c.JSON(http.StatusInternalServerError, model.ResponseAsset{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to export zone file",
+			Data:       nil,
+		})
+	}
+
+	records := export.ZoneRecords(origin, assets)
+	if zonefile.InZone(ns, origin) && !hasAddressRecord(records, ns) {
+		return c.JSON(http.StatusBadRequest, model.ResponseAsset{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Validation failed",
+			Data: []model.FieldError{{Field: "ns", Message: fmt.Sprintf(
+				"%s is inside %s but no asset gives it an A/AAAA record, use a name server outside the zone", ns, origin)}},
+		})
+	}
+
+	zone := zonefile.Zone{
+		Origin:     origin,
+		TTL:        ttl,
+		Serial:     uint32(time.Now().Unix()),
+		PrimaryNS:  ns,
+		Hostmaster: hostmaster,
+		Records:    records,
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/dns; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="db.%s"`, strings.TrimSuffix(origin, ".")))
+	res.WriteHeader(http.StatusOK)
+	if err := zonefile.Write(res, zone); err != nil {
+		log.Error(err.Error())
+	}
+	return nil
+}
+
+// hasAddressRecord cho biết zone có bản ghi A/AAAA cho name
+func hasAddressRecord(records []zonefile.Record, name string) bool {
+	for _, r := range records {
+		if r.Name == name && (r.Type == "A" || r.Type == "AAAA") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// ParseNetworkAssetCSV đọc file CSV có dòng header. Tên cột là tên json (dns_host_name)
+// hoặc tên cột db (dnshostname) của NetworkAsset. Lỗi header trả về dạng field error,
+// lỗi giá trị từng dòng được kiểm tra khi import.
+func ParseNetworkAssetCSV(r io.Reader) ([]model.ImportRow, []model.FieldError, error) {
+	reader, headerErrs, err := NewCSVReader(r)
+	if err != nil || len(headerErrs) > 0 {
+		return nil, headerErrs, err
+	}
+	return reader.ReadAll()
+}
+
+// ParseSourceCSV đọc file CSV với tên cột của nguồn, giá trị cần chuyển đổi bằng mapping trước khi import
+func ParseSourceCSV(r io.Reader) ([]model.ImportRow, []model.FieldError, error) {
+	reader, headerErrs, err := NewSourceCSVReader(r)
+	if err != nil || len(headerErrs) > 0 {
+		return nil, headerErrs, err
+	}
+	return reader.ReadAll()
+}
+
+// CSVReader đọc từng dòng của file CSV network asset, dùng khi không muốn giữ cả file trong bộ nhớ
+type CSVReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+// ColumnCountError: dòng có số cột khác header, có thể đọc tiếp dòng sau
+type ColumnCountError struct {
+	Line     int
+	Expected int
+	Got      int
+}
+
+func (e *ColumnCountError) Error() string {
+	return fmt.Sprintf("expected %d columns, got %d", e.Expected, e.Got)
+}
+
+// NewCSVReader đọc và kiểm tra header, lỗi header trả về dạng field error
+func NewCSVReader(r io.Reader) (*CSVReader, []model.FieldError, error) {
+	reader, header, headerErrs, err := readHeader(r)
+	if err != nil || len(headerErrs) > 0 {
+		return nil, headerErrs, err
+	}
+
+	columns := make([]string, len(header))
+	seen := map[string]bool{}
+	for i, h := range header {
+		f, ok := model.LookupImportField(h)
+		if !ok {
+			headerErrs = append(headerErrs, model.FieldError{Field: h, Message: "unknown or read-only column"})
+			continue
+		}
+		if seen[f.Name] {
+			headerErrs = append(headerErrs, model.FieldError{Field: h, Message: "duplicate column"})
+			continue
+		}
+		seen[f.Name] = true
+		columns[i] = f.Name
+	}
+	if !seen["name"] {
+		headerErrs = append(headerErrs, model.FieldError{Field: "name", Message: "column is required"})
+	}
+	if len(headerErrs) > 0 {
+		return nil, headerErrs, nil
+	}
+
+	return &CSVReader{reader: reader, columns: columns}, nil, nil
+}
+
+// NewSourceCSVReader giữ nguyên tên cột của file (IP, ip_addr...) để chuyển đổi bằng mapping,
+// chỉ kiểm tra cột rỗng và cột trùng tên (không phân biệt hoa thường)
+func NewSourceCSVReader(r io.Reader) (*CSVReader, []model.FieldError, error) {
+	reader, header, headerErrs, err := readHeader(r)
+	if err != nil || len(headerErrs) > 0 {
+		return nil, headerErrs, err
+	}
+
+	columns := make([]string, len(header))
+	seen := map[string]bool{}
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		key := strings.ToLower(h)
+		if h == "" {
+			headerErrs = append(headerErrs, model.FieldError{Field: fmt.Sprintf("column %d", i+1), Message: "column name is required"})
+			continue
+		}
+		if seen[key] {
+			headerErrs = append(headerErrs, model.FieldError{Field: h, Message: "duplicate column"})
+			continue
+		}
+		seen[key] = true
+		columns[i] = h
+	}
+	if len(headerErrs) > 0 {
+		return nil, headerErrs, nil
+	}
+
+	return &CSVReader{reader: reader, columns: columns}, nil, nil
+}
+
+func readHeader(r io.Reader) (*csv.Reader, []string, []model.FieldError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, []model.FieldError{{Field: "file", Message: "empty file"}}, nil
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff") // BOM do Excel thêm vào
+	}
+	// ReuseRecord: header phải được copy trước lần đọc tiếp theo
+	return reader, append([]string(nil), header...), nil, nil
+}
+
+// Next trả về dòng tiếp theo (Row là số dòng trong file), io.EOF khi hết file
+func (r *CSVReader) Next() (model.ImportRow, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		return model.ImportRow{}, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	if len(record) != len(r.columns) {
+		return model.ImportRow{Row: line}, &ColumnCountError{Line: line, Expected: len(r.columns), Got: len(record)}
+	}
+
+	row := model.ImportRow{Row: line, Values: make(map[string]string, len(record))}
+	for i, value := range record {
+		row.Values[r.columns[i]] = strings.TrimSpace(value)
+	}
+	return row, nil
+}
+
+// ReadAll đọc mọi dòng còn lại, dòng sai số cột trả về dạng field error
+func (r *CSVReader) ReadAll() ([]model.ImportRow, []model.FieldError, error) {
+	var rows []model.ImportRow
+	for {
+		row, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if e, ok := err.(*ColumnCountError); ok {
+			return nil, []model.FieldError{{Field: fmt.Sprintf("row %d", e.Line), Message: e.Error()}}, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil, nil
+}
@@ -0,0 +1,160 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type nmapHost struct {
+	StartTime int64 `xml:"starttime,attr"`
+	EndTime   int64 `xml:"endtime,attr"`
+	Status    struct {
+		State string `xml:"state,attr"`
+	} `xml:"status"`
+	Addresses []struct {
+		Addr     string `xml:"addr,attr"`
+		AddrType string `xml:"addrtype,attr"`
+	} `xml:"address"`
+	Hostnames []struct {
+		Name string `xml:"name,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"hostnames>hostname"`
+	Ports []struct {
+		Protocol string `xml:"protocol,attr"`
+		PortId   int    `xml:"portid,attr"`
+		State    struct {
+			State string `xml:"state,attr"`
+		} `xml:"state"`
+		Service struct {
+			Name    string `xml:"name,attr"`
+			Product string `xml:"product,attr"`
+			Version string `xml:"version,attr"`
+		} `xml:"service"`
+	} `xml:"ports>port"`
+}
+
+// ParseNmapXML đọc output -oX của nmap theo từng host (không giữ cả file trong bộ nhớ), trả về các
+// host đang up kèm các port mở. Thời điểm thấy host là endtime của host, không có thì lấy thời điểm
+// bắt đầu scan.
+func ParseNmapXML(r io.Reader) ([]model.DiscoveredHost, error) {
+	decoder := xml.NewDecoder(r)
+	var hosts []model.DiscoveredHost
+	byAddress := map[string]int{}
+	var scanStart time.Time
+	foundRun := false
+	index := 0
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid nmap xml: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "nmaprun":
+			foundRun = true
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "start" {
+					if sec, err := strconv.ParseInt(attr.Value, 10, 64); err == nil {
+						scanStart = time.Unix(sec, 0).UTC()
+					}
+				}
+			}
+		case "host":
+			index++
+			var h nmapHost
+			if err := decoder.DecodeElement(&h, &start); err != nil {
+				return nil, fmt.Errorf("invalid nmap xml at host %d: %w", index, err)
+			}
+			host, ok := discoveredHost(h, index, scanStart)
+			if !ok {
+				continue
+			}
+			// Cùng địa chỉ xuất hiện nhiều lần (gộp nhiều scan): giữ lần thấy sau cùng
+			if i, seen := byAddress[host.Address]; seen {
+				if host.SeenAt.Before(hosts[i].SeenAt) {
+					continue
+				}
+				hosts[i] = host
+				continue
+			}
+			byAddress[host.Address] = len(hosts)
+			hosts = append(hosts, host)
+		}
+	}
+	if !foundRun {
+		return nil, fmt.Errorf("invalid nmap xml: missing nmaprun element")
+	}
+	return hosts, nil
+}
+
+func discoveredHost(h nmapHost, index int, scanStart time.Time) (model.DiscoveredHost, bool) {
+	if h.Status.State != "up" {
+		return model.DiscoveredHost{}, false
+	}
+
+	host := model.DiscoveredHost{Row: index, SeenAt: scanStart}
+	if h.EndTime > 0 {
+		host.SeenAt = time.Unix(h.EndTime, 0).UTC()
+	} else if h.StartTime > 0 {
+		host.SeenAt = time.Unix(h.StartTime, 0).UTC()
+	}
+	if host.SeenAt.IsZero() {
+		host.SeenAt = time.Now().UTC()
+	}
+
+	for _, a := range h.Addresses {
+		switch a.AddrType {
+		case "ipv4", "ipv6":
+			if addr, err := netip.ParseAddr(a.Addr); err == nil {
+				host.Address = addr.Unmap().String()
+			}
+		case "mac":
+			if mac, err := net.ParseMAC(a.Addr); err == nil {
+				host.MAC = mac.String()
+			}
+		}
+	}
+	if host.Address == "" {
+		return model.DiscoveredHost{}, false
+	}
+
+	// Tên người dùng nhập làm target ưu tiên hơn tên PTR
+	for _, hostnameType := range []string{"user", "PTR"} {
+		for _, hn := range h.Hostnames {
+			if hn.Type == hostnameType && host.Hostname == "" {
+				host.Hostname = strings.TrimSuffix(strings.ToLower(hn.Name), ".")
+			}
+		}
+	}
+
+	for _, p := range h.Ports {
+		if !strings.HasPrefix(p.State.State, "open") {
+			continue
+		}
+		host.Ports = append(host.Ports, model.NetworkAssetPort{
+			Protocol: p.Protocol,
+			Port:     p.PortId,
+			State:    p.State.State,
+			Service:  p.Service.Name,
+			Product:  p.Service.Product,
+			Version:  p.Service.Version,
+		})
+	}
+	return host, true
+}
@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/sllpklls/template-backend-go/ipaddr"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/zonefile"
+)
+
+// ParseZone đọc các bản ghi A, AAAA và PTR của zone file, mỗi cặp (hostname, địa chỉ) là một dòng
+// import. Bản ghi A và PTR của cùng một cặp được gộp lại; các loại bản ghi khác bị bỏ qua.
+// Cột name để trống, tên asset được gán khi import.
+func ParseZone(r io.Reader, origin string) ([]model.ImportRow, error) {
+	records, err := zonefile.Parse(r, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	type pair struct {
+		host string
+		addr netip.Addr
+	}
+	lines := map[pair]int{}
+	var pairs []pair
+	add := func(line int, host string, addr netip.Addr) {
+		p := pair{host: strings.TrimSuffix(host, "."), addr: addr.Unmap()}
+		if _, ok := lines[p]; ok {
+			return
+		}
+		lines[p] = line
+		pairs = append(pairs, p)
+	}
+
+	for _, record := range records {
+		switch record.Type {
+		case "A", "AAAA":
+			addr, err := netip.ParseAddr(record.Data[0])
+			if err != nil || addr.Is4() != (record.Type == "A") {
+				return nil, &zonefile.ParseError{Line: record.Line, Message: fmt.Sprintf("invalid %s address %q", record.Type, record.Data[0])}
+			}
+			add(record.Line, record.Name, addr)
+		case "PTR":
+			// PTR không ứng với một địa chỉ cụ thể (ví dụ delegation RFC 2317) thì bỏ qua
+			if addr, ok := zonefile.AddrFromReverse(record.Name); ok {
+				add(record.Line, record.Data[0], addr)
+			}
+		}
+	}
+
+	// Thứ tự ổn định để tên sinh ra (web.local, web.local-2...) không đổi giữa các lần import
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].host != pairs[j].host {
+			return pairs[i].host < pairs[j].host
+		}
+		if pairs[i].addr.Is4() != pairs[j].addr.Is4() {
+			return pairs[i].addr.Is4()
+		}
+		return pairs[i].addr.Less(pairs[j].addr)
+	})
+
+	rows := make([]model.ImportRow, 0, len(pairs))
+	for _, p := range pairs {
+		rows = append(rows, model.ImportRow{Row: lines[p], Values: map[string]string{
+			"dns_host_name": p.host,
+			"address":       p.addr.String(),
+			"address_type":  ipaddr.Family(p.addr),
+			"system_name":   p.host,
+		}})
+	}
+	return rows, nil
+}
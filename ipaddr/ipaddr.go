@@ -0,0 +1,276 @@
+// Package ipaddr chuẩn hóa và kiểm tra địa chỉ IP, subnet mask cho NetworkAsset.
+// Dạng chuẩn: địa chỉ không kèm prefix (IPv6 theo RFC 5952: chữ thường, bỏ số 0 đứng đầu, "::"
+// thay cho dãy nhóm 0 dài nhất - đúng với netip.Addr.String), mask là độ dài prefix ("24").
+package ipaddr
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	IPv4 = "IPv4"
+	IPv6 = "IPv6"
+)
+
+// ParseAddress nhận "10.0.0.1" hoặc "10.0.0.1/24", trả về địa chỉ và prefix (-1 nếu không có)
+func ParseAddress(s string) (netip.Addr, int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return netip.Addr{}, -1, fmt.Errorf("required")
+	}
+
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Addr{}, -1, fmt.Errorf("invalid CIDR %q", s)
+		}
+		return prefix.Addr(), prefix.Bits(), nil
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, -1, fmt.Errorf("invalid IP address %q", s)
+	}
+	if addr.Zone() != "" {
+		return netip.Addr{}, -1, fmt.Errorf("zoned IPv6 addresses are not supported")
+	}
+	return addr, -1, nil
+}
+
+// ParseMask nhận độ dài prefix ("24", "/24") hoặc mask dạng địa chỉ ("255.255.255.0",
+// "ffff:ffff:ffff:ffff::") và trả về độ dài prefix cho họ địa chỉ tương ứng
+func ParseMask(s string, is4 bool) (int, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "/")
+	maxBits := 128
+	if is4 {
+		maxBits = 32
+	}
+
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 || n > maxBits {
+			return 0, fmt.Errorf("prefix length must be between 0 and %d", maxBits)
+		}
+		return n, nil
+	}
+
+	mask, err := netip.ParseAddr(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid subnet mask %q", s)
+	}
+	if mask.Is4() != is4 {
+		return 0, fmt.Errorf("subnet mask family does not match address")
+	}
+
+	bits := 0
+	seenZero := false
+	for _, b := range mask.AsSlice() {
+		for i := 7; i >= 0; i-- {
+			if b&(1<<i) != 0 {
+				if seenZero {
+					return 0, fmt.Errorf("subnet mask %q is not contiguous", s)
+				}
+				bits++
+			} else {
+				seenZero = true
+			}
+		}
+	}
+	return bits, nil
+}
+
+// Family trả về IPv4 hoặc IPv6
+func Family(addr netip.Addr) string {
+	if addr.Is4() {
+		return IPv4
+	}
+	return IPv6
+}
+
+// NormalizeFamily chuẩn hóa AddressType ("ipv4", "IPV6"...), rỗng nếu không hợp lệ
+func NormalizeFamily(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "ipv4", "v4", "4":
+		return IPv4
+	case "ipv6", "v6", "6":
+		return IPv6
+	}
+	return ""
+}
+
+// ParseCIDR nhận CIDR dạng network (10.0.0.0/24), báo lỗi nếu còn bit host
+func ParseCIDR(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR %q", s)
+	}
+	if prefix.Masked() != prefix {
+		return netip.Prefix{}, fmt.Errorf("%s has host bits set, did you mean %s", s, prefix.Masked())
+	}
+	return prefix, nil
+}
+
+// ToBig chuyển địa chỉ thành số nguyên
+func ToBig(addr netip.Addr) *big.Int {
+	return new(big.Int).SetBytes(addr.AsSlice())
+}
+
+// FromBig chuyển số nguyên thành địa chỉ cùng họ với is4
+func FromBig(n *big.Int, is4 bool) netip.Addr {
+	size := 16
+	if is4 {
+		size = 4
+	}
+	buf := make([]byte, size)
+	n.FillBytes(buf)
+	addr, _ := netip.AddrFromSlice(buf)
+	return addr
+}
+
+// LastAddr trả về địa chỉ cuối của prefix (broadcast với IPv4)
+func LastAddr(prefix netip.Prefix) netip.Addr {
+	first := ToBig(prefix.Masked().Addr())
+	return FromBig(first.Add(first, new(big.Int).Sub(PrefixSize(prefix), big.NewInt(1))), prefix.Addr().Is4())
+}
+
+// PrefixSize là số địa chỉ trong prefix
+func PrefixSize(prefix netip.Prefix) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(prefix.Addr().BitLen()-prefix.Bits()))
+}
+
+// HasNetworkBroadcast cho biết địa chỉ đầu/cuối của prefix không dùng được cho host (IPv4 từ /30 trở lên)
+func HasNetworkBroadcast(prefix netip.Prefix) bool {
+	return prefix.Addr().Is4() && prefix.Bits() <= 30
+}
+
+// UsableSize là số địa chỉ cấp được cho host
+func UsableSize(prefix netip.Prefix) *big.Int {
+	size := PrefixSize(prefix)
+	if HasNetworkBroadcast(prefix) {
+		size.Sub(size, big.NewInt(2))
+	}
+	return size
+}
+
+// RangeSize là số địa chỉ trong dải [start, end]
+func RangeSize(start, end netip.Addr) *big.Int {
+	n := new(big.Int).Sub(ToBig(end), ToBig(start))
+	return n.Add(n, big.NewInt(1))
+}
+
+// Range là dải địa chỉ [Start, End]
+type Range struct {
+	Start netip.Addr
+	End   netip.Addr
+}
+
+// ParseRange nhận dải "10.0.0.10-10.0.0.50", hai đầu phải cùng họ địa chỉ và Start <= End
+func ParseRange(s string) (Range, error) {
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return Range{}, fmt.Errorf("invalid range %q, expected start-end", s)
+	}
+	start, err := netip.ParseAddr(strings.TrimSpace(startStr))
+	if err != nil {
+		return Range{}, fmt.Errorf("invalid IP address %q", startStr)
+	}
+	end, err := netip.ParseAddr(strings.TrimSpace(endStr))
+	if err != nil {
+		return Range{}, fmt.Errorf("invalid IP address %q", endStr)
+	}
+	if start.Zone() != "" || end.Zone() != "" {
+		return Range{}, fmt.Errorf("zoned IPv6 addresses are not supported")
+	}
+	if start.Is4() != end.Is4() {
+		return Range{}, fmt.Errorf("range start and end must be the same address family")
+	}
+	if end.Less(start) {
+		return Range{}, fmt.Errorf("range start must not be after end")
+	}
+	return Range{Start: start, End: end}, nil
+}
+
+// String trả về dải dạng "start-end"
+func (r Range) String() string {
+	return r.Start.String() + "-" + r.End.String()
+}
+
+// HostRange trả về dải địa chỉ cấp được cho host trong prefix
+func HostRange(prefix netip.Prefix) Range {
+	r := Range{Start: prefix.Masked().Addr(), End: LastAddr(prefix)}
+	if HasNetworkBroadcast(prefix) {
+		r.Start = r.Start.Next()
+		r.End = r.End.Prev()
+	}
+	return r
+}
+
+// FindFree tìm count địa chỉ liên tiếp đầu tiên trong prefix không giao với các dải taken.
+// Trả về false nếu không còn đủ chỗ.
+func FindFree(prefix netip.Prefix, taken []Range, count int) (netip.Addr, bool) {
+	hosts := HostRange(prefix)
+	if count <= 0 || hosts.End.Less(hosts.Start) {
+		return netip.Addr{}, false
+	}
+
+	candidate := ToBig(hosts.Start)
+	last := ToBig(hosts.End)
+	need := big.NewInt(int64(count))
+	fits := func(end *big.Int) bool {
+		gap := new(big.Int).Sub(end, candidate)
+		gap.Add(gap, big.NewInt(1))
+		return gap.Cmp(need) >= 0
+	}
+
+	sorted := append([]Range{}, taken...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Less(sorted[j].Start) })
+
+	for _, r := range sorted {
+		start, end := ToBig(r.Start), ToBig(r.End)
+		if end.Cmp(candidate) < 0 {
+			continue
+		}
+		if start.Cmp(last) > 0 {
+			break
+		}
+		if start.Cmp(candidate) > 0 && fits(new(big.Int).Sub(start, big.NewInt(1))) {
+			return FromBig(candidate, prefix.Addr().Is4()), true
+		}
+		candidate = end.Add(end, big.NewInt(1))
+		if candidate.Cmp(last) > 0 {
+			return netip.Addr{}, false
+		}
+	}
+
+	if fits(last) {
+		return FromBig(candidate, prefix.Addr().Is4()), true
+	}
+	return netip.Addr{}, false
+}
+
+// IsFree kiểm tra count địa chỉ liên tiếp từ start đều là host của prefix và không thuộc dải taken
+func IsFree(prefix netip.Prefix, taken []Range, start netip.Addr, count int) bool {
+	hosts := HostRange(prefix)
+	if count <= 0 || !start.IsValid() || start.Less(hosts.Start) {
+		return false
+	}
+
+	endBig := ToBig(start)
+	endBig.Add(endBig, big.NewInt(int64(count-1)))
+	if endBig.Cmp(ToBig(hosts.End)) > 0 {
+		return false
+	}
+	end := FromBig(endBig, start.Is4())
+
+	for _, r := range taken {
+		if !r.End.Less(start) && !end.Less(r.Start) {
+			return false
+		}
+	}
+	return true
+}
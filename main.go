@@ -1,20 +1,35 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/labstack/gommon/log"
 
 	"github.com/sllpklls/template-backend-go/db"
+	"github.com/sllpklls/template-backend-go/dhcp"
+	"github.com/sllpklls/template-backend-go/dnscheck"
+	"github.com/sllpklls/template-backend-go/etl"
 	"github.com/sllpklls/template-backend-go/handler"
+	"github.com/sllpklls/template-backend-go/mapping"
+	"github.com/sllpklls/template-backend-go/migrations"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/reconciliation"
+	"github.com/sllpklls/template-backend-go/remedy"
+	"github.com/sllpklls/template-backend-go/repository"
 	"github.com/sllpklls/template-backend-go/repository/repo_impl"
 	"github.com/sllpklls/template-backend-go/router"
 )
 
+// Actor ghi vào lịch sử của asset bị purge định kỳ
+const purgeActor = "purge-scheduler"
+
 func main() {
 	// lấy env, nếu không có thì dùng default
 	host := getEnv("DB_HOST", "localhost")
@@ -38,6 +53,24 @@ func main() {
 	sql.Connect()
 	defer sql.Close()
 
+	// go run . migrate up|down|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(sql, os.Args[2:]); err != nil {
+			fmt.Println("❌", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Tự động chạy migration khi khởi động (tắt bằng AUTO_MIGRATE=false)
+	if getEnv("AUTO_MIGRATE", "true") == "true" {
+		n, err := sql.MigrateUp(migrations.FS)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Applied %d migrations\n", n)
+	}
+
 	e := echo.New()
 
 	// ✅ Log request (method, endpoint, IP)
@@ -71,20 +104,207 @@ func main() {
 	userHandler := handler.UserHandler{
 		UserRepo: repo_impl.NewUserRepo(sql),
 	}
+	purgeRetentionDays, err := strconv.Atoi(getEnv("PURGE_RETENTION_DAYS", "30"))
+	if err != nil {
+		purgeRetentionDays = 30
+	}
+	// File mapping YAML cho import (tham số mapping=<id>) và ETL job (config "mapping")
+	mappings := &mapping.Store{Dir: getEnv("MAPPING_DIR", "mappings")}
+	networkAssetRepo := repo_impl.NewNetworkAssetRepo(sql)
 	networkAssetHandler := handler.NetworkAssetHandler{
-		NetworkAssetRepo: repo_impl.NewNetworkAssetRepo(sql),
+		NetworkAssetRepo:   networkAssetRepo,
+		PurgeRetentionDays: purgeRetentionDays,
+		Mappings:           mappings,
+	}
+
+	// Purge định kỳ asset đã xóa mềm quá PURGE_RETENTION_DAYS ngày, PURGE_INTERVAL=0 là chỉ purge qua API
+	if interval, err := time.ParseDuration(getEnv("PURGE_INTERVAL", "24h")); err == nil && interval > 0 {
+		go schedulePurge(context.Background(), networkAssetRepo, purgeRetentionDays, interval)
+	}
+
+	// Đồng bộ lease DHCP định kỳ: DHCP_LEASE_FILES="dhcpd:/var/lib/dhcp/dhcpd.leases,kea:/var/lib/kea/kea-leases4.csv",
+	// DHCP_SYNC_INTERVAL rỗng hoặc 0 là tắt
+	if interval, err := time.ParseDuration(getEnv("DHCP_SYNC_INTERVAL", "0")); err == nil && interval > 0 {
+		sources, err := dhcp.ParseSources(getEnv("DHCP_LEASE_FILES", ""))
+		if err != nil {
+			log.Fatal(err)
+		}
+		datasetId, _ := strconv.Atoi(getEnv("DHCP_DATASET_ID", "0"))
+		syncer := &dhcp.Syncer{Repo: networkAssetRepo, Sources: sources, DatasetId: datasetId}
+		go syncer.Schedule(context.Background(), interval)
+	}
+
+	ciRegistry := model.NewDefaultCIRegistry()
+	ciHandler := handler.CIHandler{
+		Registry: ciRegistry,
+		CIRepo:   repo_impl.NewCIRepo(sql),
+	}
+	relationshipHandler := handler.RelationshipHandler{
+		RelationshipRepo: repo_impl.NewRelationshipRepo(sql, ciRegistry),
+	}
+	reconciliationRepo := repo_impl.NewReconciliationRepo(sql)
+	reconciliationHandler := handler.ReconciliationHandler{
+		ReconciliationRepo: reconciliationRepo,
+		Engine:             &reconciliation.Engine{Repo: reconciliationRepo},
+	}
+
+	subnetHandler := handler.SubnetHandler{
+		SubnetRepo: repo_impl.NewSubnetRepo(sql),
+	}
+	vrfHandler := handler.VrfHandler{
+		VrfRepo: repo_impl.NewVrfRepo(sql),
+	}
+
+	// DNS_SERVER rỗng là dùng resolver của hệ thống, DNS_CHECK_INTERVAL rỗng hoặc 0 là tắt kiểm tra định kỳ
+	dnsCheckRepo := repo_impl.NewDNSCheckRepo(sql)
+	dnsChecker := &dnscheck.Checker{
+		Repo:     dnsCheckRepo,
+		Resolver: dnscheck.NewResolver(getEnv("DNS_SERVER", ""), 5*time.Second),
+	}
+	if interval, err := time.ParseDuration(getEnv("DNS_CHECK_INTERVAL", "0")); err == nil && interval > 0 {
+		go dnsChecker.Schedule(context.Background(), interval)
+	}
+	dnsCheckHandler := handler.DNSCheckHandler{
+		DNSCheckRepo: dnsCheckRepo,
+		Checker:      dnsChecker,
+	}
+
+	// Connector Remedy: REMEDY_URL rỗng là tắt, REMEDY_SYNC_INTERVAL rỗng hoặc 0 là chỉ chạy qua API,
+	// REMEDY_MAPPING là id file mapping thay cho mapping mặc định
+	connectorRepo := repo_impl.NewConnectorRepo(sql)
+	connectorHandler := handler.ConnectorHandler{ConnectorRepo: connectorRepo}
+	if remedyURL := getEnv("REMEDY_URL", ""); remedyURL != "" {
+		datasetId, _ := strconv.Atoi(getEnv("REMEDY_TARGET_DATASET_ID", "0"))
+		connectorHandler.Remedy = &remedy.Connector{
+			Client: &remedy.Client{
+				BaseURL:  remedyURL,
+				Username: getEnv("REMEDY_USER", ""),
+				Password: getEnv("REMEDY_PASSWORD", ""),
+			},
+			Repo:      networkAssetRepo,
+			State:     connectorRepo,
+			Form:      getEnv("REMEDY_FORM", remedy.DefaultForm),
+			Dataset:   getEnv("REMEDY_DATASET", remedy.DefaultDataset),
+			DatasetId: datasetId,
+		}
+		if id := getEnv("REMEDY_MAPPING", ""); id != "" {
+			m, err := mappings.Get(id)
+			if err != nil {
+				log.Fatal(err)
+			}
+			connectorHandler.Remedy.Mapping = m
+		}
+		if interval, err := time.ParseDuration(getEnv("REMEDY_SYNC_INTERVAL", "0")); err == nil && interval > 0 {
+			go connectorHandler.Remedy.Schedule(context.Background(), interval)
+		}
+	}
+
+	// ETL job: ETL_FILE_ROOT là thư mục duy nhất job được đọc file (rỗng là tắt nguồn file), ETL_REMEDY_URLS
+	// là các URL Remedy job được gọi, cách nhau bởi dấu phẩy, mặc định REMEDY_URL; ETL_SCHEDULER=false để tắt chạy theo lịch
+	etlRepo := repo_impl.NewEtlRepo(sql)
+	etlRunner := &etl.Runner{
+		Repo: etlRepo,
+		Sources: etl.DefaultSources(networkAssetRepo, connectorRepo, getEnv("ETL_FILE_ROOT", ""),
+			strings.Split(getEnv("ETL_REMEDY_URLS", getEnv("REMEDY_URL", "")), ","), mappings),
+	}
+	etlHandler := handler.EtlHandler{EtlRepo: etlRepo, Runner: etlRunner}
+	if getEnv("ETL_SCHEDULER", "true") == "true" {
+		etlHandler.Scheduler = &etl.Scheduler{Repo: etlRepo, Runner: etlRunner}
+		if err := etlHandler.Scheduler.Start(context.Background()); err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	api := router.API{
-		Echo:                e,
-		UserHandler:         userHandler,
-		NetworkAssetHandler: networkAssetHandler, // Thêm này
+		Echo:                  e,
+		UserHandler:           userHandler,
+		NetworkAssetHandler:   networkAssetHandler, // Thêm này
+		CIHandler:             ciHandler,
+		RelationshipHandler:   relationshipHandler,
+		ReconciliationHandler: reconciliationHandler,
+		SubnetHandler:         subnetHandler,
+		VrfHandler:            vrfHandler,
+		DNSCheckHandler:       dnsCheckHandler,
+		ConnectorHandler:      connectorHandler,
+		EtlHandler:            etlHandler,
+		MappingHandler:        handler.MappingHandler{Store: mappings},
 	}
 	api.SetupRouter()
 
 	e.Logger.Fatal(e.Start(":3000"))
 }
 
+func runMigrate(sql *db.Sql, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up|down [steps|all]|status")
+	}
+
+	switch args[0] {
+	case "up":
+		n, err := sql.MigrateUp(migrations.FS)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migrations\n", n)
+	case "down":
+		// MigrateDown coi steps <= 0 là rollback tất cả, nên chỉ nhận giá trị đó qua "all"
+		steps := 1
+		if len(args) > 1 {
+			if args[1] == "all" {
+				steps = 0
+			} else {
+				s, err := strconv.Atoi(args[1])
+				if err != nil || s < 1 {
+					return fmt.Errorf("invalid steps: %s, must be a positive number or all", args[1])
+				}
+				steps = s
+			}
+		}
+		n, err := sql.MigrateDown(migrations.FS, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %d migrations\n", n)
+	case "status":
+		status, err := sql.MigrationStatus(migrations.FS)
+		if err != nil {
+			return err
+		}
+		for _, st := range status {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%-40s %s\n", st.Id, applied)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command: %s", args[0])
+	}
+	return nil
+}
+
+// schedulePurge xóa hẳn asset đã xóa mềm quá retentionDays ngày, mỗi interval cho tới khi ctx bị hủy
+func schedulePurge(ctx context.Context, repo repository.NetworkAssetRepo, retentionDays int, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deletedBefore := time.Now().AddDate(0, 0, -retentionDays)
+			names, err := repo.PurgeNetworkAssets(ctx, deletedBefore, purgeActor)
+			if err != nil {
+				log.Errorf("scheduled purge failed: %v", err)
+				continue
+			}
+			if len(names) > 0 {
+				log.Infof("scheduled purge: %d network assets deleted before %s", len(names), deletedBefore.Format(time.RFC3339))
+			}
+		}
+	}
+}
+
 // helper: lấy env hoặc fallback sang default
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
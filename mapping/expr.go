@@ -0,0 +1,310 @@
+package mapping
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Biểu thức đơn giản trong mapping:
+//
+//	lower(Hostname) + ".corp.local"
+//	coalesce(IP, ip_addr)
+//	split(field("Full Name"), ".", 0)
+//
+// Tên field nguồn không phân biệt hoa thường, field có khoảng trắng dùng field("..."). Chuỗi đặt trong
+// nháy đơn hoặc kép, + là nối chuỗi.
+
+// node là một nút của biểu thức đã compile
+type node interface {
+	eval(env *env) string
+}
+
+type env struct {
+	source  map[string]string // key đã chuẩn hóa bằng fieldKey
+	lookups map[string]map[string]string
+}
+
+type literal string
+
+func (n literal) eval(*env) string { return string(n) }
+
+type fieldRef string
+
+func (n fieldRef) eval(e *env) string { return e.source[string(n)] }
+
+type concatNode []node
+
+func (n concatNode) eval(e *env) string {
+	var b strings.Builder
+	for _, part := range n {
+		b.WriteString(part.eval(e))
+	}
+	return b.String()
+}
+
+type call struct {
+	fn   func(e *env, args []string) string
+	args []node
+}
+
+func (n *call) eval(e *env) string {
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		args[i] = arg.eval(e)
+	}
+	return n.fn(e, args)
+}
+
+type function struct {
+	minArgs, maxArgs int // maxArgs -1 là không giới hạn
+	fn               func(e *env, args []string) string
+}
+
+var functions = map[string]function{
+	"lower": {1, 1, func(_ *env, a []string) string { return strings.ToLower(a[0]) }},
+	"upper": {1, 1, func(_ *env, a []string) string { return strings.ToUpper(a[0]) }},
+	"trim":  {1, 1, func(_ *env, a []string) string { return strings.TrimSpace(a[0]) }},
+	"concat": {1, -1, func(_ *env, a []string) string {
+		return strings.Join(a, "")
+	}},
+	"replace": {3, 3, func(_ *env, a []string) string { return strings.ReplaceAll(a[0], a[1], a[2]) }},
+	// split(s, sep, i) lấy phần thứ i (từ 0, âm là đếm từ cuối), rỗng nếu không có
+	"split": {3, 3, func(_ *env, a []string) string {
+		i, err := strconv.Atoi(strings.TrimSpace(a[2]))
+		if err != nil || a[1] == "" {
+			return ""
+		}
+		parts := strings.Split(a[0], a[1])
+		if i < 0 {
+			i += len(parts)
+		}
+		if i < 0 || i >= len(parts) {
+			return ""
+		}
+		return parts[i]
+	}},
+	// coalesce trả về giá trị khác rỗng đầu tiên
+	"coalesce": {1, -1, func(_ *env, a []string) string {
+		for _, v := range a {
+			if strings.TrimSpace(v) != "" {
+				return v
+			}
+		}
+		return ""
+	}},
+	"field": {1, 1, func(e *env, a []string) string { return e.source[fieldKey(a[0])] }},
+	// lookup(table, value) tra bảng lookups của mapping
+	"lookup": {2, 2, func(e *env, a []string) string {
+		v, _ := lookupValue(e.lookups[a[0]], a[1])
+		return v
+	}},
+}
+
+// compileExpr phân tích biểu thức, tên bảng của lookup() phải là chuỗi và có trong lookups.
+// Tên field nguồn được dùng gọi vào addSource, field() với tham số không phải chuỗi gọi addSource("").
+func compileExpr(s string, lookups map[string]map[string]string, addSource func(string)) (node, error) {
+	p := &exprParser{input: s, lookups: lookups, addSource: addSource}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	n, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", p.tok.text, p.tok.pos)
+	}
+	return n, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokString
+	tokNumber
+	tokIdent
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type exprParser struct {
+	input     string
+	pos       int
+	tok       token
+	lookups   map[string]map[string]string
+	addSource func(string)
+}
+
+func (p *exprParser) parseConcat() (node, error) {
+	first, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	parts := concatNode{first}
+	for p.tok.kind == tokPunct && p.tok.text == "+" {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, n)
+	}
+	if len(parts) == 1 {
+		return first, nil
+	}
+	return parts, nil
+}
+
+func (p *exprParser) parseTerm() (node, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokString || tok.kind == tokNumber:
+		return literal(tok.text), p.next()
+	case tok.kind == tokPunct && tok.text == "(":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case tok.kind == tokIdent:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokPunct || p.tok.text != "(" {
+			p.addSource(tok.text)
+			return fieldRef(fieldKey(tok.text)), nil
+		}
+		return p.parseCall(tok)
+	case tok.kind == tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos)
+}
+
+func (p *exprParser) parseCall(name token) (node, error) {
+	f, ok := functions[strings.ToLower(name.text)]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s", name.text)
+	}
+	if err := p.next(); err != nil { // bỏ qua "("
+		return nil, err
+	}
+
+	var args []node
+	if p.tok.kind != tokPunct || p.tok.text != ")" {
+		for {
+			arg, err := p.parseConcat()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.tok.kind != tokPunct || p.tok.text != "," {
+				break
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	if len(args) < f.minArgs || (f.maxArgs >= 0 && len(args) > f.maxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments to %s", name.text)
+	}
+	if strings.EqualFold(name.text, "field") {
+		name, _ := args[0].(literal)
+		p.addSource(string(name))
+	}
+	if strings.EqualFold(name.text, "lookup") {
+		table, ok := args[0].(literal)
+		if !ok {
+			return nil, fmt.Errorf("lookup table name must be a string")
+		}
+		if _, ok := p.lookups[string(table)]; !ok {
+			return nil, fmt.Errorf("unknown lookup table %s", table)
+		}
+	}
+	return &call{fn: f.fn, args: args}, nil
+}
+
+func (p *exprParser) expect(text string) error {
+	if p.tok.kind != tokPunct || p.tok.text != text {
+		if p.tok.kind == tokEOF {
+			return fmt.Errorf("expected %q at end of expression", text)
+		}
+		return fmt.Errorf("expected %q at %d", text, p.tok.pos)
+	}
+	return p.next()
+}
+
+// next đọc token tiếp theo vào p.tok
+func (p *exprParser) next() error {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.input) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return nil
+	}
+
+	c := p.input[p.pos]
+	switch {
+	case c == '"' || c == '\'':
+		var b strings.Builder
+		for p.pos++; p.pos < len(p.input); p.pos++ {
+			ch := p.input[p.pos]
+			if ch == '\\' && p.pos+1 < len(p.input) {
+				p.pos++
+				b.WriteByte(p.input[p.pos])
+				continue
+			}
+			if ch == c {
+				p.pos++
+				p.tok = token{kind: tokString, text: b.String(), pos: start}
+				return nil
+			}
+			b.WriteByte(ch)
+		}
+		return fmt.Errorf("unterminated string at %d", start)
+	case isDigit(c) || (c == '-' && p.pos+1 < len(p.input) && isDigit(p.input[p.pos+1])):
+		for p.pos++; p.pos < len(p.input) && (isDigit(p.input[p.pos]) || p.input[p.pos] == '.'); {
+			p.pos++
+		}
+		p.tok = token{kind: tokNumber, text: p.input[start:p.pos], pos: start}
+	case isIdentStart(c):
+		for p.pos < len(p.input) && (isIdentStart(p.input[p.pos]) || isDigit(p.input[p.pos])) {
+			p.pos++
+		}
+		p.tok = token{kind: tokIdent, text: p.input[start:p.pos], pos: start}
+	case strings.IndexByte("()+,", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokPunct, text: string(c), pos: start}
+	default:
+		return fmt.Errorf("unexpected character %q at %d", c, start)
+	}
+	return nil
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
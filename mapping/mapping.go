@@ -0,0 +1,420 @@
+package mapping
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping khai báo cách chuyển một bản ghi nguồn (tên cột tùy nguồn: IP, ip_addr, Address...) thành
+// các field của NetworkAsset. Ví dụ:
+//
+//	name: Inventory export
+//	lookups:
+//	  protocol: {tcp: TCP, udp: UDP}
+//	fields:
+//	  name: Hostname                      # viết tắt của from: Hostname
+//	  address:
+//	    from: [IP, ip_addr, Address]      # lấy cột đầu tiên khác rỗng
+//	    required: true
+//	  protocol_type: {from: proto, lookup: protocol}
+//	  subnet_mask: {from: Network, regex: '/(\d+)$'}
+//	  dns_host_name: {expr: 'lower(Hostname) + ".corp.local"'}
+//	  dataset_id: {value: "2"}
+//	  address_type: {from: Family, default: IPv4}
+type Mapping struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Passthrough: cột nguồn trùng tên field NetworkAsset được giữ nguyên nếu không có rule nào ghi đè
+	Passthrough bool     `json:"passthrough"`
+	Fields      []string `json:"fields"` // field đích theo thứ tự trong file
+
+	rules   []rule
+	lookups map[string]map[string]string
+	sources []string // tên field nguồn được dùng, theo cách viết trong file
+	dynamic bool     // có field nguồn không biết trước (passthrough, field() với tham số không phải chuỗi)
+}
+
+// Error: file mapping sai, Errors là lỗi theo từng khóa trong file
+type Error struct {
+	Id     string
+	Errors []model.FieldError
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Field+": "+fe.Message)
+	}
+	return fmt.Sprintf("invalid mapping %s: %s", e.Id, strings.Join(msgs, "; "))
+}
+
+func (e *Error) Unwrap() error { return errors.MappingInvalid }
+
+// rule là một field đích đã compile. Giá trị được tính theo thứ tự: value | expr | from (mặc định là
+// field nguồn cùng tên với field đích), rồi regex, lookup, default, cuối cùng kiểm tra required.
+type rule struct {
+	target   string
+	from     []string // key đã chuẩn hóa
+	value    *string
+	expr     node
+	regex    *regexp.Regexp
+	lookup   map[string]string
+	def      *string
+	required bool
+}
+
+type document struct {
+	Name        string                       `yaml:"name"`
+	Description string                       `yaml:"description"`
+	Passthrough bool                         `yaml:"passthrough"`
+	Lookups     map[string]map[string]string `yaml:"lookups"`
+	Fields      yaml.Node                    `yaml:"fields"`
+}
+
+// fieldSpec là một khóa trong fields, dạng chuỗi là viết tắt của from
+type fieldSpec struct {
+	From     stringList `yaml:"from"`
+	Value    *string    `yaml:"value"`
+	Expr     string     `yaml:"expr"`
+	Regex    string     `yaml:"regex"`
+	Lookup   yaml.Node  `yaml:"lookup"`
+	Default  *string    `yaml:"default"`
+	Required bool       `yaml:"required"`
+}
+
+var specKeys = map[string]bool{
+	"from": true, "value": true, "expr": true, "regex": true, "lookup": true, "default": true, "required": true,
+}
+
+func (s *fieldSpec) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		s.From = stringList{n.Value}
+		return nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("expected a column name or a mapping")
+	}
+	// node.Decode không kiểm tra khóa lạ nên phải tự kiểm tra
+	for i := 0; i < len(n.Content); i += 2 {
+		if key := n.Content[i].Value; !specKeys[key] {
+			return fmt.Errorf("unknown key %q", key)
+		}
+	}
+	type plain fieldSpec
+	return n.Decode((*plain)(s))
+}
+
+// stringList nhận một chuỗi hoặc một danh sách chuỗi
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		*l = stringList{n.Value}
+		return nil
+	}
+	var list []string
+	if err := n.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// Parse đọc file mapping YAML, mọi lỗi được gom lại trong *Error
+func Parse(id string, data []byte) (*Mapping, error) {
+	var doc document
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil {
+		return nil, &Error{Id: id, Errors: []model.FieldError{{Field: "file", Message: yamlMessage(err)}}}
+	}
+
+	m := &Mapping{
+		Id:          id,
+		Name:        doc.Name,
+		Description: doc.Description,
+		Passthrough: doc.Passthrough,
+		lookups:     map[string]map[string]string{},
+		dynamic:     doc.Passthrough,
+	}
+	for name, table := range doc.Lookups {
+		m.lookups[name] = normalizeTable(table)
+	}
+
+	var errs []model.FieldError
+	if doc.Fields.Kind != yaml.MappingNode || len(doc.Fields.Content) == 0 {
+		if !doc.Passthrough {
+			errs = append(errs, model.FieldError{Field: "fields", Message: "at least one field is required"})
+		}
+		if doc.Fields.Kind != 0 && doc.Fields.Kind != yaml.MappingNode {
+			errs = append(errs, model.FieldError{Field: "fields", Message: "must be a mapping"})
+		}
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(doc.Fields.Content); i += 2 {
+		key, value := doc.Fields.Content[i].Value, doc.Fields.Content[i+1]
+		path := "fields." + key
+
+		f, ok := model.LookupImportField(key)
+		if !ok {
+			errs = append(errs, model.FieldError{Field: path, Message: "unknown or read-only field"})
+			continue
+		}
+		if seen[f.Name] {
+			errs = append(errs, model.FieldError{Field: path, Message: "duplicate field"})
+			continue
+		}
+		seen[f.Name] = true
+
+		var spec fieldSpec
+		if err := value.Decode(&spec); err != nil {
+			errs = append(errs, model.FieldError{Field: path, Message: yamlMessage(err)})
+			continue
+		}
+		r, err := m.compile(f.Name, spec)
+		if err != nil {
+			errs = append(errs, model.FieldError{Field: path, Message: err.Error()})
+			continue
+		}
+		m.rules = append(m.rules, r)
+		m.Fields = append(m.Fields, f.Name)
+	}
+
+	if len(errs) > 0 {
+		return nil, &Error{Id: id, Errors: errs}
+	}
+	return m, nil
+}
+
+func (m *Mapping) compile(target string, spec fieldSpec) (rule, error) {
+	r := rule{target: target, value: spec.Value, def: spec.Default, required: spec.Required}
+
+	sources := 0
+	if len(spec.From) > 0 {
+		sources++
+		for _, from := range spec.From {
+			if strings.TrimSpace(from) == "" {
+				return r, fmt.Errorf("from must not be empty")
+			}
+			r.from = append(r.from, fieldKey(from))
+			m.addSource(from)
+		}
+	}
+	if spec.Value != nil {
+		sources++
+	}
+	if spec.Expr != "" {
+		sources++
+		n, err := compileExpr(spec.Expr, m.lookups, m.addSource)
+		if err != nil {
+			return r, fmt.Errorf("invalid expr: %v", err)
+		}
+		r.expr = n
+	}
+	if sources > 1 {
+		return r, fmt.Errorf("only one of from, value and expr may be set")
+	}
+	if sources == 0 {
+		// Không khai báo nguồn: đọc field nguồn cùng tên, để default/lookup/regex áp lên giá trị có sẵn
+		r.from = []string{target}
+	}
+
+	if spec.Regex != "" {
+		re, err := regexp.Compile(spec.Regex)
+		if err != nil {
+			return r, fmt.Errorf("invalid regex: %v", err)
+		}
+		r.regex = re
+	}
+
+	switch spec.Lookup.Kind {
+	case 0:
+	case yaml.ScalarNode:
+		table, ok := m.lookups[spec.Lookup.Value]
+		if !ok {
+			return r, fmt.Errorf("unknown lookup table %s", spec.Lookup.Value)
+		}
+		r.lookup = table
+	case yaml.MappingNode:
+		var table map[string]string
+		if err := spec.Lookup.Decode(&table); err != nil {
+			return r, fmt.Errorf("invalid lookup: %s", yamlMessage(err))
+		}
+		r.lookup = normalizeTable(table)
+	default:
+		return r, fmt.Errorf("lookup must be a table name or a mapping")
+	}
+
+	return r, nil
+}
+
+// Apply chuyển một bản ghi nguồn thành giá trị NetworkAsset (key là tên json). Field chỉ có trong kết quả
+// khi cột nguồn tồn tại (kể cả rỗng, để import xóa được giá trị) hoặc rule sinh ra giá trị khác rỗng.
+func (m *Mapping) Apply(source map[string]string) (map[string]string, []model.FieldError) {
+	e := &env{source: make(map[string]string, len(source)), lookups: m.lookups}
+	for k, v := range source {
+		e.source[fieldKey(k)] = v
+	}
+
+	values := map[string]string{}
+	if m.Passthrough {
+		for k, v := range source {
+			if f, ok := model.LookupImportField(k); ok {
+				values[f.Name] = v
+			}
+		}
+	}
+
+	var errs []model.FieldError
+	for _, r := range m.rules {
+		v, present, err := r.apply(e)
+		if err != nil {
+			errs = append(errs, model.FieldError{Field: r.target, Message: err.Error()})
+			continue
+		}
+		if present {
+			values[r.target] = v
+		}
+	}
+	return values, errs
+}
+
+func (r *rule) apply(e *env) (string, bool, error) {
+	var v string
+	present := false
+	switch {
+	case r.value != nil:
+		v = *r.value
+	case r.expr != nil:
+		v = r.expr.eval(e)
+	default:
+		for _, from := range r.from {
+			s, ok := e.source[from]
+			if !ok {
+				continue
+			}
+			present = true
+			if strings.TrimSpace(s) != "" {
+				v = s
+				break
+			}
+		}
+	}
+	v = strings.TrimSpace(v)
+
+	if r.regex != nil && v != "" {
+		match := r.regex.FindStringSubmatch(v)
+		switch {
+		case match == nil:
+			v = ""
+		case len(match) > 1:
+			v = match[1]
+		default:
+			v = match[0]
+		}
+	}
+
+	if r.lookup != nil && v != "" {
+		mapped, ok := lookupValue(r.lookup, v)
+		if !ok {
+			return "", false, fmt.Errorf("no lookup value for %q", v)
+		}
+		v = mapped
+	}
+
+	if v == "" && r.def != nil {
+		v = *r.def
+	}
+	if v == "" && r.required {
+		return "", false, fmt.Errorf("is required")
+	}
+	return v, present || v != "", nil
+}
+
+// MustParse giống Parse nhưng panic nếu mapping sai, dùng cho mapping mặc định nhúng trong code
+func MustParse(id, data string) *Mapping {
+	m, err := Parse(id, []byte(data))
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m *Mapping) addSource(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		m.dynamic = true
+		return
+	}
+	for _, s := range m.sources {
+		if fieldKey(s) == fieldKey(name) {
+			return
+		}
+	}
+	m.sources = append(m.sources, name)
+}
+
+// Sources trả về các field nguồn mà mapping đọc, ok=false nếu không xác định được trước
+// (passthrough hoặc field() với tên tính lúc chạy)
+func (m *Mapping) Sources() ([]string, bool) {
+	return m.sources, !m.dynamic
+}
+
+// Has cho biết mapping có ghi field đích (tên json) hay không
+func (m *Mapping) Has(target string) bool {
+	for _, f := range m.Fields {
+		if f == target {
+			return true
+		}
+	}
+	return m.Passthrough
+}
+
+// fieldKey chuẩn hóa tên cột nguồn, so khớp không phân biệt hoa thường
+func fieldKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func normalizeTable(table map[string]string) map[string]string {
+	normalized := make(map[string]string, len(table))
+	for k, v := range table {
+		normalized[fieldKey(k)] = v
+	}
+	return normalized
+}
+
+// lookupValue tra bảng không phân biệt hoa thường, "*" là giá trị cho mọi khóa không có trong bảng
+func lookupValue(table map[string]string, v string) (string, bool) {
+	if mapped, ok := table[fieldKey(v)]; ok {
+		return mapped, true
+	}
+	mapped, ok := table["*"]
+	return mapped, ok
+}
+
+func yamlMessage(err error) string {
+	if err == io.EOF {
+		return "empty file"
+	}
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		msgs := make([]string, 0, len(typeErr.Errors))
+		for _, msg := range typeErr.Errors {
+			// "field x not found in type mapping.document": tên type Go không có ý nghĩa với người viết file
+			if i := strings.Index(msg, " in type "); i >= 0 {
+				msg = msg[:i]
+			}
+			msgs = append(msgs, msg)
+		}
+		return strings.Join(msgs, "; ")
+	}
+	return strings.TrimPrefix(err.Error(), "yaml: ")
+}
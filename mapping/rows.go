@@ -0,0 +1,102 @@
+package mapping
+
+import (
+	"net/netip"
+
+	"github.com/sllpklls/template-backend-go/ipaddr"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// ApplyRows áp mapping cho từng dòng. overlay=true dùng cho nguồn đã có field NetworkAsset (zone, dhcp):
+// kết quả mapping ghi đè lên giá trị của dòng nguồn thay vì thay thế cả dòng. Dòng lỗi không có
+// trong kết quả mà được trả về dạng ImportRowResult failed.
+func (m *Mapping) ApplyRows(rows []model.ImportRow, overlay bool) ([]model.ImportRow, []model.ImportRowResult) {
+	mapped := make([]model.ImportRow, 0, len(rows))
+	var failed []model.ImportRowResult
+	for _, row := range rows {
+		values, errs := m.Apply(row.Values)
+		if overlay {
+			values = merge(row.Values, values)
+		}
+		if len(errs) > 0 {
+			failed = append(failed, failedRow(row.Row, values, row.Values, errs))
+			continue
+		}
+		mapped = append(mapped, model.ImportRow{Row: row.Row, Values: values})
+	}
+	return mapped, failed
+}
+
+// ApplyHosts áp mapping cho host tìm thấy khi scan. Bản ghi nguồn gồm address, dns_host_name,
+// mac_address, address_type; address, dns_host_name, mac_address của kết quả cập nhật lại host,
+// các field khác được ghi vào host.Values.
+func (m *Mapping) ApplyHosts(hosts []model.DiscoveredHost) ([]model.DiscoveredHost, []model.ImportRowResult) {
+	mapped := make([]model.DiscoveredHost, 0, len(hosts))
+	var failed []model.ImportRowResult
+	for _, host := range hosts {
+		source := HostRecord(host)
+		values, errs := m.Apply(source)
+		values = merge(source, values)
+		if values["address"] == "" {
+			errs = append(errs, model.FieldError{Field: "address", Message: "is required"})
+		}
+		if len(errs) > 0 {
+			failed = append(failed, failedRow(host.Row, values, source, errs))
+			continue
+		}
+
+		host.Address = values["address"]
+		host.Hostname = values["dns_host_name"]
+		host.MAC = values["mac_address"]
+		delete(values, "address")
+		delete(values, "dns_host_name")
+		delete(values, "mac_address")
+		delete(values, "address_type") // tính lại từ địa chỉ khi import
+		delete(values, "name")         // tên do import gán theo địa chỉ
+		if len(values) > 0 {
+			host.Values = values
+		}
+		mapped = append(mapped, host)
+	}
+	return mapped, failed
+}
+
+// HostRecord là bản ghi nguồn của một host scan khi áp mapping
+func HostRecord(host model.DiscoveredHost) map[string]string {
+	record := map[string]string{
+		"address":       host.Address,
+		"dns_host_name": host.Hostname,
+		"mac_address":   host.MAC,
+	}
+	if family := addressFamily(host.Address); family != "" {
+		record["address_type"] = family
+	}
+	return record
+}
+
+func merge(base, override map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+func failedRow(row int, values, source map[string]string, errs []model.FieldError) model.ImportRowResult {
+	name := values["name"]
+	if name == "" {
+		name = source["name"]
+	}
+	return model.ImportRowResult{Row: row, Name: name, Status: model.ImportFailed, Message: "mapping failed", Errors: errs}
+}
+
+func addressFamily(address string) string {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return ""
+	}
+	return ipaddr.Family(addr)
+}
@@ -0,0 +1,76 @@
+package mapping
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sllpklls/template-backend-go/errors"
+)
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Store đọc các file mapping <id>.yaml (hoặc .yml) trong Dir. File được đọc lại mỗi lần Get
+// nên sửa mapping không cần khởi động lại service.
+type Store struct {
+	Dir string
+}
+
+// Summary là thông tin rút gọn của một file mapping, Error khác rỗng nếu file không hợp lệ
+type Summary struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Get đọc và kiểm tra mapping, errors.MappingNotFound nếu không có file
+func (s *Store) Get(id string) (*Mapping, error) {
+	if !idPattern.MatchString(id) {
+		return nil, errors.MappingNotFound
+	}
+	for _, ext := range []string{".yaml", ".yml"} {
+		data, err := os.ReadFile(filepath.Join(s.Dir, id+ext))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return Parse(id, data)
+	}
+	return nil, errors.MappingNotFound
+}
+
+// List trả về mọi mapping trong Dir theo thứ tự id, thư mục không tồn tại coi như rỗng
+func (s *Store) List() ([]Summary, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return []Summary{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []Summary{}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		id := strings.TrimSuffix(entry.Name(), ext)
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") || !idPattern.MatchString(id) || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		m, err := s.Get(id)
+		if err != nil {
+			summaries = append(summaries, Summary{Id: id, Error: err.Error()})
+			continue
+		}
+		summaries = append(summaries, Summary{Id: id, Name: m.Name, Description: m.Description})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Id < summaries[j].Id })
+	return summaries, nil
+}
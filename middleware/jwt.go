@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"net/http"
+
+	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/sllpklls/template-backend-go/model"
@@ -15,3 +18,25 @@ func JWTMiddleware() echo.MiddlewareFunc {
 	}
 	return middleware.JWTWithConfig(config)
 }
+
+// RequireRole chỉ cho phép user có một trong các role, phải đặt sau JWTMiddleware
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token, ok := c.Get("user").(*jwt.Token); ok {
+				if claims, ok := token.Claims.(*model.JwtCustomClaims); ok {
+					for _, role := range roles {
+						if claims.Role == role {
+							return next(c)
+						}
+					}
+				}
+			}
+			return c.JSON(http.StatusForbidden, model.Response{
+				StatusCode: http.StatusForbidden,
+				Message:    "Không có quyền thực hiện thao tác này",
+				Data:       nil,
+			})
+		}
+	}
+}
@@ -0,0 +1,40 @@
+-- +migrate Up
+CREATE TABLE Subnets (
+    Id BIGSERIAL PRIMARY KEY,
+    Cidr CIDR NOT NULL,
+    Name VARCHAR(100) NOT NULL DEFAULT '',
+    Description TEXT NOT NULL DEFAULT '',
+    Gateway INET,
+    ParentId BIGINT REFERENCES Subnets (Id) ON DELETE SET NULL, -- subnet nhỏ nhất chứa subnet này, tự tính
+    DatasetId INT NOT NULL DEFAULT 0,
+    Owner VARCHAR(100) NOT NULL DEFAULT '',
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ModifiedDate TIMESTAMPTZ,
+    LastModifiedBy VARCHAR(100) NOT NULL DEFAULT '',
+    CONSTRAINT subnets_gateway_check CHECK (Gateway IS NULL OR Gateway << Cidr)
+);
+CREATE UNIQUE INDEX subnets_cidr_key ON Subnets (Cidr);
+CREATE INDEX subnets_cidr_idx ON Subnets USING GIST (Cidr inet_ops);
+CREATE INDEX subnets_parentid_idx ON Subnets (ParentId);
+
+-- Dải địa chỉ giữ lại trong subnet (không cấp phát)
+CREATE TABLE SubnetReservations (
+    Id BIGSERIAL PRIMARY KEY,
+    SubnetId BIGINT NOT NULL REFERENCES Subnets (Id) ON DELETE CASCADE,
+    StartAddress INET NOT NULL,
+    EndAddress INET NOT NULL,
+    Description TEXT NOT NULL DEFAULT '',
+    CreatedBy VARCHAR(100) NOT NULL DEFAULT '',
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT subnetreservations_range_check CHECK (StartAddress <= EndAddress)
+);
+CREATE INDEX subnetreservations_subnetid_idx ON SubnetReservations (SubnetId);
+
+-- Asset gắn với subnet nhỏ nhất chứa địa chỉ của nó, tự tính khi ghi asset hoặc subnet
+ALTER TABLE NetworkAssets ADD COLUMN SubnetId BIGINT REFERENCES Subnets (Id) ON DELETE SET NULL;
+CREATE INDEX networkassets_subnetid_idx ON NetworkAssets (SubnetId);
+
+-- +migrate Down
+ALTER TABLE NetworkAssets DROP COLUMN SubnetId;
+DROP TABLE SubnetReservations;
+DROP TABLE Subnets;
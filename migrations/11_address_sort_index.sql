@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Index GiST dùng cho tìm kiếm theo CIDR (<<=), btree dùng cho sắp xếp và tìm theo dải địa chỉ
+CREATE INDEX networkassets_address_sort_idx ON NetworkAssets (Address);
+
+-- +migrate Down
+DROP INDEX networkassets_address_sort_idx;
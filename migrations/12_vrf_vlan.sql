@@ -0,0 +1,48 @@
+-- +migrate Up
+CREATE TABLE Vrfs (
+    Id BIGSERIAL PRIMARY KEY,
+    Name VARCHAR(100) NOT NULL,
+    RouteDistinguisher VARCHAR(50) NOT NULL DEFAULT '',
+    Description TEXT NOT NULL DEFAULT '',
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ModifiedDate TIMESTAMPTZ,
+    LastModifiedBy VARCHAR(100) NOT NULL DEFAULT ''
+);
+CREATE UNIQUE INDEX vrfs_name_key ON Vrfs (Name);
+
+-- VLAN định danh bằng VLAN ID (802.1Q), có thể gắn với một VRF
+CREATE TABLE Vlans (
+    VlanId INT PRIMARY KEY CHECK (VlanId BETWEEN 1 AND 4094),
+    Name VARCHAR(100) NOT NULL DEFAULT '',
+    Description TEXT NOT NULL DEFAULT '',
+    VrfId BIGINT REFERENCES Vrfs (Id),
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ModifiedDate TIMESTAMPTZ,
+    LastModifiedBy VARCHAR(100) NOT NULL DEFAULT ''
+);
+CREATE INDEX vlans_vrfid_idx ON Vlans (VrfId);
+
+-- VrfId NULL là bảng định tuyến global
+ALTER TABLE NetworkAssets ADD COLUMN VrfId BIGINT REFERENCES Vrfs (Id);
+ALTER TABLE NetworkAssets ADD COLUMN VlanId INT REFERENCES Vlans (VlanId);
+CREATE INDEX networkassets_vrfid_idx ON NetworkAssets (VrfId);
+CREATE INDEX networkassets_vlanid_idx ON NetworkAssets (VlanId);
+
+ALTER TABLE Subnets ADD COLUMN VrfId BIGINT REFERENCES Vrfs (Id);
+ALTER TABLE Subnets ADD COLUMN VlanId INT REFERENCES Vlans (VlanId);
+CREATE INDEX subnets_vlanid_idx ON Subnets (VlanId);
+
+-- Cùng một CIDR được phép tồn tại ở nhiều VRF
+DROP INDEX subnets_cidr_key;
+CREATE UNIQUE INDEX subnets_vrf_cidr_key ON Subnets (COALESCE(VrfId, 0), Cidr);
+
+-- +migrate Down
+-- Chỉ rollback được khi không còn CIDR trùng giữa các VRF
+DROP INDEX subnets_vrf_cidr_key;
+CREATE UNIQUE INDEX subnets_cidr_key ON Subnets (Cidr);
+ALTER TABLE Subnets DROP COLUMN VlanId;
+ALTER TABLE Subnets DROP COLUMN VrfId;
+ALTER TABLE NetworkAssets DROP COLUMN VlanId;
+ALTER TABLE NetworkAssets DROP COLUMN VrfId;
+DROP TABLE Vlans;
+DROP TABLE Vrfs;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Tìm các endpoint IPv4/IPv6 của cùng hệ thống theo SystemName
+CREATE INDEX networkassets_systemname_idx ON NetworkAssets (lower(SystemName)) WHERE SystemName <> '';
+
+-- +migrate Down
+DROP INDEX networkassets_systemname_idx;
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Kết quả kiểm tra DNS mới nhất của mỗi asset, bị xóa theo khi asset bị purge
+CREATE TABLE DNSCheckResults (
+    AssetName VARCHAR(50) PRIMARY KEY REFERENCES NetworkAssets (Name) ON DELETE CASCADE,
+    DNSHostName VARCHAR(100) NOT NULL DEFAULT '',
+    Address VARCHAR(50) NOT NULL DEFAULT '',
+    MarkAsDeleted BOOLEAN NOT NULL DEFAULT false,
+    Status VARCHAR(20) NOT NULL,
+    Issues JSONB NOT NULL DEFAULT '[]',
+    ForwardAddresses JSONB NOT NULL DEFAULT '[]',
+    ReverseNames JSONB NOT NULL DEFAULT '[]',
+    CheckedAt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX dnscheckresults_status_idx ON DNSCheckResults (Status);
+
+-- +migrate Down
+DROP TABLE DNSCheckResults;
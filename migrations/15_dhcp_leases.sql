@@ -0,0 +1,15 @@
+-- +migrate Up
+-- Thông tin lease của asset import từ DHCP (ISC dhcpd, Kea). LeaseState rỗng là asset không
+-- đến từ DHCP; lease hết hạn được chuyển sang inactive thay vì bị xóa.
+ALTER TABLE NetworkAssets ADD COLUMN MacAddress VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE NetworkAssets ADD COLUMN LeaseStart TIMESTAMPTZ;
+ALTER TABLE NetworkAssets ADD COLUMN LeaseEnd TIMESTAMPTZ;
+ALTER TABLE NetworkAssets ADD COLUMN LeaseState VARCHAR(20) NOT NULL DEFAULT '';
+CREATE INDEX networkassets_macaddress_idx ON NetworkAssets (MacAddress) WHERE MacAddress <> '';
+CREATE INDEX networkassets_leasestate_idx ON NetworkAssets (DatasetId, LeaseState) WHERE LeaseState <> '';
+
+-- +migrate Down
+ALTER TABLE NetworkAssets DROP COLUMN LeaseState;
+ALTER TABLE NetworkAssets DROP COLUMN LeaseEnd;
+ALTER TABLE NetworkAssets DROP COLUMN LeaseStart;
+ALTER TABLE NetworkAssets DROP COLUMN MacAddress;
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Lần cuối asset được phát hiện bởi scan (nmap), không tính là thay đổi trong lịch sử
+ALTER TABLE NetworkAssets ADD COLUMN LastSeen TIMESTAMPTZ;
+
+-- Port phát hiện được trên asset, mỗi (protocol, port) một dòng, giữ lại cả port không còn thấy
+-- để biết lần cuối port mở
+CREATE TABLE NetworkAssetPorts (
+    AssetName VARCHAR(50) NOT NULL REFERENCES NetworkAssets (Name) ON DELETE CASCADE,
+    Protocol VARCHAR(10) NOT NULL,
+    Port INT NOT NULL CHECK (Port BETWEEN 0 AND 65535),
+    State VARCHAR(20) NOT NULL DEFAULT '',
+    Service VARCHAR(100) NOT NULL DEFAULT '',
+    Product VARCHAR(255) NOT NULL DEFAULT '',
+    Version VARCHAR(100) NOT NULL DEFAULT '',
+    FirstSeen TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    LastSeen TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (AssetName, Protocol, Port)
+);
+
+-- +migrate Down
+DROP TABLE NetworkAssetPorts;
+ALTER TABLE NetworkAssets DROP COLUMN LastSeen;
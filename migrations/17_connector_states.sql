@@ -0,0 +1,11 @@
+-- +migrate Up
+-- Trạng thái đồng bộ tăng dần của các connector: HighWaterMark là ModifiedDate lớn nhất đã ghi thành công
+CREATE TABLE ConnectorStates (
+    Name VARCHAR(100) PRIMARY KEY,
+    HighWaterMark TIMESTAMPTZ,
+    LastRunAt TIMESTAMPTZ,
+    LastError TEXT NOT NULL DEFAULT ''
+);
+
+-- +migrate Down
+DROP TABLE ConnectorStates;
@@ -0,0 +1,34 @@
+-- +migrate Up
+CREATE TABLE EtlJobs (
+    Id BIGSERIAL PRIMARY KEY,
+    Name VARCHAR(100) NOT NULL UNIQUE,
+    SourceType VARCHAR(50) NOT NULL,
+    Config JSONB NOT NULL DEFAULT '{}',
+    Schedule VARCHAR(100) NOT NULL DEFAULT '',
+    TargetDatasetId INT NOT NULL,
+    Disabled BOOLEAN NOT NULL DEFAULT false,
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ModifiedDate TIMESTAMPTZ
+);
+
+CREATE TABLE EtlRuns (
+    Id BIGSERIAL PRIMARY KEY,
+    JobId BIGINT NOT NULL REFERENCES EtlJobs (Id) ON DELETE CASCADE,
+    Status VARCHAR(20) NOT NULL,
+    TriggeredBy VARCHAR(100) NOT NULL DEFAULT '',
+    StartedAt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FinishedAt TIMESTAMPTZ,
+    Read INT NOT NULL DEFAULT 0,
+    Inserted INT NOT NULL DEFAULT 0,
+    Updated INT NOT NULL DEFAULT 0,
+    Skipped INT NOT NULL DEFAULT 0,
+    Deleted INT NOT NULL DEFAULT 0,
+    Failed INT NOT NULL DEFAULT 0,
+    ErrorMessage TEXT NOT NULL DEFAULT '',
+    ErrorLog JSONB NOT NULL DEFAULT '[]'
+);
+CREATE INDEX etlruns_jobid_idx ON EtlRuns (JobId, StartedAt DESC);
+
+-- +migrate Down
+DROP TABLE EtlRuns;
+DROP TABLE EtlJobs;
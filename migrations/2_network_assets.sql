@@ -0,0 +1,45 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS NetworkAssets (
+    Id BIGSERIAL PRIMARY KEY,
+    Name VARCHAR(50) NOT NULL,                    -- Tên IP
+    SystemName VARCHAR(100) NOT NULL DEFAULT '',  -- Tên hệ thống hoặc hostname
+    Address VARCHAR(50) NOT NULL DEFAULT '',      -- Địa chỉ IP
+    ShortDescription VARCHAR(255) NOT NULL DEFAULT '',
+    SubnetMask VARCHAR(50) NOT NULL DEFAULT '',
+    ProtocolType VARCHAR(20) NOT NULL DEFAULT '', -- TCP, UDP, ICMP...
+    Description TEXT NOT NULL DEFAULT '',
+    AddressType VARCHAR(50) NOT NULL DEFAULT '',  -- IPv4, IPv6
+    DNSHostName VARCHAR(100) NOT NULL DEFAULT '',
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    DatasetId INT NOT NULL DEFAULT 0,
+    ModifiedDate TIMESTAMPTZ,
+    LastModifiedBy VARCHAR(100) NOT NULL DEFAULT '',
+    InstanceId VARCHAR(100) NOT NULL DEFAULT '',
+    RequestId VARCHAR(100) NOT NULL DEFAULT ''
+);
+
+-- Bảng tạo tay từ command.sql cũ không có khóa chính
+ALTER TABLE NetworkAssets ADD COLUMN IF NOT EXISTS Id BIGSERIAL;
+-- +migrate StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conrelid = 'networkassets'::regclass AND contype = 'p'
+    ) THEN
+        ALTER TABLE NetworkAssets ADD PRIMARY KEY (Id);
+    END IF;
+END
+$$;
+-- +migrate StatementEnd
+ALTER TABLE NetworkAssets ALTER COLUMN Name SET NOT NULL;
+ALTER TABLE NetworkAssets ALTER COLUMN CreateDate SET DEFAULT NOW();
+
+CREATE UNIQUE INDEX IF NOT EXISTS networkassets_name_key ON NetworkAssets (Name);
+CREATE UNIQUE INDEX IF NOT EXISTS networkassets_instanceid_key ON NetworkAssets (InstanceId) WHERE InstanceId <> '';
+CREATE INDEX IF NOT EXISTS networkassets_dnshostname_idx ON NetworkAssets (DNSHostName);
+CREATE INDEX IF NOT EXISTS networkassets_address_idx ON NetworkAssets (Address);
+CREATE INDEX IF NOT EXISTS networkassets_createdate_idx ON NetworkAssets (CreateDate DESC);
+
+-- +migrate Down
+DROP TABLE NetworkAssets;
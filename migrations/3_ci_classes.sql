@@ -0,0 +1,81 @@
+-- +migrate Up
+-- Bảng cho các CI class đăng ký trong model.NewDefaultCIRegistry
+CREATE TABLE ComputerSystems (
+    Id BIGSERIAL PRIMARY KEY,
+    Name VARCHAR(100) NOT NULL UNIQUE,
+    HostName VARCHAR(100) NOT NULL DEFAULT '',
+    Domain VARCHAR(100) NOT NULL DEFAULT '',
+    ShortDescription VARCHAR(255) NOT NULL DEFAULT '',
+    Description TEXT NOT NULL DEFAULT '',
+    ManufacturerName VARCHAR(100) NOT NULL DEFAULT '',
+    Model VARCHAR(100) NOT NULL DEFAULT '',
+    SerialNumber VARCHAR(100) NOT NULL DEFAULT '',
+    OperatingSystem VARCHAR(100) NOT NULL DEFAULT '',
+    CPUCount INT,
+    MemoryMB INT,
+    IsVirtual BOOLEAN,
+    DatasetId INT NOT NULL DEFAULT 0,
+    InstanceId VARCHAR(100) NOT NULL DEFAULT '',
+    RequestId VARCHAR(100) NOT NULL DEFAULT '',
+    LastModifiedBy VARCHAR(100) NOT NULL DEFAULT '',
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ModifiedDate TIMESTAMPTZ
+);
+CREATE INDEX computersystems_hostname_idx ON ComputerSystems (HostName);
+
+CREATE TABLE LANEndpoints (
+    Id BIGSERIAL PRIMARY KEY,
+    Name VARCHAR(100) NOT NULL UNIQUE,
+    MACAddress VARCHAR(50) NOT NULL DEFAULT '',
+    SystemName VARCHAR(100) NOT NULL DEFAULT '',
+    ShortDescription VARCHAR(255) NOT NULL DEFAULT '',
+    Description TEXT NOT NULL DEFAULT '',
+    ProtocolType VARCHAR(20) NOT NULL DEFAULT '',
+    SpeedMbps INT,
+    DatasetId INT NOT NULL DEFAULT 0,
+    InstanceId VARCHAR(100) NOT NULL DEFAULT '',
+    RequestId VARCHAR(100) NOT NULL DEFAULT '',
+    LastModifiedBy VARCHAR(100) NOT NULL DEFAULT '',
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ModifiedDate TIMESTAMPTZ
+);
+CREATE INDEX lanendpoints_macaddress_idx ON LANEndpoints (MACAddress);
+
+CREATE TABLE Applications (
+    Id BIGSERIAL PRIMARY KEY,
+    Name VARCHAR(100) NOT NULL UNIQUE,
+    ShortDescription VARCHAR(255) NOT NULL DEFAULT '',
+    Description TEXT NOT NULL DEFAULT '',
+    Version VARCHAR(50) NOT NULL DEFAULT '',
+    Vendor VARCHAR(100) NOT NULL DEFAULT '',
+    Owner VARCHAR(100) NOT NULL DEFAULT '',
+    Environment VARCHAR(50) NOT NULL DEFAULT '',
+    DatasetId INT NOT NULL DEFAULT 0,
+    InstanceId VARCHAR(100) NOT NULL DEFAULT '',
+    RequestId VARCHAR(100) NOT NULL DEFAULT '',
+    LastModifiedBy VARCHAR(100) NOT NULL DEFAULT '',
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ModifiedDate TIMESTAMPTZ
+);
+
+CREATE TABLE BusinessServices (
+    Id BIGSERIAL PRIMARY KEY,
+    Name VARCHAR(100) NOT NULL UNIQUE,
+    ShortDescription VARCHAR(255) NOT NULL DEFAULT '',
+    Description TEXT NOT NULL DEFAULT '',
+    Owner VARCHAR(100) NOT NULL DEFAULT '',
+    SupportGroup VARCHAR(100) NOT NULL DEFAULT '',
+    Criticality VARCHAR(20) NOT NULL DEFAULT '',
+    DatasetId INT NOT NULL DEFAULT 0,
+    InstanceId VARCHAR(100) NOT NULL DEFAULT '',
+    RequestId VARCHAR(100) NOT NULL DEFAULT '',
+    LastModifiedBy VARCHAR(100) NOT NULL DEFAULT '',
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ModifiedDate TIMESTAMPTZ
+);
+
+-- +migrate Down
+DROP TABLE BusinessServices;
+DROP TABLE Applications;
+DROP TABLE LANEndpoints;
+DROP TABLE ComputerSystems;
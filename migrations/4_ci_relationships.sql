@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE CIRelationships (
+    Id BIGSERIAL PRIMARY KEY,
+    Type VARCHAR(30) NOT NULL CHECK (Type IN ('HostedOn', 'DependsOn', 'MemberOf', 'ConnectedTo')),
+    SourceClass VARCHAR(50) NOT NULL,
+    SourceName VARCHAR(100) NOT NULL,
+    TargetClass VARCHAR(50) NOT NULL,
+    TargetName VARCHAR(100) NOT NULL,
+    Description TEXT NOT NULL DEFAULT '',
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CreatedBy VARCHAR(100) NOT NULL DEFAULT '',
+    UNIQUE (Type, SourceClass, SourceName, TargetClass, TargetName),
+    CHECK (SourceClass <> TargetClass OR SourceName <> TargetName)
+);
+CREATE INDEX cirelationships_source_idx ON CIRelationships (SourceClass, SourceName);
+CREATE INDEX cirelationships_target_idx ON CIRelationships (TargetClass, TargetName);
+
+-- +migrate Down
+DROP TABLE CIRelationships;
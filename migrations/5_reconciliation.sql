@@ -0,0 +1,38 @@
+-- +migrate Up
+ALTER TABLE NetworkAssets ADD COLUMN ReconciliationId VARCHAR(50) NOT NULL DEFAULT '';
+CREATE INDEX networkassets_reconciliationid_idx ON NetworkAssets (ReconciliationId) WHERE ReconciliationId <> '';
+CREATE INDEX networkassets_datasetid_idx ON NetworkAssets (DatasetId);
+
+CREATE TABLE ReconciliationJobs (
+    Id BIGSERIAL PRIMARY KEY,
+    Name VARCHAR(100) NOT NULL UNIQUE,
+    SourceDatasets JSONB NOT NULL,
+    TargetDatasetId INT NOT NULL,
+    IdentificationRules JSONB NOT NULL,
+    PrecedenceRules JSONB NOT NULL DEFAULT '{}',
+    CreateDate TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ModifiedDate TIMESTAMPTZ
+);
+
+CREATE TABLE ReconciliationRuns (
+    Id BIGSERIAL PRIMARY KEY,
+    JobId BIGINT NOT NULL REFERENCES ReconciliationJobs (Id) ON DELETE CASCADE,
+    Status VARCHAR(20) NOT NULL,
+    StartedAt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FinishedAt TIMESTAMPTZ,
+    Total INT NOT NULL DEFAULT 0,
+    Identified INT NOT NULL DEFAULT 0,
+    Merged INT NOT NULL DEFAULT 0,
+    Unmatched INT NOT NULL DEFAULT 0,
+    Created INT NOT NULL DEFAULT 0,
+    Updated INT NOT NULL DEFAULT 0,
+    ErrorMessage TEXT NOT NULL DEFAULT '',
+    TriggeredBy VARCHAR(100) NOT NULL DEFAULT ''
+);
+CREATE INDEX reconciliationruns_jobid_idx ON ReconciliationRuns (JobId, StartedAt DESC);
+
+-- +migrate Down
+DROP TABLE ReconciliationRuns;
+DROP TABLE ReconciliationJobs;
+DROP INDEX networkassets_datasetid_idx;
+ALTER TABLE NetworkAssets DROP COLUMN ReconciliationId;
@@ -0,0 +1,11 @@
+-- +migrate Up
+ALTER TABLE NetworkAssets ADD COLUMN MarkAsDeleted BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE NetworkAssets ADD COLUMN DeletedBy VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE NetworkAssets ADD COLUMN DeletedAt TIMESTAMPTZ;
+CREATE INDEX networkassets_deletedat_idx ON NetworkAssets (DeletedAt) WHERE MarkAsDeleted;
+
+-- +migrate Down
+DROP INDEX networkassets_deletedat_idx;
+ALTER TABLE NetworkAssets DROP COLUMN DeletedAt;
+ALTER TABLE NetworkAssets DROP COLUMN DeletedBy;
+ALTER TABLE NetworkAssets DROP COLUMN MarkAsDeleted;
@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE NetworkAssetHistory (
+    Id BIGSERIAL PRIMARY KEY,
+    AssetName VARCHAR(50) NOT NULL,
+    Action VARCHAR(20) NOT NULL,
+    Changes JSONB NOT NULL DEFAULT '[]',
+    Snapshot JSONB,                 -- trạng thái bản ghi sau thay đổi (to_jsonb của dòng NetworkAssets)
+    Actor VARCHAR(100) NOT NULL DEFAULT '',
+    RequestId VARCHAR(100) NOT NULL DEFAULT '',
+    ChangedAt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX networkassethistory_assetname_idx ON NetworkAssetHistory (AssetName, ChangedAt DESC);
+
+-- Lịch sử chỉ được ghi thêm, không sửa/xóa
+-- +migrate StatementBegin
+CREATE FUNCTION networkassethistory_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'NetworkAssetHistory is append-only';
+END
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+CREATE TRIGGER networkassethistory_append_only
+    BEFORE UPDATE OR DELETE ON NetworkAssetHistory
+    FOR EACH ROW EXECUTE FUNCTION networkassethistory_append_only();
+
+-- +migrate Down
+DROP TABLE NetworkAssetHistory;
+DROP FUNCTION networkassethistory_append_only();
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- Asset có từ trước khi có lịch sử: ghi một snapshot gốc để truy vấn as_of
+INSERT INTO NetworkAssetHistory (assetname, action, changes, snapshot, actor, requestid, changedat)
+SELECT n.name, 'Baseline', '[]', to_jsonb(n), 'migration', n.requestid, COALESCE(n.modifieddate, n.createdate)
+FROM NetworkAssets n
+WHERE NOT EXISTS (SELECT 1 FROM NetworkAssetHistory h WHERE h.assetname = n.name);
+
+-- +migrate Down
+ALTER TABLE NetworkAssetHistory DISABLE TRIGGER networkassethistory_append_only;
+DELETE FROM NetworkAssetHistory WHERE action = 'Baseline';
+ALTER TABLE NetworkAssetHistory ENABLE TRIGGER networkassethistory_append_only;
@@ -0,0 +1,71 @@
+-- +migrate Up
+-- Address chuyển sang INET (chỉ lưu địa chỉ, prefix nằm ở SubnetMask dạng độ dài "24")
+-- +migrate StatementBegin
+CREATE FUNCTION networkassets_try_inet(value TEXT) RETURNS INET AS $$
+BEGIN
+    IF value IS NULL OR btrim(value) = '' THEN
+        RETURN NULL;
+    END IF;
+    RETURN host(btrim(value)::inet)::inet;
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Mask dạng 255.255.255.0 hoặc ffff:ffff:ffff:ffff:: -> độ dài prefix, giá trị không hợp lệ giữ nguyên
+CREATE FUNCTION pg_temp.mask_prefix(mask TEXT) RETURNS TEXT AS $$
+DECLARE
+    m TEXT := ltrim(btrim(mask), '/');
+    bits INT;
+BEGIN
+    IF m = '' OR m ~ '^[0-9]+$' THEN
+        RETURN m;
+    END IF;
+    SELECT s INTO bits FROM generate_series(0, 128) s
+    WHERE (family(m::inet) = 4 AND s <= 32 AND netmask(('0.0.0.0/' || s)::inet) = m::inet)
+       OR (family(m::inet) = 6 AND netmask(('::/' || s)::inet) = m::inet);
+    RETURN COALESCE(bits::text, mask);
+EXCEPTION WHEN others THEN
+    RETURN mask;
+END
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TEMP TABLE invalid_addresses ON COMMIT DROP AS
+SELECT name, address FROM NetworkAssets
+WHERE btrim(address) <> '' AND networkassets_try_inet(address) IS NULL;
+
+UPDATE NetworkAssets SET subnetmask = masklen(btrim(address)::inet)::text
+WHERE address LIKE '%/%' AND btrim(subnetmask) = '' AND networkassets_try_inet(address) IS NOT NULL;
+
+UPDATE NetworkAssets SET subnetmask = pg_temp.mask_prefix(subnetmask)
+WHERE btrim(subnetmask) <> '';
+
+UPDATE NetworkAssets
+SET addresstype = CASE family(networkassets_try_inet(address)) WHEN 4 THEN 'IPv4' ELSE 'IPv6' END
+WHERE networkassets_try_inet(address) IS NOT NULL;
+
+DROP INDEX IF EXISTS networkassets_address_idx;
+ALTER TABLE NetworkAssets ALTER COLUMN Address DROP DEFAULT;
+ALTER TABLE NetworkAssets ALTER COLUMN Address DROP NOT NULL;
+ALTER TABLE NetworkAssets ALTER COLUMN Address TYPE INET USING networkassets_try_inet(Address);
+ALTER TABLE NetworkAssets ADD CONSTRAINT networkassets_address_host_check
+    CHECK (masklen(Address) = CASE family(Address) WHEN 4 THEN 32 ELSE 128 END);
+CREATE INDEX networkassets_address_idx ON NetworkAssets USING GIST (Address inet_ops);
+
+-- Địa chỉ không đọc được bị xóa, giá trị cũ được giữ trong lịch sử
+INSERT INTO NetworkAssetHistory (assetname, action, changes, snapshot, actor)
+SELECT i.name, 'Update',
+       jsonb_build_array(jsonb_build_object('field', 'address', 'old', i.address, 'new', '')),
+       to_jsonb(n), 'migration'
+FROM invalid_addresses i
+JOIN NetworkAssets n ON n.name = i.name;
+
+-- +migrate Down
+ALTER TABLE NetworkAssets DROP CONSTRAINT networkassets_address_host_check;
+DROP INDEX networkassets_address_idx;
+ALTER TABLE NetworkAssets ALTER COLUMN Address TYPE VARCHAR(50) USING COALESCE(host(Address), '');
+ALTER TABLE NetworkAssets ALTER COLUMN Address SET DEFAULT '';
+ALTER TABLE NetworkAssets ALTER COLUMN Address SET NOT NULL;
+CREATE INDEX networkassets_address_idx ON NetworkAssets (Address);
+DROP FUNCTION networkassets_try_inet(TEXT);
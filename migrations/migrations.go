@@ -0,0 +1,8 @@
+package migrations
+
+import "embed"
+
+// FS chứa toàn bộ file migration *.sql, được nhúng vào binary
+//
+//go:embed *.sql
+var FS embed.FS
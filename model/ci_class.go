@@ -0,0 +1,277 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CIFieldType string
+
+const (
+	CIFieldString CIFieldType = "string"
+	CIFieldInt    CIFieldType = "int"
+	CIFieldBool   CIFieldType = "bool"
+	CIFieldTime   CIFieldType = "time"
+)
+
+// CIField mô tả một thuộc tính của CI class: tên JSON/query, cột trong bảng, kiểu dữ liệu
+type CIField struct {
+	Name      string      `json:"name"`
+	Column    string      `json:"column"`
+	Type      CIFieldType `json:"type"`
+	Required  bool        `json:"required"`
+	MaxLength int         `json:"max_length,omitempty"`
+	ReadOnly  bool        `json:"read_only,omitempty"`
+}
+
+// CIClass mô tả một loại CI: route /api/v1/{Path} và bảng {Table}
+type CIClass struct {
+	Name   string    `json:"name"`
+	Path   string    `json:"path"`
+	Table  string    `json:"table"`
+	Fields []CIField `json:"fields"`
+}
+
+// CIRecord là một bản ghi CI, key là CIField.Name
+type CIRecord map[string]interface{}
+
+type CIFilter struct {
+	Fields map[string]interface{} // giá trị đã chuyển theo kiểu của field, xem ParseFilter
+	Page   int
+	Limit  int
+}
+
+// Các thuộc tính hệ thống có ở mọi CI class
+var ciSystemFields = []CIField{
+	{Name: "name", Column: "name", Type: CIFieldString, Required: true, MaxLength: 100},
+	{Name: "dataset_id", Column: "datasetid", Type: CIFieldInt},
+	{Name: "instance_id", Column: "instanceid", Type: CIFieldString, MaxLength: 100},
+	{Name: "request_id", Column: "requestid", Type: CIFieldString, MaxLength: 100},
+	{Name: "last_modified_by", Column: "lastmodifiedby", Type: CIFieldString, MaxLength: 100},
+	{Name: "create_date", Column: "createdate", Type: CIFieldTime, ReadOnly: true},
+	{Name: "modified_date", Column: "modifieddate", Type: CIFieldTime, ReadOnly: true},
+}
+
+var identifierRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+func (c *CIClass) Field(name string) (CIField, bool) {
+	for _, f := range c.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return CIField{}, false
+}
+
+// Columns trả về danh sách cột theo thứ tự của Fields
+func (c *CIClass) Columns() []string {
+	columns := make([]string, 0, len(c.Fields))
+	for _, f := range c.Fields {
+		columns = append(columns, f.Column)
+	}
+	return columns
+}
+
+// Validate kiểm tra và chuyển đổi giá trị của record theo kiểu khai báo.
+// partial = true cho phép bỏ qua các field bắt buộc (dùng khi update).
+func (c *CIClass) Validate(record CIRecord, partial bool) (CIRecord, []FieldError) {
+	result := CIRecord{}
+	var errs []FieldError
+
+	for key := range record {
+		if _, ok := c.Field(key); !ok {
+			errs = append(errs, FieldError{Field: key, Message: "unknown field"})
+		}
+	}
+
+	for _, f := range c.Fields {
+		if f.ReadOnly {
+			continue
+		}
+		raw, ok := record[f.Name]
+		if !ok || raw == nil {
+			if f.Required && !partial {
+				errs = append(errs, FieldError{Field: f.Name, Message: "is required"})
+			}
+			continue
+		}
+
+		value, err := convertCIValue(f, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: f.Name, Message: err.Error()})
+			continue
+		}
+		if f.Required {
+			if s, ok := value.(string); ok && strings.TrimSpace(s) == "" {
+				errs = append(errs, FieldError{Field: f.Name, Message: "is required"})
+				continue
+			}
+		}
+		result[f.Name] = value
+	}
+
+	return result, errs
+}
+
+// ParseFilter chuyển giá trị filter từ query string (chuỗi) sang kiểu của từng field,
+// giá trị sai kiểu trả về dạng field error thay vì để DB báo lỗi
+func (c *CIClass) ParseFilter(query map[string]string) (map[string]interface{}, []FieldError) {
+	result := map[string]interface{}{}
+	var errs []FieldError
+
+	for _, f := range c.Fields {
+		s, ok := query[f.Name]
+		if !ok || s == "" {
+			continue
+		}
+
+		var raw interface{} = s
+		switch f.Type {
+		case CIFieldInt:
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				raw = n
+			}
+		case CIFieldBool:
+			if b, err := strconv.ParseBool(s); err == nil {
+				raw = b
+			}
+		}
+
+		value, err := convertCIValue(f, raw)
+		if err != nil {
+			errs = append(errs, FieldError{Field: f.Name, Message: err.Error()})
+			continue
+		}
+		result[f.Name] = value
+	}
+
+	return result, errs
+}
+
+func convertCIValue(f CIField, raw interface{}) (interface{}, error) {
+	switch f.Type {
+	case CIFieldString:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string")
+		}
+		if f.MaxLength > 0 && len(s) > f.MaxLength {
+			return nil, fmt.Errorf("must be at most %d characters", f.MaxLength)
+		}
+		return s, nil
+	case CIFieldInt:
+		switch v := raw.(type) {
+		case float64:
+			if v != float64(int64(v)) {
+				return nil, fmt.Errorf("must be an integer")
+			}
+			return int64(v), nil
+		case int:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		}
+		return nil, fmt.Errorf("must be an integer")
+	case CIFieldBool:
+		b, ok := raw.(bool)
+		if !ok {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return b, nil
+	case CIFieldTime:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be an RFC3339 timestamp")
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("must be an RFC3339 timestamp")
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", f.Type)
+}
+
+// CIRegistry lưu định nghĩa các CI class, dùng để sinh repository và route
+type CIRegistry struct {
+	classes []*CIClass
+	byName  map[string]*CIClass
+	byPath  map[string]*CIClass
+}
+
+func NewCIRegistry() *CIRegistry {
+	return &CIRegistry{
+		byName: map[string]*CIClass{},
+		byPath: map[string]*CIClass{},
+	}
+}
+
+// Register thêm class vào registry; các thuộc tính hệ thống được tự động thêm vào đầu Fields
+func (r *CIRegistry) Register(class CIClass) error {
+	if class.Name == "" || class.Path == "" {
+		return fmt.Errorf("class name and path are required")
+	}
+	if _, ok := r.byName[class.Name]; ok {
+		return fmt.Errorf("class %s already registered", class.Name)
+	}
+	if _, ok := r.byPath[class.Path]; ok {
+		return fmt.Errorf("path %s already registered", class.Path)
+	}
+	// Table và Column được ghép thẳng vào câu SQL nên chỉ chấp nhận identifier an toàn
+	if !identifierRegexp.MatchString(class.Table) {
+		return fmt.Errorf("class %s: invalid table name %q", class.Name, class.Table)
+	}
+
+	fields := append([]CIField{}, ciSystemFields...)
+	seen := map[string]bool{}
+	for _, f := range ciSystemFields {
+		seen[f.Name] = true
+		seen[f.Column] = true
+	}
+	for _, f := range class.Fields {
+		if !identifierRegexp.MatchString(f.Column) {
+			return fmt.Errorf("class %s: invalid column name %q", class.Name, f.Column)
+		}
+		if f.Name == "" || seen[f.Name] || seen[f.Column] {
+			return fmt.Errorf("class %s: duplicate or empty field %q", class.Name, f.Name)
+		}
+		switch f.Type {
+		case CIFieldString, CIFieldInt, CIFieldBool, CIFieldTime:
+		default:
+			return fmt.Errorf("class %s: field %s has unsupported type %q", class.Name, f.Name, f.Type)
+		}
+		seen[f.Name] = true
+		seen[f.Column] = true
+		fields = append(fields, f)
+	}
+	class.Fields = fields
+
+	c := &class
+	r.classes = append(r.classes, c)
+	r.byName[c.Name] = c
+	r.byPath[c.Path] = c
+	return nil
+}
+
+func (r *CIRegistry) MustRegister(class CIClass) {
+	if err := r.Register(class); err != nil {
+		panic(err)
+	}
+}
+
+func (r *CIRegistry) Get(name string) (*CIClass, bool) {
+	c, ok := r.byName[name]
+	return c, ok
+}
+
+func (r *CIRegistry) GetByPath(path string) (*CIClass, bool) {
+	c, ok := r.byPath[path]
+	return c, ok
+}
+
+func (r *CIRegistry) Classes() []*CIClass {
+	return r.classes
+}
@@ -0,0 +1,69 @@
+package model
+
+// NewDefaultCIRegistry đăng ký các CI class có sẵn. Thêm class mới: khai báo ở đây
+// và thêm migration tạo bảng tương ứng.
+func NewDefaultCIRegistry() *CIRegistry {
+	r := NewCIRegistry()
+
+	r.MustRegister(CIClass{
+		Name:  "ComputerSystem",
+		Path:  "computer-systems",
+		Table: "computersystems",
+		Fields: []CIField{
+			{Name: "host_name", Column: "hostname", Type: CIFieldString, Required: true, MaxLength: 100},
+			{Name: "domain", Column: "domain", Type: CIFieldString, MaxLength: 100},
+			{Name: "short_description", Column: "shortdescription", Type: CIFieldString, MaxLength: 255},
+			{Name: "description", Column: "description", Type: CIFieldString},
+			{Name: "manufacturer_name", Column: "manufacturername", Type: CIFieldString, MaxLength: 100},
+			{Name: "model", Column: "model", Type: CIFieldString, MaxLength: 100},
+			{Name: "serial_number", Column: "serialnumber", Type: CIFieldString, MaxLength: 100},
+			{Name: "operating_system", Column: "operatingsystem", Type: CIFieldString, MaxLength: 100},
+			{Name: "cpu_count", Column: "cpucount", Type: CIFieldInt},
+			{Name: "memory_mb", Column: "memorymb", Type: CIFieldInt},
+			{Name: "is_virtual", Column: "isvirtual", Type: CIFieldBool},
+		},
+	})
+
+	r.MustRegister(CIClass{
+		Name:  "LANEndpoint",
+		Path:  "lan-endpoints",
+		Table: "lanendpoints",
+		Fields: []CIField{
+			{Name: "mac_address", Column: "macaddress", Type: CIFieldString, Required: true, MaxLength: 50},
+			{Name: "system_name", Column: "systemname", Type: CIFieldString, MaxLength: 100},
+			{Name: "short_description", Column: "shortdescription", Type: CIFieldString, MaxLength: 255},
+			{Name: "description", Column: "description", Type: CIFieldString},
+			{Name: "protocol_type", Column: "protocoltype", Type: CIFieldString, MaxLength: 20},
+			{Name: "speed_mbps", Column: "speedmbps", Type: CIFieldInt},
+		},
+	})
+
+	r.MustRegister(CIClass{
+		Name:  "Application",
+		Path:  "applications",
+		Table: "applications",
+		Fields: []CIField{
+			{Name: "short_description", Column: "shortdescription", Type: CIFieldString, MaxLength: 255},
+			{Name: "description", Column: "description", Type: CIFieldString},
+			{Name: "version", Column: "version", Type: CIFieldString, MaxLength: 50},
+			{Name: "vendor", Column: "vendor", Type: CIFieldString, MaxLength: 100},
+			{Name: "owner", Column: "owner", Type: CIFieldString, MaxLength: 100},
+			{Name: "environment", Column: "environment", Type: CIFieldString, MaxLength: 50},
+		},
+	})
+
+	r.MustRegister(CIClass{
+		Name:  "BusinessService",
+		Path:  "business-services",
+		Table: "businessservices",
+		Fields: []CIField{
+			{Name: "short_description", Column: "shortdescription", Type: CIFieldString, MaxLength: 255},
+			{Name: "description", Column: "description", Type: CIFieldString},
+			{Name: "owner", Column: "owner", Type: CIFieldString, Required: true, MaxLength: 100},
+			{Name: "support_group", Column: "supportgroup", Type: CIFieldString, MaxLength: 100},
+			{Name: "criticality", Column: "criticality", Type: CIFieldString, MaxLength: 20},
+		},
+	})
+
+	return r
+}
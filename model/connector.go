@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// ConnectorState là trạng thái đồng bộ tăng dần của một connector
+type ConnectorState struct {
+	Name          string     `json:"name" db:"name"`
+	HighWaterMark *time.Time `json:"high_water_mark" db:"highwatermark"`
+	LastRunAt     *time.Time `json:"last_run_at" db:"lastrunat"`
+	LastError     string     `json:"last_error" db:"lasterror"`
+}
+
+// ConnectorRun là kết quả một lần chạy connector
+type ConnectorRun struct {
+	Name          string       `json:"name"`
+	StartedAt     time.Time    `json:"started_at"`
+	FinishedAt    time.Time    `json:"finished_at"`
+	Since         *time.Time   `json:"since"` // high-water mark trước khi chạy, nil là đồng bộ toàn bộ
+	HighWaterMark *time.Time   `json:"high_water_mark"`
+	Read          int          `json:"read"`
+	Created       int          `json:"created"`
+	Updated       int          `json:"updated"`
+	Skipped       int          `json:"skipped"`
+	Deleted       int          `json:"deleted"`
+	Failed        int          `json:"failed"`
+	Errors        []FieldError `json:"errors,omitempty"`
+}
+
+// AddReport cộng kết quả import của một trang vào lần chạy
+func (r *ConnectorRun) AddReport(report *ImportReport) {
+	r.Created += report.Created
+	r.Updated += report.Updated
+	r.Skipped += report.Skipped
+	r.Deleted += report.Deleted
+	r.Failed += report.Failed
+	for _, row := range report.Rows {
+		for _, e := range row.Errors {
+			r.Errors = append(r.Errors, FieldError{Field: row.Name + "." + e.Field, Message: e.Message})
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// Trạng thái kiểm tra DNS của một asset, theo mức độ nghiêm trọng tăng dần
+const (
+	DNSCheckOK       = "ok"
+	DNSCheckMissing  = "missing"  // thiếu bản ghi A/AAAA hoặc PTR
+	DNSCheckMismatch = "mismatch" // bản ghi trỏ tới địa chỉ/tên khác
+	DNSCheckStale    = "stale"    // asset đã xóa nhưng DNS vẫn còn bản ghi của nó
+	DNSCheckError    = "error"    // không tra cứu được (timeout, SERVFAIL...)
+)
+
+// Các loại vấn đề trong một lần kiểm tra
+const (
+	DNSIssueForwardMissing  = "forward_missing"
+	DNSIssueForwardMismatch = "forward_mismatch"
+	DNSIssueReverseMissing  = "reverse_missing"
+	DNSIssueReverseMismatch = "reverse_mismatch"
+	DNSIssueStaleForward    = "stale_forward"
+	DNSIssueStaleReverse    = "stale_reverse"
+	DNSIssueLookupError     = "lookup_error"
+)
+
+type DNSIssue struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type DNSIssues []DNSIssue
+
+// StringList là danh sách chuỗi lưu dạng JSONB
+type StringList []string
+
+// DNSCheckResult là kết quả kiểm tra mới nhất của một asset: DNSHostName tra A/AAAA phải ra Address,
+// Address tra PTR phải ra DNSHostName
+type DNSCheckResult struct {
+	AssetName        string     `json:"asset_name" db:"assetname"`
+	DNSHostName      string     `json:"dns_host_name" db:"dnshostname"`
+	Address          string     `json:"address" db:"address"`
+	MarkAsDeleted    bool       `json:"mark_as_deleted" db:"markasdeleted"`
+	Status           string     `json:"status" db:"status"`
+	Issues           DNSIssues  `json:"issues" db:"issues"`
+	ForwardAddresses StringList `json:"forward_addresses" db:"forwardaddresses"`
+	ReverseNames     StringList `json:"reverse_names" db:"reversenames"`
+	CheckedAt        time.Time  `json:"checked_at" db:"checkedat"`
+}
+
+// DNSCheckSummary là kết quả của một lần chạy, Results chỉ gồm các asset có vấn đề
+type DNSCheckSummary struct {
+	Total      int              `json:"total"`
+	OK         int              `json:"ok"`
+	Missing    int              `json:"missing"`
+	Mismatch   int              `json:"mismatch"`
+	Stale      int              `json:"stale"`
+	Error      int              `json:"error"`
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt time.Time        `json:"finished_at"`
+	Results    []DNSCheckResult `json:"results"`
+}
+
+// Add cộng một kết quả vào summary
+func (s *DNSCheckSummary) Add(result DNSCheckResult) {
+	s.Total++
+	switch result.Status {
+	case DNSCheckOK:
+		s.OK++
+		return
+	case DNSCheckMissing:
+		s.Missing++
+	case DNSCheckMismatch:
+		s.Mismatch++
+	case DNSCheckStale:
+		s.Stale++
+	case DNSCheckError:
+		s.Error++
+	}
+	s.Results = append(s.Results, result)
+}
+
+func (i DNSIssues) Value() (driver.Value, error)  { return jsonValue(i) }
+func (i *DNSIssues) Scan(src interface{}) error   { return jsonScan(src, i) }
+func (l StringList) Value() (driver.Value, error) { return jsonValue(l) }
+func (l *StringList) Scan(src interface{}) error  { return jsonScan(src, l) }
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+const (
+	EtlRunning = "Running"
+	EtlSuccess = "Success"
+	EtlFailed  = "Failed"
+)
+
+// Các loại nguồn của ETL job
+const (
+	EtlSourceCSV    = "csv"
+	EtlSourceZone   = "zone"
+	EtlSourceDHCP   = "dhcp"
+	EtlSourceNmap   = "nmap"
+	EtlSourceRemedy = "remedy"
+)
+
+// Số lỗi tối đa lưu trong ErrorLog của một lần chạy
+const maxEtlErrors = 1000
+
+// EtlConfig là config riêng của từng loại nguồn, lưu nguyên dạng JSON
+type EtlConfig json.RawMessage
+
+type EtlJob struct {
+	Id         int64     `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name" validate:"required"`
+	SourceType string    `json:"source_type" db:"sourcetype" validate:"required"`
+	Config     EtlConfig `json:"config" db:"config"`
+	// Schedule là biểu thức cron 5 trường ("0 2 * * *", "@every 15m"), rỗng là chỉ chạy qua API
+	Schedule        string     `json:"schedule" db:"schedule"`
+	TargetDatasetId int        `json:"target_dataset_id" db:"targetdatasetid" validate:"min=0"` // 0 là dataset mặc định
+	Disabled        bool       `json:"disabled" db:"disabled"`
+	CreateDate      time.Time  `json:"create_date" db:"createdate"`
+	ModifiedDate    *time.Time `json:"modified_date" db:"modifieddate"`
+}
+
+type EtlRun struct {
+	Id           int64       `json:"id" db:"id"`
+	JobId        int64       `json:"job_id" db:"jobid"`
+	Status       string      `json:"status" db:"status"`
+	TriggeredBy  string      `json:"triggered_by" db:"triggeredby"`
+	StartedAt    time.Time   `json:"started_at" db:"startedat"`
+	FinishedAt   *time.Time  `json:"finished_at" db:"finishedat"`
+	Read         int         `json:"read" db:"read"`
+	Inserted     int         `json:"inserted" db:"inserted"`
+	Updated      int         `json:"updated" db:"updated"`
+	Skipped      int         `json:"skipped" db:"skipped"`
+	Deleted      int         `json:"deleted" db:"deleted"`
+	Failed       int         `json:"failed" db:"failed"`
+	ErrorMessage string      `json:"error_message" db:"errormessage"`
+	ErrorLog     EtlErrorLog `json:"error_log" db:"errorlog"`
+}
+
+// EtlError là lỗi của một bản ghi, Row là vị trí trong dữ liệu nguồn
+type EtlError struct {
+	Row     int    `json:"row,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type EtlErrorLog []EtlError
+
+// AddError ghi lỗi vào ErrorLog, bỏ qua khi đã đủ maxEtlErrors (Failed vẫn được đếm)
+func (r *EtlRun) AddError(e EtlError) {
+	if len(r.ErrorLog) < maxEtlErrors {
+		r.ErrorLog = append(r.ErrorLog, e)
+	}
+}
+
+// AddReport cộng kết quả import vào lần chạy. Import không được commit (có dòng lỗi) thì chỉ
+// tính số dòng đọc và số dòng lỗi, vì không có gì được ghi.
+func (r *EtlRun) AddReport(report *ImportReport) {
+	for _, row := range report.Rows {
+		if row.Status != ImportDeleted && row.Status != ImportDeactivated {
+			r.Read++
+		}
+		if row.Status == ImportFailed {
+			for _, e := range row.Errors {
+				r.AddError(EtlError{Row: row.Row, Name: row.Name, Field: e.Field, Message: e.Message})
+			}
+		}
+	}
+	r.Failed += report.Failed
+	if !report.Committed {
+		return
+	}
+	r.Inserted += report.Created
+	r.Updated += report.Updated
+	r.Skipped += report.Skipped
+	r.Deleted += report.Deleted + report.Deactivated
+}
+
+// Decode đọc config vào v, field không có trong v là lỗi để bắt lỗi gõ sai tên
+func (c EtlConfig) Decode(v interface{}) error {
+	if len(c) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(c))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func (c EtlConfig) MarshalJSON() ([]byte, error) {
+	if len(c) == 0 {
+		return []byte("{}"), nil
+	}
+	return c, nil
+}
+
+func (c *EtlConfig) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*c = nil
+		return nil
+	}
+	*c = append((*c)[:0], data...)
+	return nil
+}
+
+func (c EtlConfig) Value() (driver.Value, error) {
+	if len(c) == 0 {
+		return "{}", nil
+	}
+	return string(c), nil
+}
+
+func (c *EtlConfig) Scan(src interface{}) error {
+	var raw json.RawMessage
+	if err := jsonScan(src, &raw); err != nil {
+		return err
+	}
+	*c = EtlConfig(raw)
+	return nil
+}
+
+func (l EtlErrorLog) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return jsonValue(l)
+}
+func (l *EtlErrorLog) Scan(src interface{}) error { return jsonScan(src, l) }
@@ -0,0 +1,25 @@
+package model
+
+const (
+	ImpactDownstream = "downstream" // các CI bị ảnh hưởng khi CI gốc gặp sự cố
+	ImpactUpstream   = "upstream"   // các CI mà CI gốc phụ thuộc vào
+)
+
+type ImpactedCI struct {
+	Class            string           `json:"class"`
+	Name             string           `json:"name"`
+	Address          string           `json:"address,omitempty"`
+	Distance         int              `json:"distance"`
+	RelationshipType RelationshipType `json:"relationship_type"`
+	Path             []string         `json:"path"`
+}
+
+type ImpactResult struct {
+	Class     string       `json:"class"`
+	Name      string       `json:"name"`
+	Direction string       `json:"direction"`
+	Depth     int          `json:"depth"`
+	Items     []ImpactedCI `json:"items"`
+	Cycles    [][]string   `json:"cycles,omitempty"`
+	Truncated bool         `json:"truncated"`
+}
@@ -0,0 +1,100 @@
+package model
+
+const (
+	ImportInsert         = "insert"          // chỉ tạo mới, bỏ qua asset đã tồn tại
+	ImportUpsert         = "upsert"          // tạo mới hoặc cập nhật các cột có trong file
+	ImportReplaceDataset = "replace-dataset" // upsert rồi xóa mềm asset của dataset không có trong file
+)
+
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+	ImportDeleted = "deleted"
+	// Asset DHCP không còn lease được chuyển sang inactive
+	ImportDeactivated = "deactivated"
+)
+
+// Các field do hệ thống quản lý, không nhận từ file import
+var importReadOnlyFields = map[string]bool{
+	"create_date":       true,
+	"modified_date":     true,
+	"reconciliation_id": true,
+	"mark_as_deleted":   true,
+	"deleted_by":        true,
+	"deleted_at":        true,
+	"subnet_id":         true,
+	"last_seen":         true,
+}
+
+func IsValidImportMode(mode string) bool {
+	return mode == ImportInsert || mode == ImportUpsert || mode == ImportReplaceDataset
+}
+
+// LookupImportField tìm field có thể import theo tên cột trong file
+func LookupImportField(name string) (NetworkAssetField, bool) {
+	f, ok := LookupNetworkAssetField(name)
+	if !ok || importReadOnlyFields[f.Name] {
+		return NetworkAssetField{}, false
+	}
+	return f, true
+}
+
+type ImportOptions struct {
+	Mode      string
+	DatasetId int    // gán cho dòng không có dataset_id, bắt buộc với replace-dataset
+	RequestId string // gán cho dòng không có request_id và cho các asset bị xóa
+	DryRun    bool
+	// Cho phép ghi địa chỉ trùng với asset khác đang hoạt động trong cùng dataset
+	AllowDuplicateAddress bool
+	// DeactivateMissing (chỉ import lease DHCP) chuyển asset DHCP của dataset không có trong lần import
+	// sang inactive; chỉ đúng khi lần import chứa mọi nguồn lease của dataset
+	DeactivateMissing bool
+}
+
+// ImportRow là một dòng dữ liệu: tên field json -> giá trị thô
+type ImportRow struct {
+	Row    int
+	Values map[string]string
+}
+
+type ImportRowResult struct {
+	Row     int          `json:"row,omitempty"`
+	Name    string       `json:"name"`
+	Status  string       `json:"status"`
+	Message string       `json:"message,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+type ImportReport struct {
+	Mode      string `json:"mode"`
+	DryRun    bool   `json:"dry_run"`
+	Committed bool   `json:"committed"`
+	Created   int    `json:"created"`
+	Updated   int    `json:"updated"`
+	Skipped   int    `json:"skipped"`
+	Failed    int    `json:"failed"`
+	Deleted   int    `json:"deleted"`
+	// Deactivated chỉ có ở import lease DHCP
+	Deactivated int               `json:"deactivated,omitempty"`
+	Rows        []ImportRowResult `json:"rows"`
+}
+
+func (r *ImportReport) Add(result ImportRowResult) {
+	switch result.Status {
+	case ImportCreated:
+		r.Created++
+	case ImportUpdated:
+		r.Updated++
+	case ImportSkipped:
+		r.Skipped++
+	case ImportFailed:
+		r.Failed++
+	case ImportDeleted:
+		r.Deleted++
+	case ImportDeactivated:
+		r.Deactivated++
+	}
+	r.Rows = append(r.Rows, result)
+}
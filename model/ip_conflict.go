@@ -0,0 +1,9 @@
+package model
+
+// IPConflict là một địa chỉ được nhiều asset đang hoạt động trong cùng dataset và VRF sử dụng
+type IPConflict struct {
+	Address   string             `json:"address"`
+	DatasetId int                `json:"dataset_id"`
+	VrfId     int                `json:"vrf_id"`
+	Assets    []NetworkAssetList `json:"assets"`
+}
@@ -0,0 +1,17 @@
+package model
+
+// MappingPreview là kết quả áp mapping cho file mẫu, Rows chỉ gồm tối đa limit dòng đầu
+type MappingPreview struct {
+	Mapping string              `json:"mapping"`
+	Format  string              `json:"format"`
+	Total   int                 `json:"total"`
+	Failed  int                 `json:"failed"`
+	Rows    []MappingPreviewRow `json:"rows"`
+}
+
+type MappingPreviewRow struct {
+	Row    int               `json:"row"`
+	Source map[string]string `json:"source"`
+	Values map[string]string `json:"values"`
+	Errors []FieldError      `json:"errors,omitempty"`
+}
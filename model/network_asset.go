@@ -1,6 +1,14 @@
 package model
 
-import "time"
+import (
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sllpklls/template-backend-go/ipaddr"
+)
 
 type NetworkAsset struct {
 	Name             string     `json:"name" db:"name"`
@@ -18,8 +26,27 @@ type NetworkAsset struct {
 	LastModifiedBy   string     `json:"last_modified_by" db:"lastmodifiedby"`
 	InstanceId       string     `json:"instance_id" db:"instanceid"`
 	RequestId        string     `json:"request_id" db:"requestid"`
+	ReconciliationId string     `json:"reconciliation_id" db:"reconciliationid"`
+	MarkAsDeleted    bool       `json:"mark_as_deleted" db:"markasdeleted"`
+	DeletedBy        string     `json:"deleted_by" db:"deletedby"`
+	DeletedAt        *time.Time `json:"deleted_at" db:"deletedat"`
+	SubnetId         int        `json:"subnet_id" db:"subnetid"` // subnet nhỏ nhất chứa Address, 0 nếu không có
+	VrfId            int        `json:"vrf_id" db:"vrfid"`       // 0 là bảng định tuyến global
+	VlanId           int        `json:"vlan_id" db:"vlanid"`     // VLAN ID (802.1Q), 0 nếu không có
+	MacAddress       string     `json:"mac_address" db:"macaddress"`
+	LeaseStart       *time.Time `json:"lease_start" db:"leasestart"`
+	LeaseEnd         *time.Time `json:"lease_end" db:"leaseend"`
+	LeaseState       string     `json:"lease_state" db:"leasestate"` // rỗng nếu asset không đến từ DHCP
+	LastSeen         *time.Time `json:"last_seen" db:"lastseen"`     // lần cuối được phát hiện khi scan
 }
 
+// Trạng thái lease DHCP của asset
+const (
+	LeaseActive   = "active"
+	LeaseInactive = "inactive" // lease đã hết hạn, được giải phóng hoặc không còn trong file lease
+	LeaseReserved = "reserved" // địa chỉ cố định (host declaration, reservation)
+)
+
 type NetworkAssetList struct {
 	Name             string    `json:"name" db:"name"`
 	SystemName       string    `json:"system_name" db:"systemname"`
@@ -29,14 +56,166 @@ type NetworkAssetList struct {
 	AddressType      string    `json:"address_type" db:"addresstype"`
 	DNSHostName      string    `json:"dns_host_name" db:"dnshostname"`
 	CreateDate       time.Time `json:"create_date" db:"createdate"`
+	MarkAsDeleted    bool      `json:"mark_as_deleted,omitempty" db:"markasdeleted"`
+	VrfId            int       `json:"vrf_id" db:"vrfid"`
+	VlanId           int       `json:"vlan_id" db:"vlanid"`
+}
+
+// NetworkAssetDetail là NetworkAsset kèm các địa chỉ họ khác (IPv4/IPv6) của cùng SystemName
+type NetworkAssetDetail struct {
+	NetworkAsset
+	DualStack []NetworkAssetList `json:"dual_stack"`
 }
+
 type NetworkAssetFilter struct {
-	Name         string `json:"name,omitempty" query:"name"`
-	Address      string `json:"address,omitempty" query:"address"`
-	ProtocolType string `json:"protocol_type,omitempty" query:"protocol_type"`
-	AddressType  string `json:"address_type,omitempty" query:"address_type"`
-	DnsHostname  string `json:"dns_host_name,omitempty" query:"dns_host_name"`
-	DatasetId    int    `json:"dataset_id,omitempty" query:"dataset_id"`
-	Page         int    `json:"page" query:"page"`
-	Limit        int    `json:"limit" query:"limit"`
+	Name           string    `json:"name,omitempty" query:"name"`
+	Address        string    `json:"address,omitempty" query:"address"`
+	ProtocolType   string    `json:"protocol_type,omitempty" query:"protocol_type"`
+	AddressType    string    `json:"address_type,omitempty" query:"address_type"`
+	DnsHostname    string    `json:"dns_host_name,omitempty" query:"dns_host_name"`
+	DatasetId      int       `json:"dataset_id,omitempty" query:"dataset_id"`
+	Vrf            string    `json:"vrf,omitempty" query:"vrf"` // tên VRF, "global" là bảng định tuyến global
+	VlanId         int       `json:"vlan_id,omitempty" query:"vlan_id"`
+	MacAddress     string    `json:"mac_address,omitempty" query:"mac_address"`
+	LeaseState     string    `json:"lease_state,omitempty" query:"lease_state"`
+	IncludeDeleted bool      `json:"include_deleted,omitempty" query:"include_deleted"` // hiển thị cả asset đã MarkAsDeleted
+	AsOf           time.Time `json:"as_of,omitempty" query:"as_of"`                     // trạng thái tại thời điểm (RFC3339), rỗng là hiện tại
+	IP             string    `json:"ip,omitempty" query:"ip"`                           // đúng địa chỉ
+	Within         string    `json:"within,omitempty" query:"within"`                   // địa chỉ nằm trong CIDR, vd 10.0.0.0/16
+	Range          string    `json:"range,omitempty" query:"range"`                     // dải địa chỉ, vd 192.168.1.10-192.168.1.50
+	Sort           string    `json:"sort,omitempty" query:"sort"`                       // address, -address, create_date, -create_date (mặc định)
+	Page           int       `json:"page" query:"page"`
+	Limit          int       `json:"limit" query:"limit"`
+}
+
+// Các giá trị của NetworkAssetFilter.Sort, dấu "-" là giảm dần
+const (
+	SortAddress        = "address"
+	SortAddressDesc    = "-address"
+	SortCreateDate     = "create_date"
+	SortCreateDateDesc = "-create_date"
+)
+
+// Validate kiểm tra và chuẩn hóa các filter theo địa chỉ IP và sort
+func (f *NetworkAssetFilter) Validate() []FieldError {
+	var errs []FieldError
+
+	// address vẫn là tìm chuỗi con, nhưng một địa chỉ đầy đủ được đưa về dạng chuẩn (IPv6 theo
+	// RFC 5952) trước khi so sánh: 2001:0db8:0000::1 tìm thấy 2001:db8::1
+	if addr, prefix, err := ipaddr.ParseAddress(f.Address); err == nil && prefix < 0 {
+		f.Address = addr.String()
+	}
+
+	if f.IP != "" {
+		addr, prefix, err := ipaddr.ParseAddress(f.IP)
+		if err != nil || prefix >= 0 {
+			errs = append(errs, FieldError{Field: "ip", Message: "must be a single IP address"})
+		} else {
+			f.IP = addr.String()
+		}
+	}
+
+	if f.Within != "" {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(f.Within))
+		if err != nil {
+			errs = append(errs, FieldError{Field: "within", Message: "must be a CIDR such as 10.0.0.0/16"})
+		} else {
+			f.Within = prefix.Masked().String()
+		}
+	}
+
+	if f.Range != "" {
+		r, err := ipaddr.ParseRange(f.Range)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "range", Message: err.Error()})
+		} else {
+			f.Range = r.String()
+		}
+	}
+
+	if f.MacAddress != "" {
+		mac, err := net.ParseMAC(strings.TrimSpace(f.MacAddress))
+		if err != nil {
+			errs = append(errs, FieldError{Field: "mac_address", Message: "must be a MAC address"})
+		} else {
+			f.MacAddress = mac.String()
+		}
+	}
+
+	switch f.LeaseState {
+	case "", LeaseActive, LeaseInactive, LeaseReserved:
+	default:
+		errs = append(errs, FieldError{Field: "lease_state", Message: "must be active, inactive or reserved"})
+	}
+
+	switch f.Sort {
+	case "", SortAddress, SortAddressDesc, SortCreateDate, SortCreateDateDesc:
+	default:
+		errs = append(errs, FieldError{Field: "sort", Message: "must be address, -address, create_date or -create_date"})
+	}
+
+	return errs
+}
+
+// Validate kiểm tra NetworkAsset trước khi ghi, trả về lỗi theo từng field.
+// Address, SubnetMask và AddressType được chuẩn hóa tại chỗ (xem NormalizeAddress).
+func (a *NetworkAsset) Validate() []FieldError {
+	var errs []FieldError
+	if strings.TrimSpace(a.Name) == "" {
+		errs = append(errs, FieldError{Field: "name", Message: "required"})
+	} else if len(a.Name) > 50 {
+		errs = append(errs, FieldError{Field: "name", Message: "must be at most 50 characters"})
+	}
+	if strings.TrimSpace(a.MacAddress) != "" {
+		mac, err := net.ParseMAC(strings.TrimSpace(a.MacAddress))
+		if err != nil {
+			errs = append(errs, FieldError{Field: "mac_address", Message: "must be a MAC address"})
+		} else {
+			a.MacAddress = mac.String()
+		}
+	}
+	switch a.LeaseState {
+	case "", LeaseActive, LeaseInactive, LeaseReserved:
+	default:
+		errs = append(errs, FieldError{Field: "lease_state", Message: "must be active, inactive or reserved"})
+	}
+	return append(errs, a.NormalizeAddress()...)
+}
+
+// NormalizeAddress đưa địa chỉ về dạng chuẩn: Address không kèm prefix, SubnetMask là độ dài
+// prefix ("24"), AddressType là IPv4/IPv6. Address dạng CIDR (10.0.0.5/24) được tách ra mask.
+func (a *NetworkAsset) NormalizeAddress() []FieldError {
+	var errs []FieldError
+
+	addr, prefix, err := ipaddr.ParseAddress(a.Address)
+	if err != nil {
+		return append(errs, FieldError{Field: "address", Message: err.Error()})
+	}
+	a.Address = addr.String()
+
+	if strings.TrimSpace(a.SubnetMask) != "" {
+		bits, err := ipaddr.ParseMask(a.SubnetMask, addr.Is4())
+		if err != nil {
+			errs = append(errs, FieldError{Field: "subnet_mask", Message: err.Error()})
+		} else if prefix >= 0 && bits != prefix {
+			errs = append(errs, FieldError{Field: "subnet_mask", Message: "does not match address prefix /" + strconv.Itoa(prefix)})
+		} else {
+			a.SubnetMask = strconv.Itoa(bits)
+		}
+	} else if prefix >= 0 {
+		a.SubnetMask = strconv.Itoa(prefix)
+	}
+
+	family := ipaddr.Family(addr)
+	if strings.TrimSpace(a.AddressType) == "" {
+		a.AddressType = family
+	} else if t := ipaddr.NormalizeFamily(a.AddressType); t == "" {
+		errs = append(errs, FieldError{Field: "address_type", Message: "must be IPv4 or IPv6"})
+	} else if t != family {
+		errs = append(errs, FieldError{Field: "address_type", Message: "does not match address " + a.Address})
+	} else {
+		a.AddressType = t
+	}
+
+	return errs
 }
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NetworkAssetField mô tả một field của NetworkAsset (tên json và cột db)
+type NetworkAssetField struct {
+	Name   string
+	Column string
+	index  int
+}
+
+var networkAssetFields []NetworkAssetField
+
+func init() {
+	t := reflect.TypeOf(NetworkAsset{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		column := strings.Split(f.Tag.Get("db"), ",")[0]
+		if name == "" || name == "-" || column == "" || column == "-" {
+			continue
+		}
+		networkAssetFields = append(networkAssetFields, NetworkAssetField{Name: name, Column: column, index: i})
+	}
+}
+
+// NetworkAssetFields trả về các field theo thứ tự khai báo trong struct
+func NetworkAssetFields() []NetworkAssetField {
+	return networkAssetFields
+}
+
+// LookupNetworkAssetField tìm field theo tên json (dns_host_name) hoặc tên cột (dnshostname), không phân biệt hoa thường
+func LookupNetworkAssetField(name string) (NetworkAssetField, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, f := range networkAssetFields {
+		if f.Name == name || f.Column == name {
+			return f, true
+		}
+	}
+	return NetworkAssetField{}, false
+}
+
+// GetField trả về giá trị field dạng chuỗi (thời gian theo RFC3339, nil là chuỗi rỗng)
+func (a *NetworkAsset) GetField(name string) string {
+	f, ok := LookupNetworkAssetField(name)
+	if !ok {
+		return ""
+	}
+
+	v := reflect.ValueOf(a).Elem().Field(f.index)
+	switch val := v.Interface().(type) {
+	case string:
+		return val
+	case int:
+		return strconv.Itoa(val)
+	case bool:
+		return strconv.FormatBool(val)
+	case time.Time:
+		if val.IsZero() {
+			return ""
+		}
+		return val.Format(time.RFC3339)
+	case *time.Time:
+		if val == nil {
+			return ""
+		}
+		return val.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// SetField gán giá trị dạng chuỗi cho field, chuyển đổi theo kiểu của field
+func (a *NetworkAsset) SetField(name, value string) error {
+	f, ok := LookupNetworkAssetField(name)
+	if !ok {
+		return fmt.Errorf("unknown field %s", name)
+	}
+
+	v := reflect.ValueOf(a).Elem().Field(f.index)
+	switch v.Interface().(type) {
+	case string:
+		v.SetString(value)
+	case int:
+		if value == "" {
+			v.SetInt(0)
+			return nil
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s must be an integer", f.Name)
+		}
+		v.SetInt(int64(n))
+	case bool:
+		if value == "" {
+			v.SetBool(false)
+			return nil
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s must be a boolean", f.Name)
+		}
+		v.SetBool(b)
+	case time.Time:
+		t, err := parseFieldTime(value)
+		if err != nil {
+			return fmt.Errorf("%s must be an RFC3339 timestamp", f.Name)
+		}
+		if t != nil {
+			v.Set(reflect.ValueOf(*t))
+		} else {
+			v.Set(reflect.ValueOf(time.Time{}))
+		}
+	case *time.Time:
+		t, err := parseFieldTime(value)
+		if err != nil {
+			return fmt.Errorf("%s must be an RFC3339 timestamp", f.Name)
+		}
+		v.Set(reflect.ValueOf(t))
+	default:
+		return fmt.Errorf("field %s cannot be set", f.Name)
+	}
+	return nil
+}
+
+func parseFieldTime(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
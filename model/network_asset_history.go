@@ -0,0 +1,60 @@
+package model
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+const (
+	HistoryCreate  = "Create"
+	HistoryUpdate  = "Update"
+	HistoryDelete  = "Delete"
+	HistoryRestore = "Restore"
+	HistoryPurge   = "Purge"
+	// Snapshot gốc cho asset có từ trước khi có bảng lịch sử
+	HistoryBaseline = "Baseline"
+)
+
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+type FieldChanges []FieldChange
+
+func (c FieldChanges) Value() (driver.Value, error) { return jsonValue(c) }
+func (c *FieldChanges) Scan(src interface{}) error  { return jsonScan(src, c) }
+
+type NetworkAssetHistory struct {
+	Id        int64        `json:"id" db:"id"`
+	AssetName string       `json:"asset_name" db:"assetname"`
+	Action    string       `json:"action" db:"action"`
+	Changes   FieldChanges `json:"changes" db:"changes"`
+	Actor     string       `json:"actor" db:"actor"`
+	RequestId string       `json:"request_id" db:"requestid"`
+	ChangedAt time.Time    `json:"changed_at" db:"changedat"`
+}
+
+// DiffNetworkAssets so sánh từng field, before hoặc after là nil khi tạo mới/purge.
+// ModifiedDate luôn đổi khi update, SubnetId được tính lại từ Address, LastSeen đổi sau mỗi lần scan,
+// nên không được tính là thay đổi.
+func DiffNetworkAssets(before, after *NetworkAsset) FieldChanges {
+	changes := FieldChanges{}
+	for _, f := range NetworkAssetFields() {
+		if f.Name == "modified_date" || f.Name == "subnet_id" || f.Name == "last_seen" {
+			continue
+		}
+		var oldValue, newValue string
+		if before != nil {
+			oldValue = before.GetField(f.Name)
+		}
+		if after != nil {
+			newValue = after.GetField(f.Name)
+		}
+		if oldValue != newValue {
+			changes = append(changes, FieldChange{Field: f.Name, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
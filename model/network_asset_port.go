@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// NetworkAssetPort là port phát hiện được trên asset khi scan
+type NetworkAssetPort struct {
+	AssetName string    `json:"asset_name" db:"assetname"`
+	Protocol  string    `json:"protocol" db:"protocol"` // tcp, udp, sctp
+	Port      int       `json:"port" db:"port"`
+	State     string    `json:"state" db:"state"` // open, open|filtered..., closed khi scan mới hơn không còn thấy
+	Service   string    `json:"service" db:"service"`
+	Product   string    `json:"product" db:"product"`
+	Version   string    `json:"version" db:"version"`
+	FirstSeen time.Time `json:"first_seen" db:"firstseen"`
+	LastSeen  time.Time `json:"last_seen" db:"lastseen"`
+}
+
+// DiscoveredHost là một host đang hoạt động tìm thấy khi scan
+type DiscoveredHost struct {
+	Row      int // vị trí host trong file scan
+	Address  string
+	Hostname string
+	MAC      string
+	Ports    []NetworkAssetPort
+	SeenAt   time.Time
+	// Values là field bổ sung do mapping sinh ra, ghi đè giá trị từ scan khi import
+	Values map[string]string
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	ReconciliationRunning = "Running"
+	ReconciliationSuccess = "Success"
+	ReconciliationFailed  = "Failed"
+)
+
+// IdentificationRules: danh sách rule theo thứ tự ưu tiên, mỗi rule là tập field (tên json)
+// phải trùng nhau, ví dụ [["dns_host_name"], ["address", "subnet_mask"], ["instance_id"]]
+type IdentificationRules [][]string
+
+// PrecedenceRules: field -> danh sách DatasetId theo thứ tự ưu tiên, key "*" là mặc định
+type PrecedenceRules map[string][]int
+
+// DatasetList: danh sách DatasetId nguồn theo thứ tự ưu tiên
+type DatasetList []int
+
+type ReconciliationJob struct {
+	Id                  int64               `json:"id" db:"id"`
+	Name                string              `json:"name" db:"name" validate:"required"`
+	SourceDatasets      DatasetList         `json:"source_datasets" db:"sourcedatasets" validate:"required,min=1"`
+	TargetDatasetId     int                 `json:"target_dataset_id" db:"targetdatasetid" validate:"required"`
+	IdentificationRules IdentificationRules `json:"identification_rules" db:"identificationrules" validate:"required,min=1"`
+	PrecedenceRules     PrecedenceRules     `json:"precedence_rules" db:"precedencerules"`
+	CreateDate          time.Time           `json:"create_date" db:"createdate"`
+	ModifiedDate        *time.Time          `json:"modified_date" db:"modifieddate"`
+}
+
+type ReconciliationRun struct {
+	Id           int64      `json:"id" db:"id"`
+	JobId        int64      `json:"job_id" db:"jobid"`
+	Status       string     `json:"status" db:"status"`
+	StartedAt    time.Time  `json:"started_at" db:"startedat"`
+	FinishedAt   *time.Time `json:"finished_at" db:"finishedat"`
+	Total        int        `json:"total" db:"total"`
+	Identified   int        `json:"identified" db:"identified"`
+	Merged       int        `json:"merged" db:"merged"`
+	Unmatched    int        `json:"unmatched" db:"unmatched"`
+	Created      int        `json:"created" db:"created"`
+	Updated      int        `json:"updated" db:"updated"`
+	ErrorMessage string     `json:"error_message" db:"errormessage"`
+	TriggeredBy  string     `json:"triggered_by" db:"triggeredby"`
+}
+
+func (r IdentificationRules) Value() (driver.Value, error) { return jsonValue(r) }
+func (r *IdentificationRules) Scan(src interface{}) error  { return jsonScan(src, r) }
+func (r PrecedenceRules) Value() (driver.Value, error)     { return jsonValue(r) }
+func (r *PrecedenceRules) Scan(src interface{}) error      { return jsonScan(src, r) }
+func (d DatasetList) Value() (driver.Value, error)         { return jsonValue(d) }
+func (d *DatasetList) Scan(src interface{}) error          { return jsonScan(src, d) }
+
+func jsonValue(v interface{}) (driver.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func jsonScan(src interface{}, dest interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	}
+	return fmt.Errorf("cannot scan %T into JSON", src)
+}
+
+// ReconciliationPlan là kết quả tính toán của một lần reconcile, được ghi trong một transaction
+type ReconciliationPlan struct {
+	Create []NetworkAsset
+	// Update: bản ghi golden đã tồn tại, key là Name
+	Update []NetworkAsset
+	// Identities: Name của bản ghi nguồn -> ReconciliationId được gán
+	Identities map[string]string
+}
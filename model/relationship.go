@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// NetworkAssetClass là tên class của NetworkAsset khi tham gia relationship
+const NetworkAssetClass = "NetworkAsset"
+
+type RelationshipType string
+
+const (
+	HostedOn    RelationshipType = "HostedOn"
+	DependsOn   RelationshipType = "DependsOn"
+	MemberOf    RelationshipType = "MemberOf"
+	ConnectedTo RelationshipType = "ConnectedTo"
+)
+
+func (t RelationshipType) IsValid() bool {
+	switch t {
+	case HostedOn, DependsOn, MemberOf, ConnectedTo:
+		return true
+	}
+	return false
+}
+
+// Relationship là quan hệ có hướng: Source --Type--> Target
+// (ví dụ WebServer01 DependsOn DBServer01)
+type Relationship struct {
+	Id          int64            `json:"id" db:"id"`
+	Type        RelationshipType `json:"type" db:"type"`
+	SourceClass string           `json:"source_class" db:"sourceclass"`
+	SourceName  string           `json:"source_name" db:"sourcename"`
+	TargetClass string           `json:"target_class" db:"targetclass"`
+	TargetName  string           `json:"target_name" db:"targetname"`
+	Description string           `json:"description" db:"description"`
+	CreateDate  time.Time        `json:"create_date" db:"createdate"`
+	CreatedBy   string           `json:"created_by" db:"createdby"`
+}
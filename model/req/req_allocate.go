@@ -0,0 +1,17 @@
+package req
+
+type ReqAllocateAddress struct {
+	// count > 1 cấp các địa chỉ liên tiếp, asset được đặt tên <name>-1, <name>-2...
+	Count int `json:"count,omitempty" validate:"omitempty,min=1,max=256"`
+	// Địa chỉ cụ thể (hoặc địa chỉ đầu của dải liên tiếp), bỏ trống để lấy địa chỉ trống đầu tiên
+	Address          string `json:"address,omitempty"`
+	Name             string `json:"name,omitempty" validate:"required,max=45"`
+	SystemName       string `json:"system_name,omitempty"`
+	ShortDescription string `json:"short_description,omitempty"`
+	Description      string `json:"description,omitempty"`
+	DNSHostName      string `json:"dns_host_name,omitempty"`
+	ProtocolType     string `json:"protocol_type,omitempty"`
+	DatasetId        int    `json:"dataset_id,omitempty"`
+	VlanId           int    `json:"vlan_id,omitempty"` // mặc định là VLAN của subnet
+	RequestId        string `json:"request_id,omitempty"`
+}
@@ -0,0 +1,7 @@
+package req
+
+type ReqDNSCheck struct {
+	// Tên các asset cần kiểm tra, bỏ trống để kiểm tra mọi asset (lọc theo dataset_id nếu có)
+	Names     []string `json:"names,omitempty"`
+	DatasetId int      `json:"dataset_id,omitempty"`
+}
@@ -0,0 +1,10 @@
+package req
+
+type ReqCreateRelationship struct {
+	Type string `json:"type,omitempty" validate:"required"`
+	// outgoing (mặc định): asset hiện tại là source; incoming: asset hiện tại là target
+	Direction   string `json:"direction,omitempty" validate:"omitempty,oneof=outgoing incoming"`
+	OtherClass  string `json:"other_class,omitempty"`
+	OtherName   string `json:"other_name,omitempty" validate:"required"`
+	Description string `json:"description,omitempty"`
+}
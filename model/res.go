@@ -29,3 +29,9 @@ type ListResponse struct {
 	Page       int         `json:"page"`
 	Limit      int         `json:"limit"`
 }
+
+// FieldError - lỗi validate của một field trong request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
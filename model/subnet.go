@@ -0,0 +1,74 @@
+package model
+
+import (
+	"math/big"
+	"time"
+)
+
+type Subnet struct {
+	Id             int64      `json:"id" db:"id"`
+	Cidr           string     `json:"cidr" db:"cidr"`
+	Name           string     `json:"name" db:"name"`
+	Description    string     `json:"description" db:"description"`
+	Gateway        string     `json:"gateway" db:"gateway"`
+	ParentId       *int64     `json:"parent_id" db:"parentid"`
+	DatasetId      int        `json:"dataset_id" db:"datasetid"`
+	VrfId          int        `json:"vrf_id" db:"vrfid"`   // 0 là bảng định tuyến global
+	VlanId         int        `json:"vlan_id" db:"vlanid"` // VLAN ID (802.1Q), 0 nếu không có
+	Owner          string     `json:"owner" db:"owner"`
+	CreateDate     time.Time  `json:"create_date" db:"createdate"`
+	ModifiedDate   *time.Time `json:"modified_date" db:"modifieddate"`
+	LastModifiedBy string     `json:"last_modified_by" db:"lastmodifiedby"`
+
+	Utilization  *SubnetUtilization  `json:"utilization,omitempty" db:"-"`
+	Reservations []SubnetReservation `json:"reservations,omitempty" db:"-"`
+	Children     []*Subnet           `json:"children,omitempty" db:"-"`
+}
+
+// SubnetFilter lọc danh sách subnet
+type SubnetFilter struct {
+	Vrf    string `query:"vrf"` // tên VRF, "global" là bảng định tuyến global
+	VlanId int    `query:"vlan_id"`
+}
+
+// SubnetUtilization tính trên các asset chưa xóa nằm trong subnet (kể cả subnet con).
+// Số lượng dùng big.Int vì subnet IPv6 có thể lớn hơn int64.
+type SubnetUtilization struct {
+	Total       *big.Int `json:"total"`    // số địa chỉ cấp được (IPv4 trừ network/broadcast)
+	Used        *big.Int `json:"used"`     // số địa chỉ đã có asset
+	Reserved    *big.Int `json:"reserved"` // dải reservation và gateway chưa có asset
+	Free        *big.Int `json:"free"`
+	PercentFull float64  `json:"percent_full"`
+}
+
+type SubnetReservation struct {
+	Id           int64     `json:"id" db:"id"`
+	SubnetId     int64     `json:"subnet_id" db:"subnetid"`
+	StartAddress string    `json:"start_address" db:"startaddress"`
+	EndAddress   string    `json:"end_address" db:"endaddress"`
+	Description  string    `json:"description" db:"description"`
+	CreatedBy    string    `json:"created_by" db:"createdby"`
+	CreateDate   time.Time `json:"create_date" db:"createdate"`
+}
+
+// BuildSubnetTree dựng cây subnet theo ParentId, subnet không có cha (hoặc cha không nằm
+// trong danh sách) là gốc
+func BuildSubnetTree(subnets []Subnet) []*Subnet {
+	nodes := make(map[int64]*Subnet, len(subnets))
+	for i := range subnets {
+		nodes[subnets[i].Id] = &subnets[i]
+	}
+
+	roots := []*Subnet{}
+	for i := range subnets {
+		s := &subnets[i]
+		if s.ParentId != nil {
+			if parent, ok := nodes[*s.ParentId]; ok {
+				parent.Children = append(parent.Children, s)
+				continue
+			}
+		}
+		roots = append(roots, s)
+	}
+	return roots
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// GlobalVrf là tên dùng trong filter và tham số để chỉ bảng định tuyến global (VrfId = 0)
+const GlobalVrf = "global"
+
+// Vrf là một bảng định tuyến riêng, cho phép cùng một dải địa chỉ tồn tại ở nhiều VRF
+type Vrf struct {
+	Id                 int64      `json:"id" db:"id"`
+	Name               string     `json:"name" db:"name" validate:"required,max=100"`
+	RouteDistinguisher string     `json:"route_distinguisher" db:"routedistinguisher" validate:"max=50"`
+	Description        string     `json:"description" db:"description"`
+	CreateDate         time.Time  `json:"create_date" db:"createdate"`
+	ModifiedDate       *time.Time `json:"modified_date" db:"modifieddate"`
+	LastModifiedBy     string     `json:"last_modified_by" db:"lastmodifiedby"`
+}
+
+// Vlan định danh bằng VLAN ID (802.1Q). VrfId = 0 là VLAN không gắn VRF, dùng được ở mọi VRF.
+type Vlan struct {
+	VlanId         int        `json:"vlan_id" db:"vlanid" validate:"required,min=1,max=4094"`
+	Name           string     `json:"name" db:"name" validate:"max=100"`
+	Description    string     `json:"description" db:"description"`
+	VrfId          int        `json:"vrf_id" db:"vrfid"`
+	CreateDate     time.Time  `json:"create_date" db:"createdate"`
+	ModifiedDate   *time.Time `json:"modified_date" db:"modifieddate"`
+	LastModifiedBy string     `json:"last_modified_by" db:"lastmodifiedby"`
+}
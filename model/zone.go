@@ -0,0 +1,9 @@
+package model
+
+// ZoneFilter chọn asset để sinh zone file: Domain (không có dấu chấm cuối) cho forward zone,
+// Cidr cho reverse zone
+type ZoneFilter struct {
+	Domain    string
+	Cidr      string
+	DatasetId int
+}
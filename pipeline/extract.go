@@ -0,0 +1,83 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/csv"
+	stderrors "errors"
+	"io"
+	"strings"
+
+	"github.com/sllpklls/template-backend-go/importer"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// HeaderError: header của file nguồn không hợp lệ, không bản ghi nào được đọc
+type HeaderError struct {
+	Errors []model.FieldError
+}
+
+func (e *HeaderError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		parts = append(parts, fe.Field+": "+fe.Message)
+	}
+	return "invalid header: " + strings.Join(parts, "; ")
+}
+
+// CSVExtractor đọc file CSV network asset (cùng định dạng với import CSV) từng dòng một.
+// Dòng sai số cột hoặc sai cú pháp CSV được báo lỗi và bỏ qua.
+func CSVExtractor(r io.Reader) Extractor {
+	return csvExtractor(r, importer.NewCSVReader)
+}
+
+// SourceCSVExtractor giữ nguyên tên cột của file, dùng cùng transformer MapFields
+func SourceCSVExtractor(r io.Reader) Extractor {
+	return csvExtractor(r, importer.NewSourceCSVReader)
+}
+
+func csvExtractor(r io.Reader, newReader func(io.Reader) (*importer.CSVReader, []model.FieldError, error)) Extractor {
+	return ExtractorFunc(func(ctx context.Context, emit func(Record) error) error {
+		reader, headerErrs, err := newReader(r)
+		if err != nil {
+			return err
+		}
+		if len(headerErrs) > 0 {
+			return &HeaderError{Errors: headerErrs}
+		}
+
+		for {
+			row, err := reader.Next()
+			if err == io.EOF {
+				return nil
+			}
+			record := Record{Row: row.Row, Values: row.Values}
+			if err != nil {
+				var parseErr *csv.ParseError
+				var countErr *importer.ColumnCountError
+				switch {
+				case stderrors.As(err, &parseErr):
+					record = Record{Row: parseErr.Line, Err: err}
+				case stderrors.As(err, &countErr):
+					record.Err = err
+				default:
+					return err
+				}
+			}
+			if err := emit(record); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// RowsExtractor phát các dòng đã đọc sẵn, dùng cho nguồn mà parser phải đọc cả file (zone, lease)
+func RowsExtractor(rows []model.ImportRow) Extractor {
+	return ExtractorFunc(func(ctx context.Context, emit func(Record) error) error {
+		for _, row := range rows {
+			if err := emit(Record{Row: row.Row, Values: row.Values}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+// RepoLoader ghi bản ghi bằng NetworkAssetRepo, mỗi lô trong một transaction. Repo chỉ commit khi
+// không có dòng lỗi, nên dòng lỗi được bỏ ra và lô được ghi lại với các dòng còn lại.
+type RepoLoader struct {
+	Repo    repository.NetworkAssetRepo
+	Options model.ImportOptions
+	Actor   string
+	// ByInstanceId khớp asset theo InstanceId (ImportByInstanceId) thay vì theo name
+	ByInstanceId bool
+}
+
+func (l *RepoLoader) Load(ctx context.Context, records []Record) (*model.ImportReport, error) {
+	// replace-dataset xóa asset không có trong lần import, chạy theo lô sẽ xóa asset của các lô khác
+	if l.Options.Mode == model.ImportReplaceDataset {
+		return nil, fmt.Errorf("mode %s is not supported in a pipeline", model.ImportReplaceDataset)
+	}
+
+	rows := make([]model.ImportRow, len(records))
+	for i, record := range records {
+		rows[i] = model.ImportRow{Row: record.Row, Values: record.Values}
+	}
+	return ImportSkippingFailed(rows, func(rows []model.ImportRow) (*model.ImportReport, error) {
+		if l.ByInstanceId {
+			return l.Repo.ImportByInstanceId(ctx, rows, l.Options, l.Actor)
+		}
+		return l.Repo.ImportNetworkAssets(ctx, rows, l.Options, l.Actor)
+	})
+}
+
+// ImportSkippingFailed gọi importRows, nếu có dòng lỗi (không gì được ghi) thì bỏ các dòng đó và
+// import lại phần còn lại. Report trả về gồm kết quả của lần ghi cuối và các dòng lỗi.
+func ImportSkippingFailed(rows []model.ImportRow, importRows func([]model.ImportRow) (*model.ImportReport, error)) (*model.ImportReport, error) {
+	var failed []model.ImportRowResult
+	for {
+		report, err := importRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		if report.Failed == 0 || report.Committed {
+			for _, result := range failed {
+				report.Add(result)
+			}
+			return report, nil
+		}
+
+		failedRows := map[int]bool{}
+		for _, result := range report.Rows {
+			if result.Status == model.ImportFailed {
+				failedRows[result.Row] = true
+				failed = append(failed, result)
+			}
+		}
+		remaining := make([]model.ImportRow, 0, len(rows)-len(failedRows))
+		for _, row := range rows {
+			if !failedRows[row.Row] {
+				remaining = append(remaining, row)
+			}
+		}
+		if len(remaining) == len(rows) {
+			return nil, fmt.Errorf("import failed without row errors")
+		}
+		rows = remaining
+	}
+}
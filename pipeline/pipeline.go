@@ -0,0 +1,195 @@
+// Package pipeline ghép một nguồn dữ liệu (Extractor), các bước biến đổi (Transformer) và nơi ghi
+// (Loader) thành một luồng xử lý từng bản ghi. Bản ghi đi qua channel có giới hạn nên Extractor bị
+// chặn khi Loader ghi chậm, bộ nhớ chỉ phụ thuộc Buffer và BatchSize chứ không phụ thuộc kích thước
+// nguồn. Lỗi của từng bản ghi được ghi lại và bản ghi bị bỏ qua, không dừng cả lần chạy.
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+const (
+	DefaultBatchSize = 500
+	// Số lỗi tối đa giữ trong Stats.Errors, Failed vẫn được đếm đủ
+	maxErrors = 1000
+)
+
+// Record là một bản ghi đi qua pipeline: tên field json của NetworkAsset -> giá trị thô
+type Record struct {
+	Row    int // vị trí trong nguồn, dùng trong báo lỗi
+	Values map[string]string
+	// Err là lỗi khi đọc bản ghi, bản ghi được tính là lỗi và không đi tiếp
+	Err error
+}
+
+// Extractor đọc nguồn và gọi emit cho từng bản ghi. emit bị chặn khi pipeline phía sau đầy và trả
+// về lỗi khi pipeline đã dừng, khi đó Extract phải trả về ngay.
+type Extractor interface {
+	Extract(ctx context.Context, emit func(Record) error) error
+}
+
+type ExtractorFunc func(ctx context.Context, emit func(Record) error) error
+
+func (f ExtractorFunc) Extract(ctx context.Context, emit func(Record) error) error {
+	return f(ctx, emit)
+}
+
+// Transformer sửa bản ghi tại chỗ, error làm bản ghi bị bỏ qua và ghi vào lỗi
+type Transformer interface {
+	Transform(record *Record) error
+}
+
+type TransformerFunc func(record *Record) error
+
+func (f TransformerFunc) Transform(record *Record) error {
+	return f(record)
+}
+
+// Loader ghi một lô bản ghi. Lỗi của từng bản ghi nằm trong report, error là lỗi làm dừng pipeline
+// (mất kết nối DB...).
+type Loader interface {
+	Load(ctx context.Context, records []Record) (*model.ImportReport, error)
+}
+
+type Pipeline struct {
+	Extractor    Extractor
+	Transformers []Transformer
+	Loader       Loader
+	// BatchSize là số bản ghi mỗi lần gọi Loader, mặc định DefaultBatchSize
+	BatchSize int
+	// Buffer là số bản ghi tối đa chờ giữa Extractor và Loader, mặc định bằng BatchSize
+	Buffer int
+}
+
+type Stats struct {
+	Read    int              `json:"read"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Skipped int              `json:"skipped"`
+	Deleted int              `json:"deleted"`
+	Failed  int              `json:"failed"`
+	Errors  []model.EtlError `json:"errors,omitempty"`
+}
+
+// fail đếm một bản ghi lỗi và ghi các lỗi của nó
+func (s *Stats) fail(errs ...model.EtlError) {
+	s.Failed++
+	for _, e := range errs {
+		if len(s.Errors) < maxErrors {
+			s.Errors = append(s.Errors, e)
+		}
+	}
+}
+
+func (s *Stats) addReport(report *model.ImportReport) {
+	s.Created += report.Created
+	s.Updated += report.Updated
+	s.Skipped += report.Skipped
+	s.Deleted += report.Deleted + report.Deactivated
+	for _, row := range report.Rows {
+		if row.Status != model.ImportFailed {
+			continue
+		}
+		errs := []model.EtlError{{Row: row.Row, Name: row.Name, Message: row.Message}}
+		if len(row.Errors) > 0 {
+			errs = errs[:0]
+			for _, e := range row.Errors {
+				errs = append(errs, model.EtlError{Row: row.Row, Name: row.Name, Field: e.Field, Message: e.Message})
+			}
+		}
+		s.fail(errs...)
+	}
+}
+
+// Run chạy pipeline tới khi hết dữ liệu. Stats luôn phản ánh những gì đã ghi, kể cả khi có error.
+func (p *Pipeline) Run(ctx context.Context) (*Stats, error) {
+	batchSize := p.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	buffer := p.Buffer
+	if buffer <= 0 {
+		buffer = batchSize
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	records := make(chan Record, buffer)
+	extractDone := make(chan error, 1)
+	go func() {
+		defer close(records)
+		extractDone <- p.Extractor.Extract(ctx, func(record Record) error {
+			select {
+			case records <- record:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	stats := &Stats{}
+	loadErr := p.consume(ctx, records, batchSize, stats)
+	if loadErr != nil {
+		// Dừng Extractor và chờ nó trả về để không rò goroutine
+		cancel()
+		for range records {
+		}
+		<-extractDone
+		return stats, loadErr
+	}
+
+	if err := <-extractDone; err != nil {
+		return stats, fmt.Errorf("extract failed: %w", err)
+	}
+	return stats, nil
+}
+
+func (p *Pipeline) consume(ctx context.Context, records <-chan Record, batchSize int, stats *Stats) error {
+	batch := make([]Record, 0, batchSize)
+	load := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		report, err := p.Loader.Load(ctx, batch)
+		if err != nil {
+			return err
+		}
+		stats.addReport(report)
+		batch = make([]Record, 0, batchSize)
+		return nil
+	}
+
+	for record := range records {
+		stats.Read++
+		if record.Err != nil {
+			stats.fail(model.EtlError{Row: record.Row, Message: record.Err.Error()})
+			continue
+		}
+		if err := p.transform(&record); err != nil {
+			stats.fail(model.EtlError{Row: record.Row, Name: record.Values["name"], Message: err.Error()})
+			continue
+		}
+
+		batch = append(batch, record)
+		if len(batch) >= batchSize {
+			if err := load(); err != nil {
+				return err
+			}
+		}
+	}
+	return load()
+}
+
+func (p *Pipeline) transform(record *Record) error {
+	for _, t := range p.Transformers {
+		if err := t.Transform(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package pipeline
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sllpklls/template-backend-go/ipaddr"
+	"github.com/sllpklls/template-backend-go/mapping"
+)
+
+// Trim bỏ khoảng trắng đầu/cuối của mọi giá trị
+func Trim() Transformer {
+	return TransformerFunc(func(record *Record) error {
+		for field, value := range record.Values {
+			record.Values[field] = strings.TrimSpace(value)
+		}
+		return nil
+	})
+}
+
+// LowercaseHostname đưa dns_host_name về chữ thường (DNS không phân biệt hoa thường)
+func LowercaseHostname() Transformer {
+	return TransformerFunc(func(record *Record) error {
+		if host, ok := record.Values["dns_host_name"]; ok {
+			record.Values["dns_host_name"] = strings.ToLower(host)
+		}
+		return nil
+	})
+}
+
+// DeriveAddressType điền address_type (IPv4/IPv6) từ address khi nguồn không có. Địa chỉ không
+// hợp lệ được để nguyên cho bước kiểm tra khi ghi báo lỗi.
+func DeriveAddressType() Transformer {
+	return TransformerFunc(func(record *Record) error {
+		if record.Values["address_type"] != "" {
+			return nil
+		}
+		addr, _, err := ipaddr.ParseAddress(record.Values["address"])
+		if err != nil {
+			return nil
+		}
+		record.Values["address_type"] = ipaddr.Family(addr)
+		return nil
+	})
+}
+
+// DefaultDatasetId gán dataset_id cho bản ghi không có
+func DefaultDatasetId(datasetId int) Transformer {
+	return TransformerFunc(func(record *Record) error {
+		if record.Values["dataset_id"] == "" {
+			record.Values["dataset_id"] = strconv.Itoa(datasetId)
+		}
+		return nil
+	})
+}
+
+// MapFields chuyển bản ghi nguồn thành field NetworkAsset theo mapping, nên đặt trước các transformer khác
+func MapFields(m *mapping.Mapping) Transformer {
+	return TransformerFunc(func(record *Record) error {
+		values, errs := m.Apply(record.Values)
+		if len(errs) > 0 {
+			parts := make([]string, 0, len(errs))
+			for _, fe := range errs {
+				parts = append(parts, fe.Field+": "+fe.Message)
+			}
+			return fmt.Errorf("mapping failed: %s", strings.Join(parts, "; "))
+		}
+		record.Values = values
+		return nil
+	})
+}
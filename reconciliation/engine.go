@@ -0,0 +1,448 @@
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+// Actor ghi vào LastModifiedBy của bản ghi golden
+const Actor = "reconciliation"
+
+// Các field không được merge: định danh, metadata của bản ghi, ReconciliationId, trạng thái xóa, subnet (tự tính)
+// và LastSeen (chỉ scan ghi)
+var nonMergeableFields = map[string]bool{
+	"name":              true,
+	"dataset_id":        true,
+	"create_date":       true,
+	"modified_date":     true,
+	"last_modified_by":  true,
+	"instance_id":       true,
+	"reconciliation_id": true,
+	"mark_as_deleted":   true,
+	"deleted_by":        true,
+	"deleted_at":        true,
+	"subnet_id":         true,
+	"last_seen":         true,
+}
+
+type Engine struct {
+	Repo repository.ReconciliationRepo
+}
+
+// Run chạy một job: đọc dataset nguồn và dataset đích, tính plan và ghi kết quả,
+// mọi lần chạy đều được lưu vào ReconciliationRuns kể cả khi lỗi
+func (e *Engine) Run(ctx context.Context, job model.ReconciliationJob, triggeredBy string) (model.ReconciliationRun, error) {
+	run, err := e.Repo.CreateRun(ctx, model.ReconciliationRun{
+		JobId:       job.Id,
+		Status:      model.ReconciliationRunning,
+		TriggeredBy: triggeredBy,
+	})
+	if err != nil {
+		return run, err
+	}
+
+	runErr := e.run(ctx, job, &run)
+	if runErr != nil {
+		run.Status = model.ReconciliationFailed
+		run.ErrorMessage = runErr.Error()
+	} else {
+		run.Status = model.ReconciliationSuccess
+	}
+
+	if err := e.Repo.FinishRun(ctx, run); err != nil {
+		return run, err
+	}
+	return run, runErr
+}
+
+func (e *Engine) run(ctx context.Context, job model.ReconciliationJob, run *model.ReconciliationRun) error {
+	datasets := append([]int{job.TargetDatasetId}, job.SourceDatasets...)
+	assets, err := e.Repo.GetNetworkAssetsByDatasets(ctx, datasets)
+	if err != nil {
+		return err
+	}
+
+	plan, stats := Reconcile(job, assets)
+	run.Total = stats.Total
+	run.Identified = stats.Identified
+	run.Merged = stats.Merged
+	run.Unmatched = stats.Unmatched
+	run.Created = len(plan.Create)
+	run.Updated = len(plan.Update)
+
+	return e.Repo.SaveReconciliation(ctx, plan, run.TriggeredBy)
+}
+
+type Stats struct {
+	Total      int
+	Identified int
+	Merged     int
+	Unmatched  int
+}
+
+// Reconcile nhóm các bản ghi nguồn theo identification rules (cùng với bản ghi golden
+// đã có trong dataset đích), rồi merge từng nhóm thành một bản ghi golden theo precedence rules
+func Reconcile(job model.ReconciliationJob, assets []model.NetworkAsset) (model.ReconciliationPlan, Stats) {
+	plan := model.ReconciliationPlan{Identities: map[string]string{}}
+	var stats Stats
+
+	rank := map[int]int{}
+	for i, id := range job.SourceDatasets {
+		if _, ok := rank[id]; !ok {
+			rank[id] = i
+		}
+	}
+
+	var records []model.NetworkAsset
+	var isSource []bool
+	for _, a := range assets {
+		if a.DatasetId == job.TargetDatasetId {
+			records = append(records, a)
+			isSource = append(isSource, false)
+		} else if _, ok := rank[a.DatasetId]; ok {
+			records = append(records, a)
+			isSource = append(isSource, true)
+			stats.Total++
+		}
+	}
+
+	identified := make([]bool, len(records))
+	g := identify(records, job.IdentificationRules, identified)
+
+	groups := map[int][]int{}
+	var roots []int
+	for i := range records {
+		if isSource[i] && !identified[i] {
+			stats.Unmatched++
+			continue
+		}
+		root := g.find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+
+	for _, root := range roots {
+		var sources []model.NetworkAsset
+		var golden *model.NetworkAsset
+		for _, i := range groups[root] {
+			if isSource[i] {
+				sources = append(sources, records[i])
+			} else if golden == nil {
+				golden = &records[i]
+			}
+		}
+		if len(sources) == 0 {
+			continue
+		}
+		stats.Identified += len(sources)
+		stats.Merged++
+
+		reconciliationId := ""
+		if golden != nil {
+			reconciliationId = golden.ReconciliationId
+		}
+		for _, s := range sources {
+			if reconciliationId == "" && s.ReconciliationId != "" {
+				reconciliationId = s.ReconciliationId
+			}
+		}
+		if reconciliationId == "" {
+			reconciliationId = "RE" + strings.ReplaceAll(uuid.NewString(), "-", "")
+		}
+
+		merged := merge(job, rank, sources)
+		merged.DatasetId = job.TargetDatasetId
+		merged.ReconciliationId = reconciliationId
+		merged.LastModifiedBy = Actor
+
+		if golden == nil {
+			// Tên của bản ghi golden phải khác tên bản ghi nguồn vì Name là duy nhất; đây là tên gợi ý,
+			// khi ghi được cắt ngắn và thêm hậu tố nếu trùng
+			merged.Name = fmt.Sprintf("%s-%d", merged.Name, job.TargetDatasetId)
+			merged.InstanceId = reconciliationId
+			plan.Create = append(plan.Create, merged)
+		} else {
+			merged.Name = golden.Name
+			merged.InstanceId = golden.InstanceId
+			if changed(golden, &merged) {
+				plan.Update = append(plan.Update, merged)
+			}
+		}
+
+		for _, s := range sources {
+			if s.ReconciliationId != reconciliationId {
+				plan.Identities[s.Name] = reconciliationId
+			}
+		}
+	}
+
+	return plan, stats
+}
+
+// grouping nhóm bản ghi theo từng mức nhận diện: mức 0 là ReconciliationId đã có, mức r+1 là
+// IdentificationRules[r]. keys[root][level] là các key của nhóm ở mức đó.
+type grouping struct {
+	*unionFind
+	keys    map[int][]map[string]bool
+	matched []bool // đã vào nhóm cùng bản ghi khác ở một mức trước
+}
+
+// identify áp dụng lần lượt ReconciliationId rồi các rule theo thứ tự ưu tiên. Bản ghi được nhóm bởi
+// mức đầu tiên khớp với nó: ở các mức sau nó chỉ là điểm neo cho bản ghi chưa khớp, không kéo thêm nhóm
+// khác vào. Hai nhóm có key khác nhau ở một mức trước (ví dụ khác InstanceId) không bao giờ được gộp,
+// nên việc gộp không bắc cầu qua các rule ưu tiên thấp.
+func identify(records []model.NetworkAsset, rules model.IdentificationRules, identified []bool) *grouping {
+	levels := len(rules) + 1
+	g := &grouping{
+		unionFind: newUnionFind(len(records)),
+		keys:      map[int][]map[string]bool{},
+		matched:   make([]bool, len(records)),
+	}
+
+	recordKeys := make([][]string, len(records))
+	for i := range records {
+		recordKeys[i] = make([]string, levels)
+		g.keys[i] = make([]map[string]bool, levels)
+		if id := records[i].ReconciliationId; id != "" {
+			recordKeys[i][0] = id
+		}
+		for r, rule := range rules {
+			if key, ok := identificationKey(&records[i], rule); ok {
+				recordKeys[i][r+1] = key
+			}
+		}
+		for level, key := range recordKeys[i] {
+			if key != "" {
+				identified[i] = true
+				g.keys[i][level] = map[string]bool{key: true}
+			}
+		}
+	}
+
+	for level := 0; level < levels; level++ {
+		buckets := map[string][]int{}
+		var order []string
+		for i := range records {
+			key := recordKeys[i][level]
+			if key == "" {
+				continue
+			}
+			if _, ok := buckets[key]; !ok {
+				order = append(order, key)
+			}
+			buckets[key] = append(buckets[key], i)
+		}
+
+		for _, key := range order {
+			var targets []int
+			for _, i := range buckets[key] {
+				if g.matched[i] {
+					targets = append(targets, i)
+				}
+			}
+			for _, i := range buckets[key] {
+				if g.matched[i] {
+					continue
+				}
+				joined := false
+				for _, t := range targets {
+					if g.join(i, t, level) {
+						g.matched[t] = true
+						joined = true
+						break
+					}
+				}
+				if joined {
+					g.matched[i] = true
+				} else {
+					targets = append(targets, i)
+				}
+			}
+		}
+	}
+	return g
+}
+
+// join gộp nhóm của i vào nhóm của j nếu hai nhóm không bị tách ở mức nào trước level
+func (g *grouping) join(i, j, level int) bool {
+	ri, rj := g.find(i), g.find(j)
+	if ri == rj {
+		return true
+	}
+	for l := 0; l < level; l++ {
+		a, b := g.keys[ri][l], g.keys[rj][l]
+		if len(a) > 0 && len(b) > 0 && disjoint(a, b) {
+			return false
+		}
+	}
+
+	g.union(ri, rj)
+	root := g.find(ri)
+	other := ri
+	if root == ri {
+		other = rj
+	}
+	for l, keys := range g.keys[other] {
+		if len(keys) == 0 {
+			continue
+		}
+		if g.keys[root][l] == nil {
+			g.keys[root][l] = map[string]bool{}
+		}
+		for key := range keys {
+			g.keys[root][l][key] = true
+		}
+	}
+	delete(g.keys, other)
+	return true
+}
+
+func disjoint(a, b map[string]bool) bool {
+	for key := range a {
+		if b[key] {
+			return false
+		}
+	}
+	return true
+}
+
+func identificationKey(a *model.NetworkAsset, rule []string) (string, bool) {
+	if len(rule) == 0 {
+		return "", false
+	}
+	parts := make([]string, 0, len(rule))
+	for _, field := range rule {
+		v := strings.ToLower(strings.TrimSpace(a.GetField(field)))
+		if v == "" {
+			return "", false
+		}
+		parts = append(parts, v)
+	}
+	return strings.Join(rule, ",") + "=" + strings.Join(parts, "\x00"), true
+}
+
+// addressFields đi cùng nhau: lấy từ các nguồn khác nhau sẽ tạo ra cặp địa chỉ/VRF không có ở nguồn nào
+var addressFields = []string{"address", "subnet_mask", "address_type", "vrf_id", "vlan_id"}
+
+// merge chọn giá trị từng field từ bản ghi nguồn có dataset ưu tiên cao nhất và giá trị khác rỗng.
+// Các field trong addressFields lấy cùng lúc từ một bản ghi: bản ghi có address khác rỗng đứng đầu
+// theo precedence của "address".
+func merge(job model.ReconciliationJob, rank map[int]int, sources []model.NetworkAsset) model.NetworkAsset {
+	var merged model.NetworkAsset
+
+	precedence := func(field string) []int {
+		if order := job.PrecedenceRules[field]; order != nil {
+			return order
+		}
+		return job.PrecedenceRules["*"]
+	}
+
+	candidates := sortByPrecedence(sources, precedence("address"), rank)
+	addressSource := candidates[0]
+	for _, c := range candidates {
+		if c.Address != "" {
+			addressSource = c
+			break
+		}
+	}
+	grouped := map[string]bool{}
+	for _, field := range addressFields {
+		merged.SetField(field, addressSource.GetField(field))
+		grouped[field] = true
+	}
+
+	for _, f := range model.NetworkAssetFields() {
+		if nonMergeableFields[f.Name] && f.Name != "name" || grouped[f.Name] {
+			continue
+		}
+
+		candidates := sortByPrecedence(sources, precedence(f.Name), rank)
+
+		for _, c := range candidates {
+			if v := c.GetField(f.Name); v != "" && v != "0" {
+				merged.SetField(f.Name, v)
+				break
+			}
+		}
+	}
+
+	return merged
+}
+
+func sortByPrecedence(sources []model.NetworkAsset, order []int, rank map[int]int) []model.NetworkAsset {
+	position := func(datasetId int) int {
+		for i, id := range order {
+			if id == datasetId {
+				return i
+			}
+		}
+		// Dataset không có trong precedence rule xếp sau, theo thứ tự SourceDatasets
+		return len(order) + rank[datasetId]
+	}
+
+	sorted := append([]model.NetworkAsset{}, sources...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		pi, pj := position(sorted[i].DatasetId), position(sorted[j].DatasetId)
+		if pi != pj {
+			return pi < pj
+		}
+		// Cùng dataset: bản ghi sửa gần nhất thắng
+		return modifiedAt(sorted[i]).After(modifiedAt(sorted[j]))
+	})
+	return sorted
+}
+
+func modifiedAt(a model.NetworkAsset) time.Time {
+	if a.ModifiedDate != nil {
+		return *a.ModifiedDate
+	}
+	return a.CreateDate
+}
+
+func changed(golden, merged *model.NetworkAsset) bool {
+	for _, f := range model.NetworkAssetFields() {
+		if nonMergeableFields[f.Name] {
+			continue
+		}
+		if golden.GetField(f.Name) != merged.GetField(f.Name) {
+			return true
+		}
+	}
+	return golden.ReconciliationId != merged.ReconciliationId
+}
+
+type unionFind struct {
+	parent []int
+}
+
+func newUnionFind(n int) *unionFind {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	return &unionFind{parent: parent}
+}
+
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+func (u *unionFind) union(i, j int) {
+	ri, rj := u.find(i), u.find(j)
+	if ri != rj {
+		u.parent[ri] = rj
+	}
+}
@@ -0,0 +1,236 @@
+package remedy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client gọi Remedy AR REST API (/api/arsys/v1), xác thực bằng AR-JWT lấy từ /api/jwt/login.
+// Token được lấy lại một lần khi server trả 401 (hết hạn).
+type Client struct {
+	BaseURL  string // ví dụ https://remedy.example.com:8008
+	Username string
+	Password string
+	// HTTPClient nil thì dùng client mặc định với timeout 30s
+	HTTPClient *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+// Entry là một bản ghi của form, Values là attribute -> giá trị (string, số hoặc nil)
+type Entry struct {
+	Values map[string]interface{} `json:"values"`
+}
+
+type EntryQuery struct {
+	Qualification string   // cú pháp qualification của AR, ví dụ 'Last Modified Date' >= 1723456789
+	Sort          string   // ví dụ Last Modified Date.asc
+	Fields        []string // rỗng là lấy mọi field
+	Offset        int
+	Limit         int
+}
+
+type EntryPage struct {
+	Entries []Entry
+	HasNext bool
+}
+
+// Error là lỗi do Remedy trả về (mảng message trong body)
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("remedy returned %d: %s", e.StatusCode, e.Message)
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// Login lấy JWT mới, body của response chính là token
+func (c *Client) Login(ctx context.Context) error {
+	form := url.Values{"username": {c.Username}, "password": {c.Password}}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/api/jwt/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := c.httpClient().Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to login to remedy: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read remedy login response: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return responseError(response.StatusCode, body)
+	}
+
+	token := strings.TrimSpace(string(body))
+	if token == "" {
+		return fmt.Errorf("remedy login returned an empty token")
+	}
+	c.mu.Lock()
+	c.token = token
+	c.mu.Unlock()
+	return nil
+}
+
+// Logout hủy token hiện tại, không làm gì nếu chưa login
+func (c *Client) Logout(ctx context.Context) error {
+	c.mu.Lock()
+	token := c.token
+	c.token = ""
+	c.mu.Unlock()
+	if token == "" {
+		return nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/api/jwt/logout", nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "AR-JWT "+token)
+
+	response, err := c.httpClient().Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to logout from remedy: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+		return responseError(response.StatusCode, body)
+	}
+	return nil
+}
+
+// GetEntries đọc một trang bản ghi của form
+func (c *Client) GetEntries(ctx context.Context, form string, query EntryQuery) (*EntryPage, error) {
+	params := url.Values{}
+	if query.Qualification != "" {
+		params.Set("q", query.Qualification)
+	}
+	if query.Sort != "" {
+		params.Set("sort", query.Sort)
+	}
+	if len(query.Fields) > 0 {
+		params.Set("fields", "values("+strings.Join(query.Fields, ",")+")")
+	}
+	params.Set("offset", strconv.Itoa(query.Offset))
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	endpoint := c.BaseURL + "/api/arsys/v1/entry/" + url.PathEscape(form) + "?" + params.Encode()
+
+	var result struct {
+		Entries []Entry `json:"entries"`
+		Links   struct {
+			Next []struct {
+				Href string `json:"href"`
+			} `json:"next"`
+		} `json:"_links"`
+	}
+	if err := c.getJSON(ctx, endpoint, &result); err != nil {
+		return nil, err
+	}
+	return &EntryPage{Entries: result.Entries, HasNext: len(result.Links.Next) > 0}, nil
+}
+
+// getJSON gửi GET có token, login khi chưa có token; token cũ bị từ chối (401) thì login lại một lần
+func (c *Client) getJSON(ctx context.Context, endpoint string, out interface{}) error {
+	retried := false
+	for {
+		c.mu.Lock()
+		token := c.token
+		c.mu.Unlock()
+		fresh := token == ""
+		if fresh {
+			if err := c.Login(ctx); err != nil {
+				return err
+			}
+			c.mu.Lock()
+			token = c.token
+			c.mu.Unlock()
+		}
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Authorization", "AR-JWT "+token)
+		request.Header.Set("Accept", "application/json")
+
+		response, err := c.httpClient().Do(request)
+		if err != nil {
+			return fmt.Errorf("failed to query remedy: %w", err)
+		}
+
+		if response.StatusCode == http.StatusUnauthorized && !fresh && !retried {
+			response.Body.Close()
+			retried = true
+			c.mu.Lock()
+			if c.token == token {
+				c.token = ""
+			}
+			c.mu.Unlock()
+			continue
+		}
+
+		err = decodeResponse(response, out)
+		response.Body.Close()
+		return err
+	}
+}
+
+func decodeResponse(response *http.Response, out interface{}) error {
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+		return responseError(response.StatusCode, body)
+	}
+	if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode remedy response: %w", err)
+	}
+	return nil
+}
+
+// responseError đọc message lỗi của Remedy: [{"messageType":"ERROR","messageText":"...","messageAppendedText":"..."}]
+func responseError(status int, body []byte) error {
+	var messages []struct {
+		MessageText         string `json:"messageText"`
+		MessageAppendedText string `json:"messageAppendedText"`
+	}
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &messages) == nil && len(messages) > 0 {
+		parts := make([]string, 0, len(messages))
+		for _, m := range messages {
+			text := m.MessageText
+			if m.MessageAppendedText != "" {
+				text += " (" + m.MessageAppendedText + ")"
+			}
+			parts = append(parts, text)
+		}
+		message = strings.Join(parts, "; ")
+	}
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	return &Error{StatusCode: status, Message: message}
+}
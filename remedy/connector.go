@@ -0,0 +1,339 @@
+package remedy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/ipaddr"
+	"github.com/sllpklls/template-backend-go/mapping"
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/pipeline"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+// Actor ghi vào LastModifiedBy và lịch sử của asset do connector tạo/sửa
+const Actor = "remedy-sync"
+
+const (
+	DefaultName     = "remedy"
+	DefaultForm     = "BMC.CORE:BMC_IPEndpoint"
+	DefaultDataset  = "BMC.ASSET"
+	DefaultPageSize = 500
+
+	// ModifiedField là field core của AR (id 6) dùng làm high-water mark, có ở mọi form
+	ModifiedField = "Last Modified Date"
+	// InstanceIdField phân định thứ tự các bản ghi cùng Last Modified Date khi phân trang
+	InstanceIdField = "InstanceId"
+)
+
+// DefaultMapping là mapping attribute của BMC_IPEndpoint -> field của NetworkAsset
+var DefaultMapping = mapping.MustParse("remedy", `
+name: Remedy BMC_IPEndpoint
+fields:
+  name: Name
+  instance_id: InstanceId
+  request_id: Request ID
+  address: Address
+  subnet_mask: SubnetMask
+  address_type: AddressType
+  protocol_type: ProtocolType
+  dns_host_name: DNSHostName
+  short_description: ShortDescription
+  description: Description
+`)
+
+// Connector đồng bộ BMC_IPEndpoint từ Remedy vào dataset DatasetId, khớp asset theo InstanceId.
+// Mỗi lần chạy chỉ đọc bản ghi có Last Modified Date >= high-water mark của lần trước; mỗi trang được
+// ghi trong một transaction và high-water mark tăng theo từng trang đã ghi, nên lần chạy bị ngắt
+// giữa chừng tiếp tục từ trang cuối cùng đã ghi. Trang sau được đọc bằng keyset (Last Modified Date,
+// InstanceId) của bản ghi cuối trang trước thay vì offset: bản ghi bị sửa trong lúc chạy chuyển xuống
+// cuối thứ tự sắp xếp và không làm lệch vị trí các bản ghi chưa đọc.
+type Connector struct {
+	Client *Client
+	Repo   repository.NetworkAssetRepo
+	State  repository.ConnectorRepo
+
+	Name      string           // khóa trong ConnectorStates, mặc định DefaultName
+	Form      string           // mặc định DefaultForm
+	Dataset   string           // DatasetId phía Remedy, mặc định DefaultDataset
+	DatasetId int              // dataset đích
+	Mapping   *mapping.Mapping // mặc định DefaultMapping, phải ghi instance_id
+	PageSize  int
+	Actor     string // ghi vào lịch sử của asset, mặc định Actor
+
+	running sync.Mutex
+}
+
+// Run đồng bộ các bản ghi thay đổi từ lần chạy trước, full=true thì đọc lại toàn bộ.
+// Trả về errors.ConnectorRunning nếu lần chạy trước chưa xong.
+func (c *Connector) Run(ctx context.Context, full bool) (*model.ConnectorRun, error) {
+	if !c.running.TryLock() {
+		return nil, errors.ConnectorRunning
+	}
+	defer c.running.Unlock()
+
+	name := c.name()
+	state, err := c.State.GetConnectorState(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &model.ConnectorRun{Name: name, StartedAt: time.Now(), HighWaterMark: state.HighWaterMark}
+	if !full {
+		run.Since = state.HighWaterMark
+	}
+
+	runErr := c.sync(ctx, run)
+	if err := c.Client.Logout(ctx); err != nil {
+		log.Warnf("remedy logout failed: %v", err)
+	}
+	run.FinishedAt = time.Now()
+
+	message := ""
+	if runErr != nil {
+		message = runErr.Error()
+	}
+	if err := c.State.FinishConnectorRun(ctx, name, run.StartedAt, message); err != nil {
+		return run, err
+	}
+	return run, runErr
+}
+
+func (c *Connector) sync(ctx context.Context, run *model.ConnectorRun) error {
+	m := c.Mapping
+	if m == nil {
+		m = DefaultMapping
+	}
+	if !m.Has("instance_id") {
+		return fmt.Errorf("remedy mapping %s must map a field to instance_id", m.Id)
+	}
+
+	pageSize := c.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	query := EntryQuery{
+		Qualification: qualification(c.dataset(), run.Since),
+		Sort:          ModifiedField + ".asc," + InstanceIdField + ".asc",
+		Fields:        attributes(m),
+		Limit:         pageSize,
+	}
+
+	for {
+		page, err := c.Client.GetEntries(ctx, c.form(), query)
+		if err != nil {
+			return err
+		}
+		if len(page.Entries) == 0 {
+			return nil
+		}
+
+		rows := c.mapEntries(page.Entries, run.Read, m, run)
+		run.Read += len(page.Entries)
+		report, err := c.importPage(ctx, rows)
+		if err != nil {
+			return err
+		}
+		run.AddReport(report)
+
+		last := page.Entries[len(page.Entries)-1]
+		mark, err := parseTime(last.Values[ModifiedField])
+		if err != nil {
+			return fmt.Errorf("cannot page past entry %d: %w", run.Read, err)
+		}
+		if err := c.State.SaveHighWaterMark(ctx, run.Name, mark); err != nil {
+			return err
+		}
+		if run.HighWaterMark == nil || mark.After(*run.HighWaterMark) {
+			run.HighWaterMark = &mark
+		}
+
+		if !page.HasNext {
+			return nil
+		}
+		query.Qualification = after(c.dataset(), mark, stringValue(last.Values[InstanceIdField]))
+	}
+}
+
+// importPage ghi một trang. Dòng lỗi (dữ liệu không hợp lệ) được bỏ ra và trang được ghi lại với
+// các dòng còn lại, để một bản ghi lỗi không chặn high-water mark mãi mãi; bản ghi đó sẽ được đọc
+// lại khi được sửa trên Remedy.
+func (c *Connector) importPage(ctx context.Context, rows []model.ImportRow) (*model.ImportReport, error) {
+	opts := model.ImportOptions{Mode: model.ImportUpsert, DatasetId: c.DatasetId}
+	return pipeline.ImportSkippingFailed(rows, func(rows []model.ImportRow) (*model.ImportReport, error) {
+		return c.Repo.ImportByInstanceId(ctx, rows, opts, c.actor())
+	})
+}
+
+// mapEntries chuyển bản ghi Remedy thành dòng import (Row là thứ tự đọc trong lần chạy, tính từ 1).
+// Bản ghi thiếu InstanceId hoặc Last Modified Date được tính là lỗi.
+func (c *Connector) mapEntries(entries []Entry, offset int, m *mapping.Mapping, run *model.ConnectorRun) []model.ImportRow {
+	var rows []model.ImportRow
+	for i, entry := range entries {
+		row := offset + i + 1
+		values, err := mapEntry(entry, m)
+		if err != nil {
+			run.Failed++
+			run.Errors = append(run.Errors, model.FieldError{Field: fmt.Sprintf("entry %d", row), Message: err.Error()})
+			continue
+		}
+		rows = append(rows, model.ImportRow{Row: row, Values: values})
+	}
+	return rows
+}
+
+func mapEntry(entry Entry, m *mapping.Mapping) (map[string]string, error) {
+	source := make(map[string]string, len(entry.Values))
+	for attribute, v := range entry.Values {
+		source[attribute] = stringValue(v)
+	}
+	values, errs := m.Apply(source)
+	if len(errs) > 0 {
+		parts := make([]string, 0, len(errs))
+		for _, fe := range errs {
+			parts = append(parts, fe.Field+": "+fe.Message)
+		}
+		return nil, fmt.Errorf("%s: %s", source[InstanceIdField], strings.Join(parts, "; "))
+	}
+	if values["instance_id"] == "" {
+		return nil, fmt.Errorf("InstanceId is empty")
+	}
+	if _, err := parseTime(entry.Values[ModifiedField]); err != nil {
+		return nil, fmt.Errorf("%s: %w", values["instance_id"], err)
+	}
+
+	// AddressType của Remedy có thể là "Other"/"Unknown", để trống thì được suy ra từ Address
+	if t, ok := values["address_type"]; ok && ipaddr.NormalizeFamily(t) == "" {
+		delete(values, "address_type")
+	}
+	if deleted := stringValue(entry.Values["MarkAsDeleted"]); strings.EqualFold(deleted, "Yes") || deleted == "1" {
+		values["mark_as_deleted"] = "true"
+	}
+	return values, nil
+}
+
+// qualification lọc theo dataset Remedy và high-water mark. Dùng >= vì Last Modified Date chỉ chính xác
+// tới giây: bản ghi cùng giây với mark được đọc lại và bỏ qua nếu không đổi.
+func qualification(dataset string, since *time.Time) string {
+	q := fmt.Sprintf(`'DatasetId' = "%s"`, quote(dataset))
+	if since != nil {
+		q += fmt.Sprintf(" AND '%s' >= %d", ModifiedField, since.Unix())
+	}
+	return q
+}
+
+// after lọc các bản ghi đứng sau (mark, instanceId) theo thứ tự sắp xếp của sync
+func after(dataset string, mark time.Time, instanceId string) string {
+	return fmt.Sprintf(`'DatasetId' = "%s" AND ('%s' > %d OR ('%s' = %d AND '%s' > "%s"))`,
+		quote(dataset), ModifiedField, mark.Unix(), ModifiedField, mark.Unix(), InstanceIdField, quote(instanceId))
+}
+
+// quote escape chuỗi trong qualification của AR
+func quote(s string) string {
+	return strings.ReplaceAll(s, `"`, `""`)
+}
+
+func stringValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// parseTime đọc Last Modified Date dạng "2025-08-12T10:00:00.000+0000", RFC 3339 hoặc epoch giây
+func parseTime(v interface{}) (time.Time, error) {
+	switch v := v.(type) {
+	case float64:
+		return time.Unix(int64(v), 0).UTC(), nil
+	case string:
+		for _, layout := range []string{"2006-01-02T15:04:05.000-0700", "2006-01-02T15:04:05-0700", time.RFC3339Nano} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Unix(n, 0).UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %v", ModifiedField, v)
+}
+
+// Schedule đồng bộ mỗi interval cho tới khi ctx bị hủy
+func (c *Connector) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run, err := c.Run(ctx, false)
+			if err != nil {
+				log.Errorf("remedy sync failed: %v", err)
+				continue
+			}
+			log.Infof("remedy sync: %d read, %d created, %d updated, %d unchanged, %d deleted, %d failed",
+				run.Read, run.Created, run.Updated, run.Skipped, run.Deleted, run.Failed)
+		}
+	}
+}
+
+func (c *Connector) name() string {
+	if c.Name == "" {
+		return DefaultName
+	}
+	return c.Name
+}
+
+func (c *Connector) actor() string {
+	if c.Actor == "" {
+		return Actor
+	}
+	return c.Actor
+}
+
+func (c *Connector) form() string {
+	if c.Form == "" {
+		return DefaultForm
+	}
+	return c.Form
+}
+
+func (c *Connector) dataset() string {
+	if c.Dataset == "" {
+		return DefaultDataset
+	}
+	return c.Dataset
+}
+
+// attributes trả về các attribute cần đọc từ Remedy theo thứ tự cố định, nil (đọc mọi attribute)
+// nếu mapping không xác định trước được field nguồn
+func attributes(m *mapping.Mapping) []string {
+	sources, ok := m.Sources()
+	if !ok {
+		return nil
+	}
+	fixed := []string{ModifiedField, InstanceIdField, "MarkAsDeleted"}
+	attributes := append([]string{}, fixed...)
+	for _, attribute := range sources {
+		if attribute != ModifiedField && attribute != InstanceIdField && attribute != "MarkAsDeleted" {
+			attributes = append(attributes, attribute)
+		}
+	}
+	sort.Strings(attributes[len(fixed):])
+	return attributes
+}
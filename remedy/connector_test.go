@@ -0,0 +1,347 @@
+package remedy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sllpklls/template-backend-go/model"
+	"github.com/sllpklls/template-backend-go/repository"
+)
+
+// fakeRemedy giả lập AR REST API: login/logout AR-JWT và đọc entry của BMC_IPEndpoint, lọc theo
+// 'Last Modified Date' >= N hoặc keyset (Last Modified Date, InstanceId), sắp xếp theo hai field đó
+// và phân trang offset/limit.
+type fakeRemedy struct {
+	t *testing.T
+
+	mu          sync.Mutex
+	entries     []map[string]interface{}
+	token       string
+	logins      int
+	expireAfter int // số request GET hợp lệ trước khi token bị thu hồi, 0 là không thu hồi
+	served      int
+	queries     []map[string]string // q, sort, offset, limit của mỗi request GET hợp lệ
+	// afterPage được gọi (đang giữ mu) sau khi trả trang thứ n, tính từ 1
+	afterPage func(n int)
+}
+
+var (
+	sinceRe  = regexp.MustCompile(`'Last Modified Date' >= (\d+)`)
+	keysetRe = regexp.MustCompile(`\('Last Modified Date' > (\d+) OR \('Last Modified Date' = (\d+) AND 'InstanceId' > "([^"]*)"\)\)`)
+)
+
+func (f *fakeRemedy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/jwt/login":
+		if r.FormValue("username") != "svc" || r.FormValue("password") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `[{"messageType":"ERROR","messageText":"Authentication failed"}]`)
+			return
+		}
+		f.logins++
+		f.token = "token-" + strconv.Itoa(f.logins)
+		fmt.Fprint(w, f.token)
+
+	case r.Method == http.MethodPost && r.URL.Path == "/api/jwt/logout":
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodGet && r.URL.Path == "/api/arsys/v1/entry/"+DefaultForm:
+		if f.token == "" || r.Header.Get("Authorization") != "AR-JWT "+f.token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.served++
+		if f.expireAfter > 0 && f.served == f.expireAfter {
+			f.token = "" // token hiện tại hết hạn sau request này
+		}
+
+		params := r.URL.Query()
+		f.queries = append(f.queries, map[string]string{
+			"q": params.Get("q"), "sort": params.Get("sort"), "offset": params.Get("offset"), "limit": params.Get("limit"),
+		})
+		if !strings.HasPrefix(params.Get("q"), `'DatasetId' = "BMC.ASSET"`) {
+			f.t.Errorf("unexpected qualification %q", params.Get("q"))
+		}
+
+		match := func(values map[string]interface{}) bool { return true }
+		if m := sinceRe.FindStringSubmatch(params.Get("q")); m != nil {
+			since, _ := strconv.ParseInt(m[1], 10, 64)
+			match = func(values map[string]interface{}) bool { return modifiedAt(values).Unix() >= since }
+		}
+		if m := keysetRe.FindStringSubmatch(params.Get("q")); m != nil {
+			mark, _ := strconv.ParseInt(m[1], 10, 64)
+			match = func(values map[string]interface{}) bool {
+				modified := modifiedAt(values).Unix()
+				return modified > mark || modified == mark && values["InstanceId"].(string) > m[3]
+			}
+		}
+		var matched []map[string]interface{}
+		for _, values := range f.entries {
+			if match(values) {
+				matched = append(matched, values)
+			}
+		}
+		sort.SliceStable(matched, func(i, j int) bool {
+			a, b := modifiedAt(matched[i]), modifiedAt(matched[j])
+			if !a.Equal(b) {
+				return a.Before(b)
+			}
+			return matched[i]["InstanceId"].(string) < matched[j]["InstanceId"].(string)
+		})
+
+		offset, _ := strconv.Atoi(params.Get("offset"))
+		limit, _ := strconv.Atoi(params.Get("limit"))
+		end := len(matched)
+		if limit > 0 && offset+limit < end {
+			end = offset + limit
+		}
+		if offset > end {
+			offset = end
+		}
+
+		response := map[string]interface{}{"_links": map[string]interface{}{}}
+		entries := []map[string]interface{}{}
+		for _, values := range matched[offset:end] {
+			entries = append(entries, map[string]interface{}{"values": values})
+		}
+		response["entries"] = entries
+		if end < len(matched) {
+			response["_links"] = map[string]interface{}{"next": []map[string]string{{"href": "next"}}}
+		}
+		json.NewEncoder(w).Encode(response)
+		if f.afterPage != nil {
+			f.afterPage(len(f.queries))
+		}
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func modifiedAt(values map[string]interface{}) time.Time {
+	t, _ := parseTime(values[ModifiedField])
+	return t
+}
+
+func endpoint(instanceId, name, address string, modified time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"InstanceId":    instanceId,
+		"Name":          name,
+		"Address":       address,
+		"AddressType":   "IPv4",
+		"MarkAsDeleted": "No",
+		ModifiedField:   modified.UTC().Format("2006-01-02T15:04:05.000-0700"),
+	}
+}
+
+// fakeAssetRepo lưu asset theo instance_id, chỉ cài ImportByInstanceId
+type fakeAssetRepo struct {
+	repository.NetworkAssetRepo
+
+	assets map[string]map[string]string
+}
+
+func (r *fakeAssetRepo) ImportByInstanceId(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions, actor string) (*model.ImportReport, error) {
+	report := &model.ImportReport{Mode: opts.Mode, Committed: true}
+	for _, row := range rows {
+		id := row.Values["instance_id"]
+		result := model.ImportRowResult{Row: row.Row, Name: row.Values["name"]}
+		existing, ok := r.assets[id]
+		switch {
+		case !ok:
+			result.Status = model.ImportCreated
+		case reflect.DeepEqual(existing, row.Values):
+			result.Status = model.ImportSkipped
+		default:
+			result.Status = model.ImportUpdated
+		}
+		r.assets[id] = row.Values
+		report.Rows = append(report.Rows, result)
+		report.Add(result)
+	}
+	return report, nil
+}
+
+type fakeConnectorState struct {
+	state model.ConnectorState
+	marks []time.Time
+	runs  int
+}
+
+func (s *fakeConnectorState) GetConnectorState(ctx context.Context, name string) (model.ConnectorState, error) {
+	return s.state, nil
+}
+
+func (s *fakeConnectorState) SaveHighWaterMark(ctx context.Context, name string, mark time.Time) error {
+	s.marks = append(s.marks, mark)
+	if s.state.HighWaterMark == nil || mark.After(*s.state.HighWaterMark) {
+		s.state.HighWaterMark = &mark
+	}
+	return nil
+}
+
+func (s *fakeConnectorState) FinishConnectorRun(ctx context.Context, name string, runAt time.Time, runErr string) error {
+	s.runs++
+	s.state.LastRunAt = &runAt
+	s.state.LastError = runErr
+	return nil
+}
+
+func TestConnectorSync(t *testing.T) {
+	base := time.Date(2025, 8, 12, 10, 0, 0, 0, time.UTC)
+	remedy := &fakeRemedy{t: t, expireAfter: 1}
+	for i, minute := range []int{0, 1, 1, 3, 4} {
+		remedy.entries = append(remedy.entries, endpoint(
+			fmt.Sprintf("OI-%d", i), fmt.Sprintf("host-%d", i), fmt.Sprintf("10.0.0.%d", i+1), base.Add(time.Duration(minute)*time.Minute)))
+	}
+	server := httptest.NewServer(remedy)
+	defer server.Close()
+
+	repo := &fakeAssetRepo{assets: map[string]map[string]string{}}
+	state := &fakeConnectorState{}
+	connector := &Connector{
+		Client:    &Client{BaseURL: server.URL, Username: "svc", Password: "secret"},
+		Repo:      repo,
+		State:     state,
+		DatasetId: 4,
+		PageSize:  2,
+	}
+
+	// Lần đầu: đọc toàn bộ 3 trang, token hết hạn sau trang đầu nên phải login lại
+	run, err := connector.Run(context.Background(), false)
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if run.Read != 5 || run.Created != 5 || run.Failed != 0 {
+		t.Errorf("first run read %d, created %d, failed %d; want 5, 5, 0", run.Read, run.Created, run.Failed)
+	}
+	if remedy.logins != 2 {
+		t.Errorf("logins = %d, want 2 (re-login after 401)", remedy.logins)
+	}
+	if len(remedy.queries) != 3 {
+		t.Fatalf("requested %d pages, want 3", len(remedy.queries))
+	}
+	for i, q := range remedy.queries {
+		if q["sort"] != "Last Modified Date.asc,InstanceId.asc" || q["offset"] != "0" || q["limit"] != "2" {
+			t.Errorf("page %d: sort %q, offset %s, limit %s", i+1, q["sort"], q["offset"], q["limit"])
+		}
+	}
+	if strings.Contains(remedy.queries[0]["q"], ModifiedField) {
+		t.Errorf("first page must not filter by %s: %q", ModifiedField, remedy.queries[0]["q"])
+	}
+	// OI-1 và OI-2 cùng Last Modified Date, trang 2 bắt đầu sau OI-1 theo InstanceId
+	if want := fmt.Sprintf(`'DatasetId' = "BMC.ASSET" AND ('Last Modified Date' > %[1]d OR ('Last Modified Date' = %[1]d AND 'InstanceId' > "OI-1"))`,
+		base.Add(time.Minute).Unix()); remedy.queries[1]["q"] != want {
+		t.Errorf("page 2 qualification = %q, want %q", remedy.queries[1]["q"], want)
+	}
+	last := base.Add(4 * time.Minute)
+	if state.state.HighWaterMark == nil || !state.state.HighWaterMark.Equal(last) {
+		t.Fatalf("high-water mark = %v, want %v", state.state.HighWaterMark, last)
+	}
+	if len(state.marks) != 3 {
+		t.Errorf("high-water mark saved %d times, want once per page", len(state.marks))
+	}
+
+	// Lần sau: chỉ đọc từ high-water mark, bản ghi sửa trên Remedy được cập nhật, không tạo trùng
+	remedy.mu.Lock()
+	remedy.queries = nil
+	remedy.expireAfter = 0
+	remedy.entries[1] = endpoint("OI-1", "host-1", "10.0.1.2", last.Add(time.Minute))
+	remedy.mu.Unlock()
+
+	run, err = connector.Run(context.Background(), false)
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if want := fmt.Sprintf(`'DatasetId' = "BMC.ASSET" AND 'Last Modified Date' >= %d`, last.Unix()); remedy.queries[0]["q"] != want {
+		t.Errorf("qualification = %q, want %q", remedy.queries[0]["q"], want)
+	}
+	if run.Read != 2 || run.Created != 0 || run.Updated != 1 || run.Skipped != 1 {
+		t.Errorf("second run read %d, created %d, updated %d, skipped %d; want 2, 0, 1, 1",
+			run.Read, run.Created, run.Updated, run.Skipped)
+	}
+	if len(repo.assets) != 5 {
+		t.Errorf("repo has %d assets, want 5", len(repo.assets))
+	}
+	if got := repo.assets["OI-1"]["address"]; got != "10.0.1.2" {
+		t.Errorf("OI-1 address = %q, want 10.0.1.2", got)
+	}
+	if !state.state.HighWaterMark.Equal(last.Add(time.Minute)) {
+		t.Errorf("high-water mark = %v, want %v", state.state.HighWaterMark, last.Add(time.Minute))
+	}
+	if state.runs != 2 || state.state.LastError != "" {
+		t.Errorf("finished runs = %d, last error %q", state.runs, state.state.LastError)
+	}
+}
+
+func TestClientLoginFailure(t *testing.T) {
+	server := httptest.NewServer(&fakeRemedy{t: t})
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Username: "svc", Password: "wrong"}
+	_, err := client.GetEntries(context.Background(), DefaultForm, EntryQuery{Limit: 10})
+	remedyErr, ok := err.(*Error)
+	if !ok || remedyErr.StatusCode != http.StatusUnauthorized || remedyErr.Message != "Authentication failed" {
+		t.Fatalf("err = %v, want 401 Authentication failed", err)
+	}
+}
+
+func TestConnectorSyncEntryChangedDuringRun(t *testing.T) {
+	base := time.Date(2025, 8, 12, 10, 0, 0, 0, time.UTC)
+	remedy := &fakeRemedy{t: t}
+	for i := 0; i < 5; i++ {
+		remedy.entries = append(remedy.entries, endpoint(
+			fmt.Sprintf("OI-%d", i), fmt.Sprintf("host-%d", i), fmt.Sprintf("10.0.0.%d", i+1), base.Add(time.Duration(i)*time.Minute)))
+	}
+	// OI-0 đã đọc ở trang 1 bị sửa trước khi trang 2 được đọc: nó chuyển xuống cuối thứ tự sắp xếp,
+	// phân trang theo offset sẽ bỏ sót OI-2
+	remedy.afterPage = func(n int) {
+		if n == 1 {
+			remedy.entries[0] = endpoint("OI-0", "host-0", "10.0.1.1", base.Add(10*time.Minute))
+		}
+	}
+	server := httptest.NewServer(remedy)
+	defer server.Close()
+
+	repo := &fakeAssetRepo{assets: map[string]map[string]string{}}
+	state := &fakeConnectorState{}
+	connector := &Connector{
+		Client:   &Client{BaseURL: server.URL, Username: "svc", Password: "secret"},
+		Repo:     repo,
+		State:    state,
+		PageSize: 2,
+	}
+
+	run, err := connector.Run(context.Background(), false)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, ok := repo.assets[fmt.Sprintf("OI-%d", i)]; !ok {
+			t.Errorf("OI-%d was not synced", i)
+		}
+	}
+	if run.Read != 6 || run.Created != 5 || run.Updated != 1 {
+		t.Errorf("read %d, created %d, updated %d; want 6, 5, 1", run.Read, run.Created, run.Updated)
+	}
+	if got := repo.assets["OI-0"]["address"]; got != "10.0.1.1" {
+		t.Errorf("OI-0 address = %q, want 10.0.1.1", got)
+	}
+	if !state.state.HighWaterMark.Equal(base.Add(10 * time.Minute)) {
+		t.Errorf("high-water mark = %v, want %v", state.state.HighWaterMark, base.Add(10*time.Minute))
+	}
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// CIRepo là repository CRUD/search chung cho mọi class trong model.CIRegistry
+type CIRepo interface {
+	GetAllCIs(ctx context.Context, class *model.CIClass, filter model.CIFilter) ([]model.CIRecord, error)
+	GetTotalCIs(ctx context.Context, class *model.CIClass, filter model.CIFilter) (int, error)
+	GetCIByName(ctx context.Context, class *model.CIClass, name string) (model.CIRecord, error)
+	CreateCI(ctx context.Context, class *model.CIClass, record model.CIRecord) error
+	UpdateCI(ctx context.Context, class *model.CIClass, name string, record model.CIRecord) error
+	DeleteCI(ctx context.Context, class *model.CIClass, name string) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type ConnectorRepo interface {
+	// GetConnectorState trả về trạng thái rỗng nếu connector chưa chạy lần nào
+	GetConnectorState(ctx context.Context, name string) (model.ConnectorState, error)
+	// SaveHighWaterMark chỉ tăng high-water mark, không lùi
+	SaveHighWaterMark(ctx context.Context, name string, mark time.Time) error
+	// FinishConnectorRun ghi thời điểm chạy và lỗi (rỗng nếu thành công)
+	FinishConnectorRun(ctx context.Context, name string, runAt time.Time, runErr string) error
+}
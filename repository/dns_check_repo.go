@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type DNSCheckRepo interface {
+	// GetAssetsForDNSCheck trả về các asset có DNSHostName hoặc Address, gồm cả asset đã xóa mềm để
+	// phát hiện bản ghi DNS stale. names rỗng là mọi asset, datasetId 0 là mọi dataset.
+	GetAssetsForDNSCheck(ctx context.Context, names []string, datasetId int) ([]model.NetworkAsset, error)
+	// SaveDNSCheckResults ghi đè kết quả mới nhất của từng asset
+	SaveDNSCheckResults(ctx context.Context, results []model.DNSCheckResult) error
+	GetDNSCheckResults(ctx context.Context, status string, page, limit int) ([]model.DNSCheckResult, error)
+	GetTotalDNSCheckResults(ctx context.Context, status string) (int, error)
+}
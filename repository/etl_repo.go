@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type EtlRepo interface {
+	CreateJob(ctx context.Context, job model.EtlJob) (model.EtlJob, error)
+	GetJobs(ctx context.Context) ([]model.EtlJob, error)
+	GetJobById(ctx context.Context, id int64) (*model.EtlJob, error)
+	UpdateJob(ctx context.Context, id int64, job model.EtlJob) error
+	DeleteJob(ctx context.Context, id int64) error
+
+	CreateRun(ctx context.Context, run model.EtlRun) (model.EtlRun, error)
+	FinishRun(ctx context.Context, run model.EtlRun) error
+	// AbortRunningRuns đánh dấu Failed các lần chạy còn Running (server dừng khi đang chạy)
+	AbortRunningRuns(ctx context.Context) error
+	GetRunsByJob(ctx context.Context, jobId int64) ([]model.EtlRun, error)
+	GetRunById(ctx context.Context, id int64) (*model.EtlRun, error)
+}
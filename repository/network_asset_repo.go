@@ -2,21 +2,45 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/sllpklls/template-backend-go/model"
 )
 
 type NetworkAssetRepo interface {
-	GetAllNetworkAssets(ctx context.Context, page, limit int) ([]model.NetworkAssetList, error)
-	GetNetworkAssetByName(ctx context.Context, name string) (*model.NetworkAsset, error)
+	GetAllNetworkAssets(ctx context.Context, page, limit int, includeDeleted bool) ([]model.NetworkAssetList, error)
+	GetNetworkAssetByName(ctx context.Context, name string, includeDeleted bool) (*model.NetworkAsset, error)
+	GetNetworkAssetByNameAsOf(ctx context.Context, name string, asOf time.Time, includeDeleted bool) (*model.NetworkAsset, error)
+	// GetDualStackAddresses trả về các asset cùng SystemName và dataset có địa chỉ khác họ với asset,
+	// asOf khác zero thì đọc trạng thái tại thời điểm đó
+	GetDualStackAddresses(ctx context.Context, asset *model.NetworkAsset, asOf time.Time) ([]model.NetworkAssetList, error)
 	GetNetworkAssetsByFilter(ctx context.Context, filter model.NetworkAssetFilter) ([]model.NetworkAssetList, error)
-	GetNetworkAssetsByDNSHostName(ctx context.Context, dnsHostName string, page, limit int) ([]model.NetworkAssetList, error)
-	GetTotalNetworkAssetsByDNSHostName(ctx context.Context, dnsHostName string) (int, error)
-	GetTotalNetworkAssets(ctx context.Context) (int, error)
+	StreamNetworkAssetsByFilter(ctx context.Context, filter model.NetworkAssetFilter, fn func(asset *model.NetworkAsset) error) error
+	GetNetworkAssetsByDNSHostName(ctx context.Context, dnsHostName string, page, limit int, includeDeleted bool) ([]model.NetworkAssetList, error)
+	GetTotalNetworkAssetsByDNSHostName(ctx context.Context, dnsHostName string, includeDeleted bool) (int, error)
+	GetTotalNetworkAssets(ctx context.Context, includeDeleted bool) (int, error)
 	GetTotalNetworkAssetsByFilter(ctx context.Context, filter model.NetworkAssetFilter) (int, error)
-	CreateNetworkAsset(ctx context.Context, asset model.NetworkAsset) error
-	UpdateNetworkAsset(ctx context.Context, name string, asset model.NetworkAsset) error
-	DeleteNetworkAsset(ctx context.Context, name string) error
+	CreateNetworkAsset(ctx context.Context, asset model.NetworkAsset, actor string, allowDuplicateAddress bool) error
+	UpdateNetworkAsset(ctx context.Context, name string, asset model.NetworkAsset, actor string, allowDuplicateAddress bool) error
+	DeleteNetworkAsset(ctx context.Context, name, deletedBy, requestId string) error
+	RestoreNetworkAsset(ctx context.Context, name, actor, requestId string, allowDuplicateAddress bool) error
+	PurgeNetworkAssets(ctx context.Context, deletedBefore time.Time, actor string) ([]string, error)
+	ImportNetworkAssets(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions, actor string) (*model.ImportReport, error)
+	// ImportZone import các cặp hostname/địa chỉ đọc từ zone file, tên asset được gán tự động
+	ImportZone(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions, actor string) (*model.ImportReport, error)
+	// ImportLeases đồng bộ lease DHCP vào dataset, opts.DeactivateMissing thì asset không còn lease được chuyển sang inactive
+	ImportLeases(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions, actor string) (*model.ImportReport, error)
+	// ImportScan ghi host phát hiện được khi scan, kèm port mở và LastSeen
+	ImportScan(ctx context.Context, hosts []model.DiscoveredHost, opts model.ImportOptions, actor string) (*model.ImportReport, error)
+	// ImportByInstanceId đồng bộ bản ghi từ CMDB nguồn, khớp asset theo InstanceId
+	ImportByInstanceId(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions, actor string) (*model.ImportReport, error)
+	GetNetworkAssetPorts(ctx context.Context, name string) ([]model.NetworkAssetPort, error)
+	GetAssetsForZone(ctx context.Context, filter model.ZoneFilter) ([]model.NetworkAsset, error)
+
+	GetNetworkAssetHistory(ctx context.Context, name string, page, limit int) ([]model.NetworkAssetHistory, error)
+	GetTotalNetworkAssetHistory(ctx context.Context, name string) (int, error)
+
+	GetIPConflicts(ctx context.Context, datasetId int) ([]model.IPConflict, error)
 
 	GetIPEndpointByDNSHostName(ctx context.Context, dnsHostName string) (bool, error)
 }
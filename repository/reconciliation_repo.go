@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type ReconciliationRepo interface {
+	CreateJob(ctx context.Context, job model.ReconciliationJob) (model.ReconciliationJob, error)
+	GetJobs(ctx context.Context) ([]model.ReconciliationJob, error)
+	GetJobById(ctx context.Context, id int64) (*model.ReconciliationJob, error)
+	UpdateJob(ctx context.Context, id int64, job model.ReconciliationJob) error
+	DeleteJob(ctx context.Context, id int64) error
+
+	CreateRun(ctx context.Context, run model.ReconciliationRun) (model.ReconciliationRun, error)
+	FinishRun(ctx context.Context, run model.ReconciliationRun) error
+	GetRunsByJob(ctx context.Context, jobId int64) ([]model.ReconciliationRun, error)
+	GetRunById(ctx context.Context, id int64) (*model.ReconciliationRun, error)
+
+	GetNetworkAssetsByDatasets(ctx context.Context, datasetIds []int) ([]model.NetworkAsset, error)
+	SaveReconciliation(ctx context.Context, plan model.ReconciliationPlan, actor string) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type RelationshipRepo interface {
+	CreateRelationship(ctx context.Context, rel model.Relationship) (model.Relationship, error)
+	GetRelationshipsByCI(ctx context.Context, class, name string) ([]model.Relationship, error)
+	DeleteRelationship(ctx context.Context, class, name string, id int64) error
+	CIExists(ctx context.Context, class, name string) (bool, error)
+	GetImpact(ctx context.Context, class, name, direction string, depth, maxPaths int) (*model.ImpactResult, error)
+}
@@ -0,0 +1,230 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/sllpklls/template-backend-go/db"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// CIRepoImpl sinh câu SQL từ định nghĩa model.CIClass. Tên bảng và cột đã được
+// CIRegistry.Register kiểm tra nên có thể ghép trực tiếp vào câu lệnh.
+type CIRepoImpl struct {
+	sql *db.Sql
+}
+
+func NewCIRepo(sql *db.Sql) *CIRepoImpl {
+	return &CIRepoImpl{sql: sql}
+}
+
+func buildCIFilter(class *model.CIClass, filter model.CIFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	for _, f := range class.Fields {
+		value, ok := filter.Fields[f.Name]
+		if !ok {
+			continue
+		}
+		if s, isString := value.(string); isString && f.Type == model.CIFieldString {
+			conditions = append(conditions, fmt.Sprintf("%s ILIKE $%d", f.Column, argIndex))
+			args = append(args, "%"+s+"%")
+		} else {
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", f.Column, argIndex))
+			args = append(args, value)
+		}
+		argIndex++
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *CIRepoImpl) GetAllCIs(ctx context.Context, class *model.CIClass, filter model.CIFilter) ([]model.CIRecord, error) {
+	where, args := buildCIFilter(class, filter)
+	offset := (filter.Page - 1) * filter.Limit
+
+	query := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY createdate DESC LIMIT $%d OFFSET $%d",
+		strings.Join(class.Columns(), ", "), class.Table, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, offset)
+
+	rows, err := r.sql.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", class.Name, err)
+	}
+	defer rows.Close()
+
+	records := []model.CIRecord{}
+	for rows.Next() {
+		record, err := scanCIRecord(class, rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *CIRepoImpl) GetTotalCIs(ctx context.Context, class *model.CIClass, filter model.CIFilter) (int, error) {
+	where, args := buildCIFilter(class, filter)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", class.Table, where)
+
+	var total int
+	if err := r.sql.Db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to get total %s: %w", class.Name, err)
+	}
+	return total, nil
+}
+
+func (r *CIRepoImpl) GetCIByName(ctx context.Context, class *model.CIClass, name string) (model.CIRecord, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE name = $1", strings.Join(class.Columns(), ", "), class.Table)
+
+	rows, err := r.sql.Db.QueryContext(ctx, query, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", class.Name, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", class.Name, err)
+		}
+		return nil, errors.CINotFound
+	}
+	return scanCIRecord(class, rows)
+}
+
+func (r *CIRepoImpl) CreateCI(ctx context.Context, class *model.CIClass, record model.CIRecord) error {
+	var columns, placeholders []string
+	var args []interface{}
+	for _, f := range class.Fields {
+		value, ok := record[f.Name]
+		if !ok || f.ReadOnly {
+			continue
+		}
+		args = append(args, value)
+		columns = append(columns, f.Column)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		class.Table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+
+	if _, err := r.sql.Db.ExecContext(ctx, query, args...); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return errors.CIConflict
+		}
+		return fmt.Errorf("failed to create %s: %w", class.Name, err)
+	}
+	return nil
+}
+
+func (r *CIRepoImpl) UpdateCI(ctx context.Context, class *model.CIClass, name string, record model.CIRecord) error {
+	sets := []string{"modifieddate = NOW()"}
+	var args []interface{}
+	for _, f := range class.Fields {
+		value, ok := record[f.Name]
+		if !ok || f.ReadOnly {
+			continue
+		}
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", f.Column, len(args)))
+	}
+	args = append(args, name)
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE name = $%d", class.Table, strings.Join(sets, ", "), len(args))
+
+	tx, err := r.sql.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return errors.CIConflict
+		}
+		return fmt.Errorf("failed to update %s: %w", class.Name, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.CINotFound
+	}
+
+	if newName, ok := record["name"].(string); ok && newName != name {
+		if err := renameRelationshipsOf(ctx, tx, class.Name, name, newName); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *CIRepoImpl) DeleteCI(ctx context.Context, class *model.CIClass, name string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE name = $1", class.Table)
+
+	tx, err := r.sql.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", class.Name, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.CINotFound
+	}
+
+	if err := deleteRelationshipsOf(ctx, tx, class.Name, name); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanCIRecord(class *model.CIClass, rows *sql.Rows) (model.CIRecord, error) {
+	values := make([]interface{}, len(class.Fields))
+	pointers := make([]interface{}, len(class.Fields))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", class.Name, err)
+	}
+
+	record := model.CIRecord{}
+	for i, f := range class.Fields {
+		if b, ok := values[i].([]byte); ok {
+			record[f.Name] = string(b)
+		} else {
+			record[f.Name] = values[i]
+		}
+	}
+	return record, nil
+}
@@ -0,0 +1,49 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sllpklls/template-backend-go/db"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type ConnectorRepoImpl struct {
+	sql *db.Sql
+}
+
+func NewConnectorRepo(sql *db.Sql) *ConnectorRepoImpl {
+	return &ConnectorRepoImpl{sql: sql}
+}
+
+func (r *ConnectorRepoImpl) GetConnectorState(ctx context.Context, name string) (model.ConnectorState, error) {
+	state := model.ConnectorState{Name: name}
+	query := `SELECT name, highwatermark, lastrunat, lasterror FROM ConnectorStates WHERE name = $1`
+	if err := r.sql.Db.GetContext(ctx, &state, query, name); err != nil && err != sql.ErrNoRows {
+		return state, fmt.Errorf("failed to get connector state %s: %w", name, err)
+	}
+	return state, nil
+}
+
+func (r *ConnectorRepoImpl) SaveHighWaterMark(ctx context.Context, name string, mark time.Time) error {
+	query := `
+		INSERT INTO ConnectorStates (name, highwatermark) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET
+			highwatermark = GREATEST(ConnectorStates.highwatermark, EXCLUDED.highwatermark)`
+	if _, err := r.sql.Db.ExecContext(ctx, query, name, mark); err != nil {
+		return fmt.Errorf("failed to save high-water mark of %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *ConnectorRepoImpl) FinishConnectorRun(ctx context.Context, name string, runAt time.Time, runErr string) error {
+	query := `
+		INSERT INTO ConnectorStates (name, lastrunat, lasterror) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET lastrunat = EXCLUDED.lastrunat, lasterror = EXCLUDED.lasterror`
+	if _, err := r.sql.Db.ExecContext(ctx, query, name, runAt, runErr); err != nil {
+		return fmt.Errorf("failed to save connector run of %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package repo_impl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sllpklls/template-backend-go/db"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type DNSCheckRepoImpl struct {
+	sql *db.Sql
+}
+
+func NewDNSCheckRepo(sql *db.Sql) *DNSCheckRepoImpl {
+	return &DNSCheckRepoImpl{sql: sql}
+}
+
+const dnsCheckResultColumns = `assetname, dnshostname, address, markasdeleted, status, issues, forwardaddresses, reversenames, checkedat`
+
+func (r *DNSCheckRepoImpl) GetAssetsForDNSCheck(ctx context.Context, names []string, datasetId int) ([]model.NetworkAsset, error) {
+	query := `SELECT ` + networkAssetColumns + `
+		FROM NetworkAssets
+		WHERE (dnshostname <> '' OR address IS NOT NULL)
+		  AND (cardinality($1::text[]) = 0 OR name = ANY($1))
+		  AND ($2 = 0 OR datasetid = $2)
+		ORDER BY name`
+
+	if names == nil {
+		names = []string{}
+	}
+	assets := []model.NetworkAsset{}
+	if err := r.sql.Db.SelectContext(ctx, &assets, query, pq.Array(names), datasetId); err != nil {
+		return nil, fmt.Errorf("failed to query network assets for dns check: %w", err)
+	}
+	return assets, nil
+}
+
+// SaveDNSCheckResults bỏ qua kết quả của asset đã bị purge trong lúc đang kiểm tra
+func (r *DNSCheckRepoImpl) SaveDNSCheckResults(ctx context.Context, results []model.DNSCheckResult) error {
+	query := `
+		INSERT INTO DNSCheckResults (` + dnsCheckResultColumns + `)
+		SELECT :assetname, :dnshostname, :address, :markasdeleted, :status, :issues, :forwardaddresses, :reversenames, :checkedat
+		WHERE EXISTS (SELECT 1 FROM NetworkAssets WHERE name = :assetname)
+		ON CONFLICT (assetname) DO UPDATE SET
+			dnshostname = EXCLUDED.dnshostname,
+			address = EXCLUDED.address,
+			markasdeleted = EXCLUDED.markasdeleted,
+			status = EXCLUDED.status,
+			issues = EXCLUDED.issues,
+			forwardaddresses = EXCLUDED.forwardaddresses,
+			reversenames = EXCLUDED.reversenames,
+			checkedat = EXCLUDED.checkedat`
+
+	return withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		for _, result := range results {
+			if _, err := tx.NamedExecContext(ctx, query, result); err != nil {
+				return fmt.Errorf("failed to save dns check result of %s: %w", result.AssetName, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *DNSCheckRepoImpl) GetDNSCheckResults(ctx context.Context, status string, page, limit int) ([]model.DNSCheckResult, error) {
+	query := `SELECT ` + dnsCheckResultColumns + `
+		FROM DNSCheckResults
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY assetname
+		LIMIT $2 OFFSET $3`
+
+	results := []model.DNSCheckResult{}
+	if err := r.sql.Db.SelectContext(ctx, &results, query, status, limit, (page-1)*limit); err != nil {
+		return nil, fmt.Errorf("failed to query dns check results: %w", err)
+	}
+	return results, nil
+}
+
+func (r *DNSCheckRepoImpl) GetTotalDNSCheckResults(ctx context.Context, status string) (int, error) {
+	var total int
+	if err := r.sql.Db.GetContext(ctx, &total, `SELECT COUNT(*) FROM DNSCheckResults WHERE ($1 = '' OR status = $1)`, status); err != nil {
+		return 0, fmt.Errorf("failed to count dns check results: %w", err)
+	}
+	return total, nil
+}
@@ -0,0 +1,199 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sllpklls/template-backend-go/db"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type EtlRepoImpl struct {
+	sql *db.Sql
+}
+
+func NewEtlRepo(sql *db.Sql) *EtlRepoImpl {
+	return &EtlRepoImpl{sql: sql}
+}
+
+const etlJobColumns = `id, name, sourcetype, config, schedule, targetdatasetid, disabled, createdate, modifieddate`
+
+const etlRunColumns = `id, jobid, status, triggeredby, startedat, finishedat, read, inserted, updated,
+	skipped, deleted, failed, errormessage, errorlog`
+
+func (r *EtlRepoImpl) CreateJob(ctx context.Context, job model.EtlJob) (model.EtlJob, error) {
+	query := `
+		INSERT INTO EtlJobs (name, sourcetype, config, schedule, targetdatasetid, disabled)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, createdate`
+
+	err := r.sql.Db.QueryRowContext(ctx, query,
+		job.Name,
+		job.SourceType,
+		job.Config,
+		job.Schedule,
+		job.TargetDatasetId,
+		job.Disabled,
+	).Scan(&job.Id, &job.CreateDate)
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return job, errors.EtlJobConflict
+		}
+		return job, fmt.Errorf("failed to create ETL job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (r *EtlRepoImpl) GetJobs(ctx context.Context) ([]model.EtlJob, error) {
+	query := `SELECT ` + etlJobColumns + ` FROM EtlJobs ORDER BY id`
+
+	jobs := []model.EtlJob{}
+	if err := r.sql.Db.SelectContext(ctx, &jobs, query); err != nil {
+		return nil, fmt.Errorf("failed to query ETL jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *EtlRepoImpl) GetJobById(ctx context.Context, id int64) (*model.EtlJob, error) {
+	query := `SELECT ` + etlJobColumns + ` FROM EtlJobs WHERE id = $1`
+
+	var job model.EtlJob
+	if err := r.sql.Db.GetContext(ctx, &job, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.EtlJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get ETL job: %w", err)
+	}
+	return &job, nil
+}
+
+func (r *EtlRepoImpl) UpdateJob(ctx context.Context, id int64, job model.EtlJob) error {
+	query := `
+		UPDATE EtlJobs SET
+			name = $1, sourcetype = $2, config = $3, schedule = $4,
+			targetdatasetid = $5, disabled = $6, modifieddate = NOW()
+		WHERE id = $7`
+
+	result, err := r.sql.Db.ExecContext(ctx, query,
+		job.Name,
+		job.SourceType,
+		job.Config,
+		job.Schedule,
+		job.TargetDatasetId,
+		job.Disabled,
+		id,
+	)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return errors.EtlJobConflict
+		}
+		return fmt.Errorf("failed to update ETL job: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.EtlJobNotFound
+	}
+	return nil
+}
+
+func (r *EtlRepoImpl) DeleteJob(ctx context.Context, id int64) error {
+	result, err := r.sql.Db.ExecContext(ctx, "DELETE FROM EtlJobs WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete ETL job: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.EtlJobNotFound
+	}
+	return nil
+}
+
+func (r *EtlRepoImpl) CreateRun(ctx context.Context, run model.EtlRun) (model.EtlRun, error) {
+	query := `
+		INSERT INTO EtlRuns (jobid, status, triggeredby)
+		VALUES ($1, $2, $3)
+		RETURNING id, startedat`
+
+	err := r.sql.Db.QueryRowContext(ctx, query, run.JobId, run.Status, run.TriggeredBy).
+		Scan(&run.Id, &run.StartedAt)
+	if err != nil {
+		return run, fmt.Errorf("failed to create ETL run: %w", err)
+	}
+	return run, nil
+}
+
+func (r *EtlRepoImpl) FinishRun(ctx context.Context, run model.EtlRun) error {
+	query := `
+		UPDATE EtlRuns SET
+			status = $1, finishedat = NOW(), read = $2, inserted = $3, updated = $4,
+			skipped = $5, deleted = $6, failed = $7, errormessage = $8, errorlog = $9
+		WHERE id = $10`
+
+	_, err := r.sql.Db.ExecContext(ctx, query,
+		run.Status,
+		run.Read,
+		run.Inserted,
+		run.Updated,
+		run.Skipped,
+		run.Deleted,
+		run.Failed,
+		run.ErrorMessage,
+		run.ErrorLog,
+		run.Id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish ETL run: %w", err)
+	}
+	return nil
+}
+
+func (r *EtlRepoImpl) AbortRunningRuns(ctx context.Context) error {
+	query := `
+		UPDATE EtlRuns SET status = $1, finishedat = NOW(), errormessage = 'interrupted by server restart'
+		WHERE status = $2`
+
+	if _, err := r.sql.Db.ExecContext(ctx, query, model.EtlFailed, model.EtlRunning); err != nil {
+		return fmt.Errorf("failed to abort running ETL runs: %w", err)
+	}
+	return nil
+}
+
+func (r *EtlRepoImpl) GetRunsByJob(ctx context.Context, jobId int64) ([]model.EtlRun, error) {
+	query := `SELECT ` + etlRunColumns + `
+		FROM EtlRuns
+		WHERE jobid = $1
+		ORDER BY startedat DESC
+		LIMIT 100`
+
+	runs := []model.EtlRun{}
+	if err := r.sql.Db.SelectContext(ctx, &runs, query, jobId); err != nil {
+		return nil, fmt.Errorf("failed to query ETL runs: %w", err)
+	}
+	return runs, nil
+}
+
+func (r *EtlRepoImpl) GetRunById(ctx context.Context, id int64) (*model.EtlRun, error) {
+	query := `SELECT ` + etlRunColumns + ` FROM EtlRuns WHERE id = $1`
+
+	var run model.EtlRun
+	if err := r.sql.Db.GetContext(ctx, &run, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.EtlRunNotFound
+		}
+		return nil, fmt.Errorf("failed to get ETL run: %w", err)
+	}
+	return &run, nil
+}
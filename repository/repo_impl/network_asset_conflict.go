@@ -0,0 +1,119 @@
+package repo_impl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// checkAddressConflict trả về errors.AddressConflict nếu địa chỉ của asset đang được asset khác
+// (chưa xóa) trong cùng dataset và VRF sử dụng. Advisory lock theo (dataset, VRF, địa chỉ) giữ đến hết
+// transaction nên hai request ghi cùng địa chỉ không thể cùng vượt qua bước kiểm tra.
+func checkAddressConflict(ctx context.Context, ex sqlx.ExtContext, asset model.NetworkAsset) error {
+	if asset.Address == "" {
+		return nil
+	}
+
+	key := fmt.Sprintf("networkasset-address:%d:%d:%s", asset.DatasetId, asset.VrfId, asset.Address)
+	if _, err := ex.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", key); err != nil {
+		return fmt.Errorf("failed to lock address %s: %w", asset.Address, err)
+	}
+
+	query := `
+		SELECT name FROM NetworkAssets
+		WHERE address = $1::inet AND datasetid = $2 AND COALESCE(vrfid, 0) = $4
+		  AND name <> $3 AND markasdeleted = false
+		ORDER BY name`
+
+	var names []string
+	if err := sqlx.SelectContext(ctx, ex, &names, query, asset.Address, asset.DatasetId, asset.Name, asset.VrfId); err != nil {
+		return fmt.Errorf("failed to check address conflict: %w", err)
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("%w: %s used by %s", errors.AddressConflict, asset.Address, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// GetIPConflicts liệt kê các địa chỉ đang được nhiều asset trong cùng dataset và VRF sử dụng,
+// datasetId = 0 là mọi dataset
+func (r *NetworkAssetRepoImpl) GetIPConflicts(ctx context.Context, datasetId int) ([]model.IPConflict, error) {
+	query := `
+		SELECT host(n.address) AS address, n.datasetid, COALESCE(n.vrfid, 0), n.name, n.systemname,
+		       n.shortdescription, n.protocoltype, n.addresstype, n.dnshostname, n.createdate,
+		       COALESCE(n.vlanid, 0)
+		FROM NetworkAssets n
+		WHERE n.markasdeleted = false AND n.address IS NOT NULL
+		  AND ($1 = 0 OR n.datasetid = $1)
+		  AND EXISTS (
+		      SELECT 1 FROM NetworkAssets d
+		      WHERE d.address = n.address AND d.datasetid = n.datasetid
+		        AND d.vrfid IS NOT DISTINCT FROM n.vrfid
+		        AND d.name <> n.name AND d.markasdeleted = false
+		  )
+		ORDER BY n.address, n.datasetid, COALESCE(n.vrfid, 0), n.name`
+
+	rows, err := r.sql.Db.QueryContext(ctx, query, datasetId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ip conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	conflicts := []model.IPConflict{}
+	for rows.Next() {
+		var asset model.NetworkAssetList
+		var datasetId int
+		if err := rows.Scan(&asset.Address, &datasetId, &asset.VrfId, &asset.Name, &asset.SystemName,
+			&asset.ShortDescription, &asset.ProtocolType, &asset.AddressType, &asset.DNSHostName,
+			&asset.CreateDate, &asset.VlanId); err != nil {
+			return nil, fmt.Errorf("failed to scan ip conflict: %w", err)
+		}
+
+		last := len(conflicts) - 1
+		if last < 0 || conflicts[last].Address != asset.Address || conflicts[last].DatasetId != datasetId || conflicts[last].VrfId != asset.VrfId {
+			conflicts = append(conflicts, model.IPConflict{Address: asset.Address, DatasetId: datasetId, VrfId: asset.VrfId})
+			last++
+		}
+		conflicts[last].Assets = append(conflicts[last].Assets, asset)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+// checkNameConflict trả về errors.NetworkAssetConflict nếu Name hoặc InstanceId (khác rỗng) đã thuộc về
+// asset khác, kể cả asset đã xóa mềm (unique index không phân biệt), và cho biết asset đó đã bị xóa chưa
+func checkNameConflict(ctx context.Context, ex sqlx.ExtContext, asset model.NetworkAsset) error {
+	var existing []struct {
+		Name          string `db:"name"`
+		InstanceId    string `db:"instanceid"`
+		MarkAsDeleted bool   `db:"markasdeleted"`
+	}
+	query := `
+		SELECT name, instanceid, markasdeleted FROM NetworkAssets
+		WHERE name = $1 OR ($2 <> '' AND instanceid = $2)
+		ORDER BY name = $1 DESC
+		LIMIT 1`
+	if err := sqlx.SelectContext(ctx, ex, &existing, query, asset.Name, asset.InstanceId); err != nil {
+		return fmt.Errorf("failed to check name conflict: %w", err)
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	clash := "name " + asset.Name
+	if existing[0].Name != asset.Name {
+		clash = fmt.Sprintf("instance_id %s (asset %s)", asset.InstanceId, existing[0].Name)
+	}
+	if existing[0].MarkAsDeleted {
+		return fmt.Errorf("%w: %s belongs to a soft-deleted asset, restore or purge it first", errors.NetworkAssetConflict, clash)
+	}
+	return fmt.Errorf("%w: %s", errors.NetworkAssetConflict, clash)
+}
@@ -0,0 +1,106 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// networkAssetsAsOf dựng lại bảng NetworkAssets tại thời điểm $1 từ snapshot mới nhất của mỗi asset
+// trong lịch sử (snapshot NULL là đã purge). Asset chưa có dòng lịch sử nào (insert bằng SQL tay)
+// lấy trạng thái hiện tại nếu đã được tạo trước $1. Snapshot trước khi Address chuyển sang INET
+// có thể chứa địa chỉ không hợp lệ nên address được ép kiểu an toàn.
+const networkAssetsAsOf = `(
+		SELECT n.* FROM (
+			SELECT DISTINCT ON (assetname) snapshot
+			FROM NetworkAssetHistory
+			WHERE changedat <= $1
+			ORDER BY assetname, changedat DESC, id DESC
+		) h
+		CROSS JOIN LATERAL jsonb_populate_record(NULL::NetworkAssets,
+			h.snapshot || jsonb_build_object('address', networkassets_try_inet(h.snapshot->>'address'))) n
+		WHERE h.snapshot IS NOT NULL
+		UNION ALL
+		SELECT a.* FROM NetworkAssets a
+		WHERE a.createdate <= $1
+		  AND NOT EXISTS (SELECT 1 FROM NetworkAssetHistory x WHERE x.assetname = a.name)
+	) AS NetworkAssets`
+
+// getNetworkAssetForUpdate khóa và đọc bản ghi trước khi sửa, nil nếu không tồn tại
+func getNetworkAssetForUpdate(ctx context.Context, q sqlx.QueryerContext, name string) (*model.NetworkAsset, error) {
+	query := `SELECT ` + networkAssetColumns + ` FROM NetworkAssets WHERE name = $1 FOR UPDATE`
+
+	var asset model.NetworkAsset
+	if err := sqlx.GetContext(ctx, q, &asset, query, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get network asset: %w", err)
+	}
+	return &asset, nil
+}
+
+// recordNetworkAssetChange ghi một dòng NetworkAssetHistory sau khi bản ghi đã thay đổi,
+// phải được gọi trong cùng transaction với thao tác ghi
+func recordNetworkAssetChange(ctx context.Context, ex sqlx.ExtContext, action, name string, before *model.NetworkAsset, actor, requestId string) error {
+	after, err := getNetworkAssetForUpdate(ctx, ex, name)
+	if err != nil {
+		return err
+	}
+
+	changes := model.DiffNetworkAssets(before, after)
+	if len(changes) == 0 && action == model.HistoryUpdate {
+		return nil
+	}
+
+	query := `
+		INSERT INTO NetworkAssetHistory (assetname, action, changes, snapshot, actor, requestid)
+		VALUES ($1, $2, $3, (SELECT to_jsonb(n) FROM NetworkAssets n WHERE n.name = $1), $4, $5)`
+
+	if _, err := ex.ExecContext(ctx, query, name, action, changes, actor, requestId); err != nil {
+		return fmt.Errorf("failed to record network asset history: %w", err)
+	}
+	return nil
+}
+
+func (r *NetworkAssetRepoImpl) GetNetworkAssetHistory(ctx context.Context, name string, page, limit int) ([]model.NetworkAssetHistory, error) {
+	query := `
+		SELECT id, assetname, action, changes, actor, requestid, changedat
+		FROM NetworkAssetHistory
+		WHERE assetname = $1
+		ORDER BY changedat DESC, id DESC
+		LIMIT $2 OFFSET $3`
+
+	history := []model.NetworkAssetHistory{}
+	if err := r.sql.Db.SelectContext(ctx, &history, query, name, limit, (page-1)*limit); err != nil {
+		return nil, fmt.Errorf("failed to query network asset history: %w", err)
+	}
+	return history, nil
+}
+
+func (r *NetworkAssetRepoImpl) GetTotalNetworkAssetHistory(ctx context.Context, name string) (int, error) {
+	var total int
+	query := "SELECT COUNT(*) FROM NetworkAssetHistory WHERE assetname = $1"
+	if err := r.sql.Db.QueryRowContext(ctx, query, name).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to get total network asset history: %w", err)
+	}
+	return total, nil
+}
+
+func (r *NetworkAssetRepoImpl) GetNetworkAssetByNameAsOf(ctx context.Context, name string, asOf time.Time, includeDeleted bool) (*model.NetworkAsset, error) {
+	query := `
+		SELECT ` + networkAssetColumns + `
+		FROM ` + networkAssetsAsOf + `
+		WHERE name = $2 AND ` + activeCondition(includeDeleted)
+
+	var asset model.NetworkAsset
+	if err := r.sql.Db.GetContext(ctx, &asset, query, asOf, name); err != nil {
+		return nil, fmt.Errorf("failed to get network asset as of %s: %w", asOf.Format(time.RFC3339), err)
+	}
+
+	return &asset, nil
+}
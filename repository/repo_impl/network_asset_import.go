@@ -0,0 +1,226 @@
+package repo_impl
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// ImportNetworkAssets ghi toàn bộ file trong một transaction. Lỗi của từng dòng được ghi vào
+// report (mỗi dòng chạy trong savepoint nên lỗi DB không làm hỏng transaction); chỉ commit khi
+// không có dòng nào lỗi và không phải dry run, nên file lỗi không bao giờ được ghi một nửa.
+func (r *NetworkAssetRepoImpl) ImportNetworkAssets(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions, actor string) (*model.ImportReport, error) {
+	tx, err := r.sql.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	return importNetworkAssets(ctx, tx, rows, opts, actor, nil)
+}
+
+// importNetworkAssets ghi các dòng trong tx và commit nếu không có dòng lỗi, dùng chung cho mọi nguồn
+// import. finish (có thể nil) chạy sau khi mọi dòng thành công, trước khi commit.
+func importNetworkAssets(ctx context.Context, tx *sqlx.Tx, rows []model.ImportRow, opts model.ImportOptions, actor string,
+	finish func(seen map[string]int, report *model.ImportReport) error) (*model.ImportReport, error) {
+	report := &model.ImportReport{Mode: opts.Mode, DryRun: opts.DryRun, Rows: []model.ImportRowResult{}}
+
+	seen := map[string]int{}
+	for _, row := range rows {
+		result, err := importNetworkAssetRow(ctx, tx, row, opts, actor, seen)
+		if err != nil {
+			return nil, err
+		}
+		report.Add(result)
+	}
+
+	if opts.Mode == model.ImportReplaceDataset && report.Failed == 0 {
+		if err := deleteMissingFromDataset(ctx, tx, opts, actor, seen, report); err != nil {
+			return nil, err
+		}
+	}
+	if finish != nil && report.Failed == 0 {
+		if err := finish(seen, report); err != nil {
+			return nil, err
+		}
+	}
+
+	if report.Failed > 0 || opts.DryRun {
+		return report, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+	report.Committed = true
+
+	return report, nil
+}
+
+// importNetworkAssetRow xử lý một dòng, chỉ trả về error khi lỗi không thuộc về dòng dữ liệu
+func importNetworkAssetRow(ctx context.Context, tx *sqlx.Tx, row model.ImportRow, opts model.ImportOptions, actor string, seen map[string]int) (model.ImportRowResult, error) {
+	name := row.Values["name"]
+	result := model.ImportRowResult{Row: row.Row, Name: name}
+
+	fail := func(errs ...model.FieldError) (model.ImportRowResult, error) {
+		result.Status = model.ImportFailed
+		result.Errors = errs
+		return result, nil
+	}
+
+	if name == "" {
+		return fail(model.FieldError{Field: "name", Message: "required"})
+	}
+	if prev, ok := seen[name]; ok {
+		return fail(model.FieldError{Field: "name", Message: fmt.Sprintf("duplicate of row %d", prev)})
+	}
+	seen[name] = row.Row
+
+	existing, err := getNetworkAssetForUpdate(ctx, tx, name)
+	if err != nil {
+		return result, err
+	}
+	if existing != nil && existing.MarkAsDeleted {
+		return fail(model.FieldError{Field: "name", Message: "network asset is deleted, restore it first"})
+	}
+	if existing != nil && opts.Mode == model.ImportInsert {
+		result.Status = model.ImportSkipped
+		result.Message = "already exists"
+		return result, nil
+	}
+	if existing != nil && opts.Mode == model.ImportReplaceDataset && existing.DatasetId != opts.DatasetId {
+		return fail(model.FieldError{Field: "name", Message: fmt.Sprintf("belongs to dataset %d", existing.DatasetId)})
+	}
+
+	// Upsert chỉ ghi đè các cột có trong file
+	var asset model.NetworkAsset
+	if existing != nil {
+		asset = *existing
+	}
+	if _, ok := row.Values["dataset_id"]; !ok && existing == nil {
+		asset.DatasetId = opts.DatasetId
+	}
+	if _, ok := row.Values["request_id"]; !ok {
+		asset.RequestId = opts.RequestId
+	}
+	var fieldErrs []model.FieldError
+	for field, value := range row.Values {
+		if err := asset.SetField(field, value); err != nil {
+			fieldErrs = append(fieldErrs, model.FieldError{Field: field, Message: err.Error()})
+		}
+	}
+	fieldErrs = append(fieldErrs, asset.Validate()...)
+	if opts.Mode == model.ImportReplaceDataset && asset.DatasetId != opts.DatasetId {
+		fieldErrs = append(fieldErrs, model.FieldError{Field: "dataset_id", Message: fmt.Sprintf("must be %d", opts.DatasetId)})
+	}
+	if len(fieldErrs) > 0 {
+		return fail(fieldErrs...)
+	}
+
+	if existing != nil && len(model.DiffNetworkAssets(existing, &asset)) == 0 {
+		result.Status = model.ImportSkipped
+		result.Message = "unchanged"
+		return result, nil
+	}
+
+	err = withSavepoint(ctx, tx, func() error {
+		if existing == nil {
+			return insertNetworkAsset(ctx, tx, asset, actor, opts.AllowDuplicateAddress)
+		}
+		return updateNetworkAsset(ctx, tx, name, asset, actor, opts.AllowDuplicateAddress)
+	})
+	if stderrors.Is(err, errors.AddressConflict) {
+		return fail(model.FieldError{Field: "address", Message: err.Error()})
+	}
+	if err != nil {
+		return fail(model.FieldError{Field: "row", Message: importErrorMessage(err)})
+	}
+
+	if existing == nil {
+		result.Status = model.ImportCreated
+	} else {
+		result.Status = model.ImportUpdated
+	}
+	return result, nil
+}
+
+// deleteMissingFromDataset xóa mềm các asset của dataset không có trong file
+func deleteMissingFromDataset(ctx context.Context, tx *sqlx.Tx, opts model.ImportOptions, actor string, seen map[string]int, report *model.ImportReport) error {
+	var names []string
+	query := "SELECT name FROM NetworkAssets WHERE datasetid = $1 AND markasdeleted = false ORDER BY name FOR UPDATE"
+	if err := tx.SelectContext(ctx, &names, query, opts.DatasetId); err != nil {
+		return fmt.Errorf("failed to query dataset %d: %w", opts.DatasetId, err)
+	}
+
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		if err := softDeleteNetworkAsset(ctx, tx, name, actor, opts.RequestId); err != nil {
+			return err
+		}
+		report.Add(model.ImportRowResult{Name: name, Status: model.ImportDeleted})
+	}
+	return nil
+}
+
+// uniqueAssetName sinh tên từ base chưa được dùng (kể cả bởi asset đã xóa mềm và các tên trong used),
+// thêm hậu tố -2, -3... nếu trùng
+func uniqueAssetName(ctx context.Context, tx *sqlx.Tx, base string, used map[string]bool) (string, error) {
+	const maxNameLen = 50
+	for n := 1; ; n++ {
+		suffix := ""
+		if n > 1 {
+			suffix = "-" + strconv.Itoa(n)
+		}
+		name := base
+		if len(name)+len(suffix) > maxNameLen {
+			name = name[:maxNameLen-len(suffix)]
+		}
+		name += suffix
+		if used[name] {
+			continue
+		}
+
+		var exists bool
+		if err := tx.GetContext(ctx, &exists, "SELECT EXISTS (SELECT 1 FROM NetworkAssets WHERE name = $1)", name); err != nil {
+			return "", fmt.Errorf("failed to check network asset name %s: %w", name, err)
+		}
+		if !exists {
+			return name, nil
+		}
+	}
+}
+
+func withSavepoint(ctx context.Context, tx *sqlx.Tx, fn func() error) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT import_row"); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	if err := fn(); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT import_row"); rbErr != nil {
+			return fmt.Errorf("failed to rollback savepoint: %w", rbErr)
+		}
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT import_row"); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+func importErrorMessage(err error) string {
+	var pqErr *pq.Error
+	if !stderrors.As(err, &pqErr) {
+		return err.Error()
+	}
+	if pqErr.Code.Name() == "unique_violation" {
+		return "duplicate value violates " + pqErr.Constraint
+	}
+	return pqErr.Message
+}
@@ -0,0 +1,107 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// ImportByInstanceId đồng bộ bản ghi từ CMDB nguồn theo InstanceId (duy nhất trên toàn bảng): asset có
+// cùng InstanceId được cập nhật và giữ tên cũ, bản ghi mới được đặt tên theo name của nguồn (hoặc
+// InstanceId). Dòng có mark_as_deleted=true xóa mềm asset tương ứng; asset đã xóa mềm mà nguồn còn
+// hoạt động được restore. Địa chỉ trùng được chấp nhận như các nguồn đồng bộ khác. InstanceId lặp lại
+// trong rows chỉ lấy dòng cuối.
+func (r *NetworkAssetRepoImpl) ImportByInstanceId(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions, actor string) (*model.ImportReport, error) {
+	tx, err := r.sql.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	opts.AllowDuplicateAddress = true
+
+	last := map[string]int{}
+	for i, row := range rows {
+		last[row.Values["instance_id"]] = i
+	}
+
+	var upserts []model.ImportRow
+	var deletes []model.ImportRowResult
+	used := map[string]bool{}
+	for i, row := range rows {
+		instanceId := row.Values["instance_id"]
+		if instanceId != "" && last[instanceId] != i {
+			continue
+		}
+		// Chép lại values để rows của caller không bị sửa (connector import lại trang khi có dòng lỗi)
+		values := make(map[string]string, len(row.Values))
+		for field, value := range row.Values {
+			values[field] = value
+		}
+		deleted := values["mark_as_deleted"] == "true"
+		delete(values, "mark_as_deleted")
+
+		name, markedDeleted, err := findInstanceAsset(ctx, tx, instanceId)
+		if err != nil {
+			return nil, err
+		}
+
+		if deleted {
+			if name != "" && !markedDeleted {
+				deletes = append(deletes, model.ImportRowResult{Row: row.Row, Name: name, Status: model.ImportDeleted})
+			}
+			continue
+		}
+
+		if markedDeleted {
+			if err := restoreNetworkAsset(ctx, tx, name, actor, opts.RequestId, true); err != nil {
+				return nil, err
+			}
+		}
+		if name == "" && instanceId != "" {
+			base := values["name"]
+			if base == "" {
+				base = instanceId
+			}
+			if name, err = uniqueAssetName(ctx, tx, base, used); err != nil {
+				return nil, err
+			}
+		}
+		values["name"] = name
+		used[name] = true
+		upserts = append(upserts, model.ImportRow{Row: row.Row, Values: values})
+	}
+
+	return importNetworkAssets(ctx, tx, upserts, opts, actor, func(_ map[string]int, report *model.ImportReport) error {
+		for _, result := range deletes {
+			if err := softDeleteNetworkAsset(ctx, tx, result.Name, actor, opts.RequestId); err != nil {
+				return err
+			}
+			report.Add(result)
+		}
+		return nil
+	})
+}
+
+// findInstanceAsset trả về tên asset có InstanceId (kể cả đã xóa mềm), rỗng nếu chưa có
+func findInstanceAsset(ctx context.Context, tx *sqlx.Tx, instanceId string) (string, bool, error) {
+	if instanceId == "" {
+		return "", false, nil
+	}
+
+	var asset struct {
+		Name          string `db:"name"`
+		MarkAsDeleted bool   `db:"markasdeleted"`
+	}
+	query := "SELECT name, markasdeleted FROM NetworkAssets WHERE instanceid = $1 FOR UPDATE"
+	if err := tx.GetContext(ctx, &asset, query, instanceId); err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to find network asset of instance %s: %w", instanceId, err)
+	}
+	return asset.Name, asset.MarkAsDeleted, nil
+}
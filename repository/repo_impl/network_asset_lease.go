@@ -0,0 +1,100 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// ImportLeases đồng bộ lease DHCP (dhcp.ImportRows) vào dataset opts.DatasetId. Mỗi địa chỉ là một
+// asset: địa chỉ đã có asset DHCP đang hoạt động trong dataset thì cập nhật asset đó (lease hết hạn thì
+// chuyển sang inactive), ngược lại tạo asset "dhcp-<địa chỉ>" nếu lease còn dùng. Với opts.DeactivateMissing, asset DHCP của dataset không còn trong danh sách
+// được chuyển sang inactive, không bị xóa. Địa chỉ trùng với asset khác được chấp nhận (hiện trong báo cáo ip-conflicts) để một
+// địa chỉ cấu hình tĩnh không làm hỏng cả lần đồng bộ.
+func (r *NetworkAssetRepoImpl) ImportLeases(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions, actor string) (*model.ImportReport, error) {
+	tx, err := r.sql.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	opts.AllowDuplicateAddress = true
+	used := map[string]bool{}
+	kept := rows[:0]
+	for _, row := range rows {
+		address := row.Values["address"]
+		name, err := findLeaseAsset(ctx, tx, opts.DatasetId, address)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			// Lease đã hết hạn/giải phóng của địa chỉ chưa có asset thì bỏ qua
+			if row.Values["lease_state"] == model.LeaseInactive {
+				continue
+			}
+			if name, err = uniqueAssetName(ctx, tx, "dhcp-"+address, used); err != nil {
+				return nil, err
+			}
+		}
+		row.Values["name"] = name
+		used[name] = true
+		kept = append(kept, row)
+	}
+	rows = kept
+
+	if !opts.DeactivateMissing {
+		return importNetworkAssets(ctx, tx, rows, opts, actor, nil)
+	}
+	return importNetworkAssets(ctx, tx, rows, opts, actor, func(seen map[string]int, report *model.ImportReport) error {
+		return deactivateMissingLeases(ctx, tx, opts, actor, seen, report)
+	})
+}
+
+func findLeaseAsset(ctx context.Context, tx *sqlx.Tx, datasetId int, address string) (string, error) {
+	query := `SELECT name FROM NetworkAssets
+		WHERE datasetid = $1 AND markasdeleted = false AND leasestate <> ''
+		  AND address = NULLIF($2, '')::inet
+		ORDER BY createdate
+		LIMIT 1`
+
+	var name string
+	if err := tx.GetContext(ctx, &name, query, datasetId, address); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to find lease asset of %s: %w", address, err)
+	}
+	return name, nil
+}
+
+// deactivateMissingLeases chuyển asset DHCP còn active/reserved của dataset nhưng không có trong lần
+// đồng bộ sang inactive, giữ nguyên thông tin lease cuối cùng
+func deactivateMissingLeases(ctx context.Context, tx *sqlx.Tx, opts model.ImportOptions, actor string, seen map[string]int, report *model.ImportReport) error {
+	var names []string
+	query := `SELECT name FROM NetworkAssets
+		WHERE datasetid = $1 AND markasdeleted = false AND leasestate IN ($2, $3)
+		ORDER BY name FOR UPDATE`
+	if err := tx.SelectContext(ctx, &names, query, opts.DatasetId, model.LeaseActive, model.LeaseReserved); err != nil {
+		return fmt.Errorf("failed to query leases of dataset %d: %w", opts.DatasetId, err)
+	}
+
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		asset, err := getNetworkAssetForUpdate(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+		asset.LeaseState = model.LeaseInactive
+		asset.RequestId = opts.RequestId
+		if err := updateNetworkAsset(ctx, tx, name, *asset, actor, true); err != nil {
+			return err
+		}
+		report.Add(model.ImportRowResult{Name: name, Status: model.ImportDeactivated})
+	}
+	return nil
+}
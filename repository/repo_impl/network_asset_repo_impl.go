@@ -5,11 +5,31 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/sllpklls/template-backend-go/db"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/ipaddr"
 	"github.com/sllpklls/template-backend-go/model"
 )
 
+// networkAssetColumns là danh sách cột tương ứng với các db tag của model.NetworkAsset.
+// Address là INET, đọc ra bằng host() để không kèm /32. Các cột thêm sau có COALESCE vì snapshot
+// lịch sử cũ (as_of) không có chúng.
+const networkAssetColumns = `name, systemname, COALESCE(host(address), '') AS address, shortdescription, subnetmask, protocoltype,
+		description, addresstype, dnshostname, createdate, datasetid, modifieddate,
+		lastmodifiedby, instanceid, requestid, reconciliationid, markasdeleted,
+		deletedby, deletedat, COALESCE(subnetid, 0) AS subnetid, COALESCE(vrfid, 0) AS vrfid,
+		COALESCE(vlanid, 0) AS vlanid, COALESCE(macaddress, '') AS macaddress, leasestart, leaseend,
+		COALESCE(leasestate, '') AS leasestate, lastseen`
+
+// networkAssetListColumns tương ứng với model.NetworkAssetList, dùng cùng scanNetworkAssetLists
+const networkAssetListColumns = `name, systemname, COALESCE(host(address), '') AS address, shortdescription, protocoltype,
+		       addresstype, dnshostname, createdate, markasdeleted, COALESCE(vrfid, 0) AS vrfid,
+		       COALESCE(vlanid, 0) AS vlanid`
+
 type NetworkAssetRepoImpl struct {
 	sql *db.Sql
 }
@@ -18,22 +38,15 @@ func NewNetworkAssetRepo(sql *db.Sql) *NetworkAssetRepoImpl {
 	return &NetworkAssetRepoImpl{sql: sql}
 }
 
-func (r *NetworkAssetRepoImpl) GetAllNetworkAssets(ctx context.Context, page, limit int) ([]model.NetworkAssetList, error) {
-	offset := (page - 1) * limit
-
-	query := `
-		SELECT name, systemname, address, shortdescription, protocoltype, 
-		       addresstype, dnshostname, createdate
-		FROM NetworkAssets 
-		ORDER BY createdate DESC 
-		LIMIT $1 OFFSET $2`
-
-	rows, err := r.sql.Db.QueryContext(ctx, query, limit, offset)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query network assets: %w", err)
+// activeCondition ẩn các asset đã MarkAsDeleted, trừ khi gọi với include_deleted=true
+func activeCondition(includeDeleted bool) string {
+	if includeDeleted {
+		return "TRUE"
 	}
-	defer rows.Close()
+	return "markasdeleted = false"
+}
 
+func scanNetworkAssetLists(rows *sql.Rows) ([]model.NetworkAssetList, error) {
 	var assets []model.NetworkAssetList
 	for rows.Next() {
 		var asset model.NetworkAssetList
@@ -46,6 +59,9 @@ func (r *NetworkAssetRepoImpl) GetAllNetworkAssets(ctx context.Context, page, li
 			&asset.AddressType,
 			&asset.DNSHostName,
 			&asset.CreateDate,
+			&asset.MarkAsDeleted,
+			&asset.VrfId,
+			&asset.VlanId,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan network asset: %w", err)
@@ -53,58 +69,100 @@ func (r *NetworkAssetRepoImpl) GetAllNetworkAssets(ctx context.Context, page, li
 		assets = append(assets, asset)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error during rows iteration: %w", err)
 	}
 
 	return assets, nil
 }
 
-func (r *NetworkAssetRepoImpl) GetNetworkAssetByName(ctx context.Context, name string) (*model.NetworkAsset, error) {
-	query := `
-		SELECT name, systemname, address, shortdescription, subnetmask, protocoltype, 
-		       description, addresstype, dnshostname, createdate, datasetid, 
-		       modifieddate, lastmodifiedby, instanceid, requestid
-		FROM NetworkAssets 
-		WHERE name = $1`
+func (r *NetworkAssetRepoImpl) GetAllNetworkAssets(ctx context.Context, page, limit int, includeDeleted bool) ([]model.NetworkAssetList, error) {
+	offset := (page - 1) * limit
 
-	var asset model.NetworkAsset
-	row := r.sql.Db.QueryRowContext(ctx, query, name)
-
-	err := row.Scan(
-		&asset.Name,
-		&asset.SystemName,
-		&asset.Address,
-		&asset.ShortDescription,
-		&asset.SubnetMask,
-		&asset.ProtocolType,
-		&asset.Description,
-		&asset.AddressType,
-		&asset.DNSHostName,
-		&asset.CreateDate,
-		&asset.DatasetId,
-		&asset.ModifiedDate,
-		&asset.LastModifiedBy,
-		&asset.InstanceId,
-		&asset.RequestId,
-	)
+	query := `
+		SELECT ` + networkAssetListColumns + `
+		FROM NetworkAssets
+		WHERE ` + activeCondition(includeDeleted) + `
+		ORDER BY createdate DESC
+		LIMIT $1 OFFSET $2`
 
+	rows, err := r.sql.Db.QueryContext(ctx, query, limit, offset)
 	if err != nil {
+		return nil, fmt.Errorf("failed to query network assets: %w", err)
+	}
+	defer rows.Close()
+
+	return scanNetworkAssetLists(rows)
+}
+
+func (r *NetworkAssetRepoImpl) GetNetworkAssetByName(ctx context.Context, name string, includeDeleted bool) (*model.NetworkAsset, error) {
+	query := `
+		SELECT ` + networkAssetColumns + `
+		FROM NetworkAssets
+		WHERE name = $1 AND ` + activeCondition(includeDeleted)
+
+	var asset model.NetworkAsset
+	if err := r.sql.Db.GetContext(ctx, &asset, query, name); err != nil {
 		return nil, fmt.Errorf("failed to get network asset: %w", err)
 	}
 
 	return &asset, nil
 }
 
-func (r *NetworkAssetRepoImpl) GetNetworkAssetsByDNSHostName(ctx context.Context, dnsHostName string, page, limit int) ([]model.NetworkAssetList, error) {
+// GetDualStackAddresses tìm các endpoint khác của cùng hệ thống (SystemName không phân biệt hoa thường,
+// cùng dataset) có địa chỉ khác họ: asset IPv4 thấy các địa chỉ IPv6 và ngược lại
+func (r *NetworkAssetRepoImpl) GetDualStackAddresses(ctx context.Context, asset *model.NetworkAsset, asOf time.Time) ([]model.NetworkAssetList, error) {
+	assets := []model.NetworkAssetList{}
+	if strings.TrimSpace(asset.SystemName) == "" {
+		return assets, nil
+	}
+
+	from := "NetworkAssets"
+	args := []interface{}{}
+	if !asOf.IsZero() {
+		from = networkAssetsAsOf
+		args = append(args, asOf)
+	}
+
+	family := 0
+	if addr, _, err := ipaddr.ParseAddress(asset.Address); err == nil {
+		family = 6
+		if addr.Is4() {
+			family = 4
+		}
+	}
+
+	n := len(args)
+	query := fmt.Sprintf(`
+		SELECT `+networkAssetListColumns+`
+		FROM `+from+`
+		WHERE markasdeleted = false AND address IS NOT NULL
+		  AND lower(systemname) = lower($%d) AND datasetid = $%d AND name <> $%d
+		  AND ($%d = 0 OR family(address) <> $%d)
+		ORDER BY address`, n+1, n+2, n+3, n+4, n+4)
+	args = append(args, asset.SystemName, asset.DatasetId, asset.Name, family)
+
+	rows, err := r.sql.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dual-stack addresses: %w", err)
+	}
+	defer rows.Close()
+
+	found, err := scanNetworkAssetLists(rows)
+	if err != nil {
+		return nil, err
+	}
+	return append(assets, found...), nil
+}
+
+func (r *NetworkAssetRepoImpl) GetNetworkAssetsByDNSHostName(ctx context.Context, dnsHostName string, page, limit int, includeDeleted bool) ([]model.NetworkAssetList, error) {
 	offset := (page - 1) * limit
 
 	query := `
-		SELECT name, systemname, address, shortdescription, protocoltype, 
-		       addresstype, dnshostname, createdate
-		FROM NetworkAssets 
-		WHERE dnshostname ILIKE $1
-		ORDER BY createdate DESC 
+		SELECT ` + networkAssetListColumns + `
+		FROM NetworkAssets
+		WHERE dnshostname ILIKE $1 AND ` + activeCondition(includeDeleted) + `
+		ORDER BY createdate DESC
 		LIMIT $2 OFFSET $3`
 
 	rows, err := r.sql.Db.QueryContext(ctx, query, "%"+dnsHostName+"%", limit, offset)
@@ -113,33 +171,10 @@ func (r *NetworkAssetRepoImpl) GetNetworkAssetsByDNSHostName(ctx context.Context
 	}
 	defer rows.Close()
 
-	var assets []model.NetworkAssetList
-	for rows.Next() {
-		var asset model.NetworkAssetList
-		err := rows.Scan(
-			&asset.Name,
-			&asset.SystemName,
-			&asset.Address,
-			&asset.ShortDescription,
-			&asset.ProtocolType,
-			&asset.AddressType,
-			&asset.DNSHostName,
-			&asset.CreateDate,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan network asset: %w", err)
-		}
-		assets = append(assets, asset)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error during rows iteration: %w", err)
-	}
-
-	return assets, nil
+	return scanNetworkAssetLists(rows)
 }
-func (r *NetworkAssetRepoImpl) GetTotalNetworkAssetsByDNSHostName(ctx context.Context, dnsHostName string) (int, error) {
-	query := "SELECT COUNT(*) FROM NetworkAssets WHERE dnshostname ILIKE $1"
+func (r *NetworkAssetRepoImpl) GetTotalNetworkAssetsByDNSHostName(ctx context.Context, dnsHostName string, includeDeleted bool) (int, error) {
+	query := "SELECT COUNT(*) FROM NetworkAssets WHERE dnshostname ILIKE $1 AND " + activeCondition(includeDeleted)
 
 	var total int
 	err := r.sql.Db.QueryRowContext(ctx, query, "%"+dnsHostName+"%").Scan(&total)
@@ -152,8 +187,8 @@ func (r *NetworkAssetRepoImpl) GetTotalNetworkAssetsByDNSHostName(ctx context.Co
 func (r *NetworkAssetRepoImpl) GetIPEndpointByDNSHostName(ctx context.Context, dnsHostName string) (bool, error) {
 	query := `
 		SELECT 1
-		FROM NetworkAssets 
-		WHERE dnshostname = $1
+		FROM NetworkAssets
+		WHERE dnshostname = $1 AND markasdeleted = false
 		LIMIT 1`
 
 	var exists int
@@ -168,9 +203,9 @@ func (r *NetworkAssetRepoImpl) GetIPEndpointByDNSHostName(ctx context.Context, d
 	return true, nil
 }
 
-func (r *NetworkAssetRepoImpl) GetTotalNetworkAssets(ctx context.Context) (int, error) {
+func (r *NetworkAssetRepoImpl) GetTotalNetworkAssets(ctx context.Context, includeDeleted bool) (int, error) {
 	var total int
-	query := "SELECT COUNT(*) FROM NetworkAssets"
+	query := "SELECT COUNT(*) FROM NetworkAssets WHERE " + activeCondition(includeDeleted)
 
 	err := r.sql.Db.QueryRowContext(ctx, query).Scan(&total)
 	if err != nil {
@@ -180,12 +215,19 @@ func (r *NetworkAssetRepoImpl) GetTotalNetworkAssets(ctx context.Context) (int,
 	return total, nil
 }
 
-func (r *NetworkAssetRepoImpl) GetTotalNetworkAssetsByFilter(ctx context.Context, filter model.NetworkAssetFilter) (int, error) {
-	var conditions []string
+// buildNetworkAssetFilter sinh bảng nguồn và điều kiện WHERE dùng chung cho search và count,
+// với as_of bảng nguồn là NetworkAssets dựng lại từ lịch sử
+func buildNetworkAssetFilter(filter model.NetworkAssetFilter) (string, string, []interface{}) {
+	conditions := []string{activeCondition(filter.IncludeDeleted)}
 	var args []interface{}
 	argIndex := 1
 
-	baseQuery := "SELECT COUNT(*) FROM NetworkAssets"
+	from := "NetworkAssets"
+	if !filter.AsOf.IsZero() {
+		from = networkAssetsAsOf
+		args = append(args, filter.AsOf)
+		argIndex++
+	}
 
 	if filter.Name != "" {
 		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", argIndex))
@@ -194,11 +236,31 @@ func (r *NetworkAssetRepoImpl) GetTotalNetworkAssetsByFilter(ctx context.Context
 	}
 
 	if filter.Address != "" {
-		conditions = append(conditions, fmt.Sprintf("address ILIKE $%d", argIndex))
+		conditions = append(conditions, fmt.Sprintf("host(address) ILIKE $%d", argIndex))
 		args = append(args, "%"+filter.Address+"%")
 		argIndex++
 	}
 
+	// ip, within và range đã được chuẩn hóa bởi NetworkAssetFilter.Validate
+	if filter.IP != "" {
+		conditions = append(conditions, fmt.Sprintf("address = $%d::inet", argIndex))
+		args = append(args, filter.IP)
+		argIndex++
+	}
+
+	if filter.Within != "" {
+		conditions = append(conditions, fmt.Sprintf("address <<= $%d::cidr", argIndex))
+		args = append(args, filter.Within)
+		argIndex++
+	}
+
+	if start, end, ok := strings.Cut(filter.Range, "-"); ok {
+		// inet so sánh họ địa chỉ trước nên dải IPv4 không lấy địa chỉ IPv6 và ngược lại
+		conditions = append(conditions, fmt.Sprintf("address BETWEEN $%d::inet AND $%d::inet", argIndex, argIndex+1))
+		args = append(args, start, end)
+		argIndex += 2
+	}
+
 	if filter.ProtocolType != "" {
 		conditions = append(conditions, fmt.Sprintf("protocoltype = $%d", argIndex))
 		args = append(args, filter.ProtocolType)
@@ -210,16 +272,57 @@ func (r *NetworkAssetRepoImpl) GetTotalNetworkAssetsByFilter(ctx context.Context
 		args = append(args, filter.AddressType)
 		argIndex++
 	}
-
+	if filter.DnsHostname != "" {
+		conditions = append(conditions, fmt.Sprintf("dnshostname ILIKE $%d", argIndex))
+		args = append(args, "%"+filter.DnsHostname+"%")
+		argIndex++
+	}
 	if filter.DatasetId > 0 {
 		conditions = append(conditions, fmt.Sprintf("datasetid = $%d", argIndex))
 		args = append(args, filter.DatasetId)
 		argIndex++
 	}
+	if filter.Vrf != "" {
+		conditions = append(conditions, vrfCondition("vrfid", argIndex))
+		args = append(args, filter.Vrf)
+		argIndex++
+	}
+	if filter.VlanId > 0 {
+		conditions = append(conditions, fmt.Sprintf("vlanid = $%d", argIndex))
+		args = append(args, filter.VlanId)
+		argIndex++
+	}
+	if filter.MacAddress != "" {
+		conditions = append(conditions, fmt.Sprintf("macaddress = $%d", argIndex))
+		args = append(args, filter.MacAddress)
+		argIndex++
+	}
+	if filter.LeaseState != "" {
+		conditions = append(conditions, fmt.Sprintf("leasestate = $%d", argIndex))
+		args = append(args, filter.LeaseState)
+		argIndex++
+	}
+
+	return from, " WHERE " + strings.Join(conditions, " AND "), args
+}
 
-	if len(conditions) > 0 {
-		baseQuery += " WHERE " + strings.Join(conditions, " AND ")
+// networkAssetOrder trả về ORDER BY theo filter.Sort, address sắp xếp theo giá trị số của địa chỉ
+func networkAssetOrder(sort string) string {
+	switch sort {
+	case model.SortAddress:
+		return " ORDER BY address ASC NULLS LAST, name"
+	case model.SortAddressDesc:
+		return " ORDER BY address DESC NULLS LAST, name"
+	case model.SortCreateDate:
+		return " ORDER BY createdate ASC"
+	default:
+		return " ORDER BY createdate DESC"
 	}
+}
+
+func (r *NetworkAssetRepoImpl) GetTotalNetworkAssetsByFilter(ctx context.Context, filter model.NetworkAssetFilter) (int, error) {
+	from, where, args := buildNetworkAssetFilter(filter)
+	baseQuery := "SELECT COUNT(*) FROM " + from + where
 
 	var total int
 	err := r.sql.Db.QueryRowContext(ctx, baseQuery, args...).Scan(&total)
@@ -229,55 +332,47 @@ func (r *NetworkAssetRepoImpl) GetTotalNetworkAssetsByFilter(ctx context.Context
 	// log.Info(total)
 	return total, nil
 }
-func (r *NetworkAssetRepoImpl) GetNetworkAssetsByFilter(ctx context.Context, filter model.NetworkAssetFilter) ([]model.NetworkAssetList, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
 
-	baseQuery := `
-		SELECT name, systemname, address, shortdescription, protocoltype, 
-		       addresstype, dnshostname, createdate
-		FROM NetworkAssets`
+// StreamNetworkAssetsByFilter đọc mọi asset khớp filter (bỏ qua page/limit) và gọi fn cho từng dòng
+// ngay khi đọc được từ cursor, dùng cho export dữ liệu lớn
+func (r *NetworkAssetRepoImpl) StreamNetworkAssetsByFilter(ctx context.Context, filter model.NetworkAssetFilter, fn func(asset *model.NetworkAsset) error) error {
+	from, where, args := buildNetworkAssetFilter(filter)
+	query := `
+		SELECT ` + networkAssetColumns + `
+		FROM ` + from + where + networkAssetOrder(filter.Sort)
 
-	if filter.Name != "" {
-		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", argIndex))
-		args = append(args, "%"+filter.Name+"%")
-		argIndex++
+	rows, err := r.sql.Db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query network assets for export: %w", err)
 	}
+	defer rows.Close()
 
-	if filter.Address != "" {
-		conditions = append(conditions, fmt.Sprintf("address ILIKE $%d", argIndex))
-		args = append(args, "%"+filter.Address+"%")
-		argIndex++
+	for rows.Next() {
+		var asset model.NetworkAsset
+		if err := rows.StructScan(&asset); err != nil {
+			return fmt.Errorf("failed to scan network asset: %w", err)
+		}
+		if err := fn(&asset); err != nil {
+			return err
+		}
 	}
 
-	if filter.ProtocolType != "" {
-		conditions = append(conditions, fmt.Sprintf("protocoltype = $%d", argIndex))
-		args = append(args, filter.ProtocolType)
-		argIndex++
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error during rows iteration: %w", err)
 	}
+	return nil
+}
 
-	if filter.AddressType != "" {
-		conditions = append(conditions, fmt.Sprintf("addresstype = $%d", argIndex))
-		args = append(args, filter.AddressType)
-		argIndex++
-	}
-	if filter.DnsHostname != "" {
-		conditions = append(conditions, fmt.Sprintf("dnshostname ILIKE $%d", argIndex))
-		args = append(args, "%"+filter.DnsHostname+"%")
-		argIndex++
-	}
-	if filter.DatasetId > 0 {
-		conditions = append(conditions, fmt.Sprintf("datasetid = $%d", argIndex))
-		args = append(args, filter.DatasetId)
-		argIndex++
-	}
+func (r *NetworkAssetRepoImpl) GetNetworkAssetsByFilter(ctx context.Context, filter model.NetworkAssetFilter) ([]model.NetworkAssetList, error) {
+	from, where, args := buildNetworkAssetFilter(filter)
 
-	if len(conditions) > 0 {
-		baseQuery += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	baseQuery := `
+		SELECT ` + networkAssetListColumns + `
+		FROM ` + from + where
 
-	baseQuery += " ORDER BY createdate DESC"
+	baseQuery += networkAssetOrder(filter.Sort)
+	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
 
 	rows, err := r.sql.Db.QueryContext(ctx, baseQuery, args...)
 	if err != nil {
@@ -285,37 +380,56 @@ func (r *NetworkAssetRepoImpl) GetNetworkAssetsByFilter(ctx context.Context, fil
 	}
 	defer rows.Close()
 
-	var assets []model.NetworkAssetList
-	for rows.Next() {
-		var asset model.NetworkAssetList
-		err := rows.Scan(
-			&asset.Name,
-			&asset.SystemName,
-			&asset.Address,
-			&asset.ShortDescription,
-			&asset.ProtocolType,
-			&asset.AddressType,
-			&asset.DNSHostName,
-			&asset.CreateDate,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan network asset: %w", err)
-		}
-		assets = append(assets, asset)
+	return scanNetworkAssetLists(rows)
+}
+
+// withTx chạy fn trong một transaction, rollback nếu fn trả về lỗi
+func withTx(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	return assets, nil
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *NetworkAssetRepoImpl) CreateNetworkAsset(ctx context.Context, asset model.NetworkAsset) error {
+func (r *NetworkAssetRepoImpl) CreateNetworkAsset(ctx context.Context, asset model.NetworkAsset, actor string, allowDuplicateAddress bool) error {
+	return withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		return insertNetworkAsset(ctx, tx, asset, actor, allowDuplicateAddress)
+	})
+}
+
+// insertNetworkAsset dùng chung cho CreateNetworkAsset và các thao tác ghi trong transaction,
+// ghi luôn lịch sử nên ex phải là transaction
+func insertNetworkAsset(ctx context.Context, ex sqlx.ExtContext, asset model.NetworkAsset, actor string, allowDuplicateAddress bool) error {
+	if err := resolveVrf(ctx, ex, &asset.VrfId, asset.VlanId); err != nil {
+		return err
+	}
+	if err := checkNameConflict(ctx, ex, asset); err != nil {
+		return err
+	}
+	if !allowDuplicateAddress {
+		if err := checkAddressConflict(ctx, ex, asset); err != nil {
+			return err
+		}
+	}
+
 	query := `
 		INSERT INTO NetworkAssets (
 			name, systemname, address, shortdescription, subnetmask, protocoltype,
 			description, addresstype, dnshostname, datasetid, lastmodifiedby,
-			instanceid, requestid
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
-
-	_, err := r.sql.Db.ExecContext(ctx, query,
+			instanceid, requestid, reconciliationid, vrfid, vlanid, subnetid,
+			macaddress, leasestart, leaseend, leasestate
+		) VALUES ($1, $2, NULLIF($3, '')::inet, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+			NULLIF($15, 0), NULLIF($16, 0),
+			(` + containingSubnet("NULLIF($3, '')::inet", "NULLIF($15, 0)") + `),
+			$17, $18, $19, $20)`
+
+	_, err := ex.ExecContext(ctx, query,
 		asset.Name,
 		asset.SystemName,
 		asset.Address,
@@ -329,25 +443,65 @@ func (r *NetworkAssetRepoImpl) CreateNetworkAsset(ctx context.Context, asset mod
 		asset.LastModifiedBy,
 		asset.InstanceId,
 		asset.RequestId,
+		asset.ReconciliationId,
+		asset.VrfId,
+		asset.VlanId,
+		asset.MacAddress,
+		asset.LeaseStart,
+		asset.LeaseEnd,
+		asset.LeaseState,
 	)
 
 	if err != nil {
+		// Request khác ghi cùng tên giữa lúc kiểm tra và lúc insert
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return fmt.Errorf("%w: %s", errors.NetworkAssetConflict, asset.Name)
+		}
 		return fmt.Errorf("failed to create network asset: %w", err)
 	}
 
-	return nil
+	return recordNetworkAssetChange(ctx, ex, model.HistoryCreate, asset.Name, nil, actor, asset.RequestId)
 }
 
-func (r *NetworkAssetRepoImpl) UpdateNetworkAsset(ctx context.Context, name string, asset model.NetworkAsset) error {
+func (r *NetworkAssetRepoImpl) UpdateNetworkAsset(ctx context.Context, name string, asset model.NetworkAsset, actor string, allowDuplicateAddress bool) error {
+	return withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		return updateNetworkAsset(ctx, tx, name, asset, actor, allowDuplicateAddress)
+	})
+}
+
+func updateNetworkAsset(ctx context.Context, ex sqlx.ExtContext, name string, asset model.NetworkAsset, actor string, allowDuplicateAddress bool) error {
+	before, err := getNetworkAssetForUpdate(ctx, ex, name)
+	if err != nil {
+		return err
+	}
+	if before == nil || before.MarkAsDeleted {
+		return fmt.Errorf("network asset not found")
+	}
+
+	if err := resolveVrf(ctx, ex, &asset.VrfId, asset.VlanId); err != nil {
+		return err
+	}
+
+	// Chỉ kiểm tra khi địa chỉ, dataset hoặc VRF đổi, để asset đang trùng từ trước vẫn sửa được các field khác
+	addressMoved := before.Address != asset.Address || before.DatasetId != asset.DatasetId || before.VrfId != asset.VrfId
+	if addressMoved && !allowDuplicateAddress {
+		asset.Name = name
+		if err := checkAddressConflict(ctx, ex, asset); err != nil {
+			return err
+		}
+	}
+
 	query := `
 		UPDATE NetworkAssets SET
-			systemname = $1, address = $2, shortdescription = $3, subnetmask = $4,
+			systemname = $1, address = NULLIF($2, '')::inet, shortdescription = $3, subnetmask = $4,
 			protocoltype = $5, description = $6, addresstype = $7, dnshostname = $8,
 			datasetid = $9, modifieddate = NOW(), lastmodifiedby = $10,
-			instanceid = $11, requestid = $12
+			instanceid = $11, requestid = $12, vrfid = NULLIF($14, 0), vlanid = NULLIF($15, 0),
+			subnetid = (` + containingSubnet("NULLIF($2, '')::inet", "NULLIF($14, 0)") + `),
+			macaddress = $16, leasestart = $17, leaseend = $18, leasestate = $19
 		WHERE name = $13`
 
-	result, err := r.sql.Db.ExecContext(ctx, query,
+	_, err = ex.ExecContext(ctx, query,
 		asset.SystemName,
 		asset.Address,
 		asset.ShortDescription,
@@ -361,40 +515,116 @@ func (r *NetworkAssetRepoImpl) UpdateNetworkAsset(ctx context.Context, name stri
 		asset.InstanceId,
 		asset.RequestId,
 		name,
+		asset.VrfId,
+		asset.VlanId,
+		asset.MacAddress,
+		asset.LeaseStart,
+		asset.LeaseEnd,
+		asset.LeaseState,
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to update network asset: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	return recordNetworkAssetChange(ctx, ex, model.HistoryUpdate, name, before, actor, asset.RequestId)
+}
+
+// DeleteNetworkAsset chỉ đánh dấu MarkAsDeleted, bản ghi và relationship vẫn được giữ
+// để có thể restore; xóa hẳn bằng PurgeNetworkAssets
+func (r *NetworkAssetRepoImpl) DeleteNetworkAsset(ctx context.Context, name, deletedBy, requestId string) error {
+	return withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		return softDeleteNetworkAsset(ctx, tx, name, deletedBy, requestId)
+	})
+}
+
+func softDeleteNetworkAsset(ctx context.Context, ex sqlx.ExtContext, name, deletedBy, requestId string) error {
+	before, err := getNetworkAssetForUpdate(ctx, ex, name)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return err
 	}
-
-	if rowsAffected == 0 {
+	if before == nil || before.MarkAsDeleted {
 		return fmt.Errorf("network asset not found")
 	}
 
-	return nil
+	query := `
+		UPDATE NetworkAssets SET
+			markasdeleted = true, deletedby = $1, deletedat = NOW()
+		WHERE name = $2`
+
+	if _, err := ex.ExecContext(ctx, query, deletedBy, name); err != nil {
+		return fmt.Errorf("failed to delete network asset: %w", err)
+	}
+
+	return recordNetworkAssetChange(ctx, ex, model.HistoryDelete, name, before, deletedBy, requestId)
 }
 
-func (r *NetworkAssetRepoImpl) DeleteNetworkAsset(ctx context.Context, name string) error {
-	query := "DELETE FROM NetworkAssets WHERE name = $1"
+func (r *NetworkAssetRepoImpl) RestoreNetworkAsset(ctx context.Context, name, actor, requestId string, allowDuplicateAddress bool) error {
+	return withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		return restoreNetworkAsset(ctx, tx, name, actor, requestId, allowDuplicateAddress)
+	})
+}
 
-	result, err := r.sql.Db.ExecContext(ctx, query, name)
+func restoreNetworkAsset(ctx context.Context, ex sqlx.ExtContext, name, actor, requestId string, allowDuplicateAddress bool) error {
+	before, err := getNetworkAssetForUpdate(ctx, ex, name)
 	if err != nil {
-		return fmt.Errorf("failed to delete network asset: %w", err)
+		return err
+	}
+	if before == nil || !before.MarkAsDeleted {
+		return fmt.Errorf("network asset not found")
+	}
+	if !allowDuplicateAddress {
+		if err := checkAddressConflict(ctx, ex, *before); err != nil {
+			return err
+		}
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	query := `
+		UPDATE NetworkAssets SET
+			markasdeleted = false, deletedby = '', deletedat = NULL
+		WHERE name = $1`
+
+	if _, err := ex.ExecContext(ctx, query, name); err != nil {
+		return fmt.Errorf("failed to restore network asset: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("network asset not found")
+	return recordNetworkAssetChange(ctx, ex, model.HistoryRestore, name, before, actor, requestId)
+}
+
+// PurgeNetworkAssets xóa hẳn các asset đã MarkAsDeleted trước deletedBefore cùng relationship của chúng,
+// lịch sử vẫn được giữ và có thêm một dòng Purge
+func (r *NetworkAssetRepoImpl) PurgeNetworkAssets(ctx context.Context, deletedBefore time.Time, actor string) ([]string, error) {
+	names := []string{}
+	err := withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		query := `
+			SELECT name FROM NetworkAssets
+			WHERE markasdeleted = true AND deletedat < $1
+			FOR UPDATE`
+
+		if err := tx.SelectContext(ctx, &names, query, deletedBefore); err != nil {
+			return fmt.Errorf("failed to query purgeable network assets: %w", err)
+		}
+
+		for _, name := range names {
+			before, err := getNetworkAssetForUpdate(ctx, tx, name)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, "DELETE FROM NetworkAssets WHERE name = $1", name); err != nil {
+				return fmt.Errorf("failed to purge network asset %s: %w", name, err)
+			}
+			if err := deleteRelationshipsOf(ctx, tx, model.NetworkAssetClass, name); err != nil {
+				return err
+			}
+			if err := recordNetworkAssetChange(ctx, tx, model.HistoryPurge, name, before, actor, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return names, nil
 }
@@ -0,0 +1,155 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sllpklls/template-backend-go/ipaddr"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// ImportScan ghi kết quả scan vào dataset opts.DatasetId. Host có địa chỉ trùng asset đang hoạt động
+// của dataset được gán vào asset đó (cập nhật hostname, MAC nếu scan có), host mới được đặt tên theo
+// hostname hoặc địa chỉ; host.Values (field do mapping sinh ra) ghi đè các giá trị khác. Port mở và
+// LastSeen được ghi cho mọi host, kể cả host không đổi.
+func (r *NetworkAssetRepoImpl) ImportScan(ctx context.Context, hosts []model.DiscoveredHost, opts model.ImportOptions, actor string) (*model.ImportReport, error) {
+	tx, err := r.sql.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows := make([]model.ImportRow, 0, len(hosts))
+	names := make([]string, len(hosts))
+	used := map[string]bool{}
+	for i, host := range hosts {
+		values := map[string]string{"address": host.Address}
+		if addr, err := netip.ParseAddr(host.Address); err == nil {
+			values["address_type"] = ipaddr.Family(addr)
+		}
+		if host.Hostname != "" {
+			values["dns_host_name"] = host.Hostname
+		}
+		if host.MAC != "" {
+			values["mac_address"] = host.MAC
+		}
+
+		name, err := findScanAsset(ctx, tx, opts.DatasetId, host.Address)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			base := host.Hostname
+			if base == "" {
+				base = host.Address
+			}
+			if name, err = uniqueAssetName(ctx, tx, base, used); err != nil {
+				return nil, err
+			}
+			values["system_name"] = host.Hostname
+			values["protocol_type"] = scanProtocols(host.Ports)
+		}
+		// Field do mapping sinh ra ghi đè giá trị từ scan, trừ tên đã được gán theo địa chỉ
+		for k, v := range host.Values {
+			values[k] = v
+		}
+		values["name"] = name
+		used[name] = true
+		names[i] = name
+		rows = append(rows, model.ImportRow{Row: host.Row, Values: values})
+	}
+
+	return importNetworkAssets(ctx, tx, rows, opts, actor, func(map[string]int, *model.ImportReport) error {
+		for i, host := range hosts {
+			if err := recordScanHost(ctx, tx, names[i], host); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func findScanAsset(ctx context.Context, tx *sqlx.Tx, datasetId int, address string) (string, error) {
+	query := `SELECT name FROM NetworkAssets
+		WHERE datasetid = $1 AND markasdeleted = false AND address = NULLIF($2, '')::inet
+		ORDER BY createdate
+		LIMIT 1`
+
+	var name string
+	if err := tx.GetContext(ctx, &name, query, datasetId, address); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to find network asset of %s: %w", address, err)
+	}
+	return name, nil
+}
+
+// scanProtocols trả về các protocol có port mở, ví dụ "TCP,UDP"
+func scanProtocols(ports []model.NetworkAssetPort) string {
+	seen := map[string]bool{}
+	var protocols []string
+	for _, p := range ports {
+		protocol := strings.ToUpper(p.Protocol)
+		if !seen[protocol] {
+			seen[protocol] = true
+			protocols = append(protocols, protocol)
+		}
+	}
+	sort.Strings(protocols)
+	return strings.Join(protocols, ",")
+}
+
+// recordScanHost ghi port và LastSeen, không ghi đè dữ liệu mới hơn khi import lại scan cũ. Port của
+// asset không có trong scan và được thấy lần cuối trước scan này chuyển sang closed, lastseen giữ nguyên
+// là lần cuối port còn mở.
+func recordScanHost(ctx context.Context, tx *sqlx.Tx, name string, host model.DiscoveredHost) error {
+	portQuery := `
+		INSERT INTO NetworkAssetPorts (assetname, protocol, port, state, service, product, version, firstseen, lastseen)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (assetname, protocol, port) DO UPDATE SET
+			state = EXCLUDED.state,
+			service = EXCLUDED.service,
+			product = EXCLUDED.product,
+			version = EXCLUDED.version,
+			firstseen = LEAST(NetworkAssetPorts.firstseen, EXCLUDED.firstseen),
+			lastseen = EXCLUDED.lastseen
+		WHERE NetworkAssetPorts.lastseen <= EXCLUDED.lastseen`
+
+	for _, p := range host.Ports {
+		if _, err := tx.ExecContext(ctx, portQuery, name, p.Protocol, p.Port, p.State, p.Service, p.Product, p.Version, host.SeenAt); err != nil {
+			return fmt.Errorf("failed to save port %s/%d of %s: %w", p.Protocol, p.Port, name, err)
+		}
+	}
+
+	closeQuery := `
+		UPDATE NetworkAssetPorts SET state = 'closed'
+		WHERE assetname = $1 AND lastseen < $2 AND state <> 'closed'`
+	if _, err := tx.ExecContext(ctx, closeQuery, name, host.SeenAt); err != nil {
+		return fmt.Errorf("failed to close ports of %s: %w", name, err)
+	}
+
+	query := "UPDATE NetworkAssets SET lastseen = $2 WHERE name = $1 AND (lastseen IS NULL OR lastseen < $2)"
+	if _, err := tx.ExecContext(ctx, query, name, host.SeenAt); err != nil {
+		return fmt.Errorf("failed to update last seen of %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *NetworkAssetRepoImpl) GetNetworkAssetPorts(ctx context.Context, name string) ([]model.NetworkAssetPort, error) {
+	query := `SELECT assetname, protocol, port, state, service, product, version, firstseen, lastseen
+		FROM NetworkAssetPorts
+		WHERE assetname = $1
+		ORDER BY protocol, port`
+
+	ports := []model.NetworkAssetPort{}
+	if err := r.sql.Db.SelectContext(ctx, &ports, query, name); err != nil {
+		return nil, fmt.Errorf("failed to query network asset ports: %w", err)
+	}
+	return ports, nil
+}
@@ -0,0 +1,88 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+// ImportZone import các dòng sinh từ zone file (importer.ParseZone). Dòng trùng hostname và địa chỉ
+// với asset đang hoạt động của dataset đích được gán vào asset đó (giữ nguyên SystemName),
+// dòng mới được đặt tên theo hostname, thêm hậu tố -2, -3... nếu tên đã có.
+func (r *NetworkAssetRepoImpl) ImportZone(ctx context.Context, rows []model.ImportRow, opts model.ImportOptions, actor string) (*model.ImportReport, error) {
+	tx, err := r.sql.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	used := map[string]bool{}
+	for i := range rows {
+		values := rows[i].Values
+		name, err := findZoneAsset(ctx, tx, opts.DatasetId, values["dns_host_name"], values["address"])
+		if err != nil {
+			return nil, err
+		}
+		if name != "" {
+			delete(values, "dns_host_name")
+			delete(values, "system_name")
+		} else if name, err = uniqueAssetName(ctx, tx, values["dns_host_name"], used); err != nil {
+			return nil, err
+		}
+		values["name"] = name
+		used[name] = true
+	}
+
+	return importNetworkAssets(ctx, tx, rows, opts, actor, nil)
+}
+
+func findZoneAsset(ctx context.Context, tx *sqlx.Tx, datasetId int, host, address string) (string, error) {
+	query := `SELECT name FROM NetworkAssets
+		WHERE datasetid = $1 AND markasdeleted = false
+		  AND address = NULLIF($2, '')::inet AND rtrim(lower(dnshostname), '.') = $3
+		ORDER BY createdate
+		LIMIT 1`
+
+	var name string
+	if err := tx.GetContext(ctx, &name, query, datasetId, address, strings.ToLower(host)); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to find network asset of %s %s: %w", host, address, err)
+	}
+	return name, nil
+}
+
+// GetAssetsForZone trả về các asset đang hoạt động có cả hostname và địa chỉ, thuộc dải Cidr
+// (reverse zone) hoặc có hostname nằm dưới Domain (forward zone)
+func (r *NetworkAssetRepoImpl) GetAssetsForZone(ctx context.Context, filter model.ZoneFilter) ([]model.NetworkAsset, error) {
+	query := `SELECT ` + networkAssetColumns + `
+		FROM NetworkAssets
+		WHERE markasdeleted = false AND dnshostname <> '' AND address IS NOT NULL
+		  AND ($1 = 0 OR datasetid = $1)`
+	args := []interface{}{filter.DatasetId}
+
+	if filter.Cidr != "" {
+		args = append(args, filter.Cidr)
+		query += fmt.Sprintf(" AND address <<= $%d::cidr", len(args))
+	} else if filter.Domain != "" {
+		args = append(args, filter.Domain, "%."+escapeLike(filter.Domain))
+		query += fmt.Sprintf(" AND (rtrim(lower(dnshostname), '.') = $%d OR rtrim(lower(dnshostname), '.') LIKE $%d)", len(args)-1, len(args))
+	}
+	query += " ORDER BY address, name"
+
+	assets := []model.NetworkAsset{}
+	if err := r.sql.Db.SelectContext(ctx, &assets, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to query network assets for zone: %w", err)
+	}
+	return assets, nil
+}
+
+// escapeLike escape các ký tự đặc biệt của LIKE (domain có thể chứa "_")
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,272 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sllpklls/template-backend-go/db"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type ReconciliationRepoImpl struct {
+	sql *db.Sql
+}
+
+func NewReconciliationRepo(sql *db.Sql) *ReconciliationRepoImpl {
+	return &ReconciliationRepoImpl{sql: sql}
+}
+
+func (r *ReconciliationRepoImpl) CreateJob(ctx context.Context, job model.ReconciliationJob) (model.ReconciliationJob, error) {
+	query := `
+		INSERT INTO ReconciliationJobs (
+			name, sourcedatasets, targetdatasetid, identificationrules, precedencerules
+		) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, createdate`
+
+	if job.PrecedenceRules == nil {
+		job.PrecedenceRules = model.PrecedenceRules{}
+	}
+
+	err := r.sql.Db.QueryRowContext(ctx, query,
+		job.Name,
+		job.SourceDatasets,
+		job.TargetDatasetId,
+		job.IdentificationRules,
+		job.PrecedenceRules,
+	).Scan(&job.Id, &job.CreateDate)
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return job, errors.ReconciliationJobConflict
+		}
+		return job, fmt.Errorf("failed to create reconciliation job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (r *ReconciliationRepoImpl) GetJobs(ctx context.Context) ([]model.ReconciliationJob, error) {
+	query := `
+		SELECT id, name, sourcedatasets, targetdatasetid, identificationrules,
+		       precedencerules, createdate, modifieddate
+		FROM ReconciliationJobs
+		ORDER BY id`
+
+	jobs := []model.ReconciliationJob{}
+	if err := r.sql.Db.SelectContext(ctx, &jobs, query); err != nil {
+		return nil, fmt.Errorf("failed to query reconciliation jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *ReconciliationRepoImpl) GetJobById(ctx context.Context, id int64) (*model.ReconciliationJob, error) {
+	query := `
+		SELECT id, name, sourcedatasets, targetdatasetid, identificationrules,
+		       precedencerules, createdate, modifieddate
+		FROM ReconciliationJobs
+		WHERE id = $1`
+
+	var job model.ReconciliationJob
+	if err := r.sql.Db.GetContext(ctx, &job, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ReconciliationJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get reconciliation job: %w", err)
+	}
+	return &job, nil
+}
+
+func (r *ReconciliationRepoImpl) UpdateJob(ctx context.Context, id int64, job model.ReconciliationJob) error {
+	query := `
+		UPDATE ReconciliationJobs SET
+			name = $1, sourcedatasets = $2, targetdatasetid = $3,
+			identificationrules = $4, precedencerules = $5, modifieddate = NOW()
+		WHERE id = $6`
+
+	if job.PrecedenceRules == nil {
+		job.PrecedenceRules = model.PrecedenceRules{}
+	}
+
+	result, err := r.sql.Db.ExecContext(ctx, query,
+		job.Name,
+		job.SourceDatasets,
+		job.TargetDatasetId,
+		job.IdentificationRules,
+		job.PrecedenceRules,
+		id,
+	)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return errors.ReconciliationJobConflict
+		}
+		return fmt.Errorf("failed to update reconciliation job: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.ReconciliationJobNotFound
+	}
+	return nil
+}
+
+func (r *ReconciliationRepoImpl) DeleteJob(ctx context.Context, id int64) error {
+	result, err := r.sql.Db.ExecContext(ctx, "DELETE FROM ReconciliationJobs WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete reconciliation job: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.ReconciliationJobNotFound
+	}
+	return nil
+}
+
+func (r *ReconciliationRepoImpl) CreateRun(ctx context.Context, run model.ReconciliationRun) (model.ReconciliationRun, error) {
+	query := `
+		INSERT INTO ReconciliationRuns (jobid, status, triggeredby)
+		VALUES ($1, $2, $3)
+		RETURNING id, startedat`
+
+	err := r.sql.Db.QueryRowContext(ctx, query, run.JobId, run.Status, run.TriggeredBy).
+		Scan(&run.Id, &run.StartedAt)
+	if err != nil {
+		return run, fmt.Errorf("failed to create reconciliation run: %w", err)
+	}
+	return run, nil
+}
+
+func (r *ReconciliationRepoImpl) FinishRun(ctx context.Context, run model.ReconciliationRun) error {
+	query := `
+		UPDATE ReconciliationRuns SET
+			status = $1, finishedat = NOW(), total = $2, identified = $3, merged = $4,
+			unmatched = $5, created = $6, updated = $7, errormessage = $8
+		WHERE id = $9`
+
+	_, err := r.sql.Db.ExecContext(ctx, query,
+		run.Status,
+		run.Total,
+		run.Identified,
+		run.Merged,
+		run.Unmatched,
+		run.Created,
+		run.Updated,
+		run.ErrorMessage,
+		run.Id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish reconciliation run: %w", err)
+	}
+	return nil
+}
+
+func (r *ReconciliationRepoImpl) GetRunsByJob(ctx context.Context, jobId int64) ([]model.ReconciliationRun, error) {
+	query := `
+		SELECT id, jobid, status, startedat, finishedat, total, identified, merged,
+		       unmatched, created, updated, errormessage, triggeredby
+		FROM ReconciliationRuns
+		WHERE jobid = $1
+		ORDER BY startedat DESC
+		LIMIT 100`
+
+	runs := []model.ReconciliationRun{}
+	if err := r.sql.Db.SelectContext(ctx, &runs, query, jobId); err != nil {
+		return nil, fmt.Errorf("failed to query reconciliation runs: %w", err)
+	}
+	return runs, nil
+}
+
+func (r *ReconciliationRepoImpl) GetRunById(ctx context.Context, id int64) (*model.ReconciliationRun, error) {
+	query := `
+		SELECT id, jobid, status, startedat, finishedat, total, identified, merged,
+		       unmatched, created, updated, errormessage, triggeredby
+		FROM ReconciliationRuns
+		WHERE id = $1`
+
+	var run model.ReconciliationRun
+	if err := r.sql.Db.GetContext(ctx, &run, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ReconciliationRunNotFound
+		}
+		return nil, fmt.Errorf("failed to get reconciliation run: %w", err)
+	}
+	return &run, nil
+}
+
+func (r *ReconciliationRepoImpl) GetNetworkAssetsByDatasets(ctx context.Context, datasetIds []int) ([]model.NetworkAsset, error) {
+	query := `SELECT ` + networkAssetColumns + `
+		FROM NetworkAssets
+		WHERE datasetid = ANY($1) AND markasdeleted = false
+		ORDER BY createdate`
+
+	assets := []model.NetworkAsset{}
+	if err := r.sql.Db.SelectContext(ctx, &assets, query, pq.Array(datasetIds)); err != nil {
+		return nil, fmt.Errorf("failed to query network assets by datasets: %w", err)
+	}
+	return assets, nil
+}
+
+// SaveReconciliation ghi plan trong một transaction, actor là người chạy job và được ghi vào lịch sử.
+// Bản ghi golden được phép trùng địa chỉ: xung đột trong dataset đích hiện trong báo cáo
+// ip-conflicts thay vì làm hỏng cả lần chạy.
+func (r *ReconciliationRepoImpl) SaveReconciliation(ctx context.Context, plan model.ReconciliationPlan, actor string) error {
+	return withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		// Name của bản ghi golden mới chỉ là tên gợi ý: có thể quá dài hoặc đã có asset dùng
+		used := map[string]bool{}
+		for _, asset := range plan.Create {
+			name, err := uniqueAssetName(ctx, tx, asset.Name, used)
+			if err != nil {
+				return err
+			}
+			used[name] = true
+			asset.Name = name
+			if err := insertNetworkAsset(ctx, tx, asset, actor, true); err != nil {
+				return err
+			}
+		}
+
+		for _, asset := range plan.Update {
+			if err := updateNetworkAsset(ctx, tx, asset.Name, asset, actor, true); err != nil {
+				return err
+			}
+			if err := setReconciliationId(ctx, tx, asset.Name, asset.ReconciliationId, actor); err != nil {
+				return err
+			}
+		}
+
+		for name, reconciliationId := range plan.Identities {
+			if err := setReconciliationId(ctx, tx, name, reconciliationId, actor); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func setReconciliationId(ctx context.Context, tx *sqlx.Tx, name, reconciliationId, actor string) error {
+	before, err := getNetworkAssetForUpdate(ctx, tx, name)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return fmt.Errorf("network asset not found")
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE NetworkAssets SET reconciliationid = $1 WHERE name = $2",
+		reconciliationId, name); err != nil {
+		return fmt.Errorf("failed to set reconciliation id: %w", err)
+	}
+
+	return recordNetworkAssetChange(ctx, tx, model.HistoryUpdate, name, before, actor, before.RequestId)
+}
@@ -0,0 +1,241 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sllpklls/template-backend-go/db"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type RelationshipRepoImpl struct {
+	sql      *db.Sql
+	registry *model.CIRegistry
+}
+
+func NewRelationshipRepo(sql *db.Sql, registry *model.CIRegistry) *RelationshipRepoImpl {
+	return &RelationshipRepoImpl{sql: sql, registry: registry}
+}
+
+// ciTable trả về bảng lưu CI của class (NetworkAsset hoặc class trong registry)
+func (r *RelationshipRepoImpl) ciTable(class string) (string, error) {
+	if class == model.NetworkAssetClass {
+		return "NetworkAssets", nil
+	}
+	if c, ok := r.registry.Get(class); ok {
+		return c.Table, nil
+	}
+	return "", errors.UnknownCIClass
+}
+
+func (r *RelationshipRepoImpl) CIExists(ctx context.Context, class, name string) (bool, error) {
+	table, err := r.ciTable(class)
+	if err != nil {
+		return false, err
+	}
+
+	var exists int
+	query := fmt.Sprintf("SELECT 1 FROM %s WHERE name = $1", table)
+	if class == model.NetworkAssetClass {
+		query += " AND markasdeleted = false"
+	}
+	err = r.sql.Db.QueryRowContext(ctx, query, name).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check %s %s: %w", class, name, err)
+	}
+	return true, nil
+}
+
+func (r *RelationshipRepoImpl) CreateRelationship(ctx context.Context, rel model.Relationship) (model.Relationship, error) {
+	query := `
+		INSERT INTO CIRelationships (
+			type, sourceclass, sourcename, targetclass, targetname, description, createdby
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, createdate`
+
+	err := r.sql.Db.QueryRowContext(ctx, query,
+		rel.Type,
+		rel.SourceClass,
+		rel.SourceName,
+		rel.TargetClass,
+		rel.TargetName,
+		rel.Description,
+		rel.CreatedBy,
+	).Scan(&rel.Id, &rel.CreateDate)
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return rel, errors.RelationshipConflict
+		}
+		return rel, fmt.Errorf("failed to create relationship: %w", err)
+	}
+
+	return rel, nil
+}
+
+func (r *RelationshipRepoImpl) GetRelationshipsByCI(ctx context.Context, class, name string) ([]model.Relationship, error) {
+	query := `
+		SELECT id, type, sourceclass, sourcename, targetclass, targetname,
+		       description, createdate, createdby
+		FROM CIRelationships
+		WHERE (sourceclass = $1 AND sourcename = $2)
+		   OR (targetclass = $1 AND targetname = $2)
+		ORDER BY createdate DESC`
+
+	relationships := []model.Relationship{}
+	if err := r.sql.Db.SelectContext(ctx, &relationships, query, class, name); err != nil {
+		return nil, fmt.Errorf("failed to query relationships: %w", err)
+	}
+
+	return relationships, nil
+}
+
+func (r *RelationshipRepoImpl) DeleteRelationship(ctx context.Context, class, name string, id int64) error {
+	query := `
+		DELETE FROM CIRelationships
+		WHERE id = $1
+		  AND ((sourceclass = $2 AND sourcename = $3) OR (targetclass = $2 AND targetname = $3))`
+
+	result, err := r.sql.Db.ExecContext(ctx, query, id, class, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete relationship: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.RelationshipNotFound
+	}
+
+	return nil
+}
+
+// deleteRelationshipsOf xóa mọi relationship có CI là source hoặc target,
+// được gọi trong transaction xóa CI
+func deleteRelationshipsOf(ctx context.Context, tx sqlx.ExecerContext, class, name string) error {
+	query := `
+		DELETE FROM CIRelationships
+		WHERE (sourceclass = $1 AND sourcename = $2)
+		   OR (targetclass = $1 AND targetname = $2)`
+
+	if _, err := tx.ExecContext(ctx, query, class, name); err != nil {
+		return fmt.Errorf("failed to delete relationships of %s %s: %w", class, name, err)
+	}
+	return nil
+}
+
+// renameRelationshipsOf cập nhật relationship khi CI đổi tên
+func renameRelationshipsOf(ctx context.Context, tx sqlx.ExecerContext, class, oldName, newName string) error {
+	queries := []string{
+		"UPDATE CIRelationships SET sourcename = $3 WHERE sourceclass = $1 AND sourcename = $2",
+		"UPDATE CIRelationships SET targetname = $3 WHERE targetclass = $1 AND targetname = $2",
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, class, oldName, newName); err != nil {
+			return fmt.Errorf("failed to rename relationships of %s %s: %w", class, oldName, err)
+		}
+	}
+	return nil
+}
+
+// GetImpact duyệt đồ thị relationship bằng recursive CTE. Quan hệ source --type--> target
+// nghĩa là source phụ thuộc target, nên downstream đi từ target về source; ConnectedTo
+// được coi là hai chiều nhưng không đi ngược lại đúng relationship vừa đi qua (A→B→A không phải
+// vòng lặp). Path dùng để phát hiện vòng lặp, maxPaths giới hạn số dòng đọc
+// từ CTE (Postgres dừng sinh thêm dòng khi đủ LIMIT) để đồ thị dày không treo request.
+func (r *RelationshipRepoImpl) GetImpact(ctx context.Context, class, name, direction string, depth, maxPaths int) (*model.ImpactResult, error) {
+	fromCols, toCols := "targetclass, targetname", "sourceclass, sourcename"
+	if direction == model.ImpactUpstream {
+		fromCols, toCols = toCols, fromCols
+	}
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE edges (id, fromclass, fromname, toclass, toname, type) AS (
+			SELECT id, %[1]s, %[2]s, type FROM CIRelationships
+			UNION ALL
+			SELECT id, %[2]s, %[1]s, type FROM CIRelationships WHERE type = 'ConnectedTo'
+		),
+		impact (class, name, distance, type, path, cycle, edge) AS (
+			SELECT $1::text, $2::text, 0, ''::text, ARRAY[$1 || ':' || $2], false, 0::bigint
+			UNION ALL
+			SELECT e.toclass::text, e.toname::text, i.distance + 1, e.type::text,
+			       i.path || (e.toclass || ':' || e.toname)::text,
+			       (e.toclass || ':' || e.toname) = ANY(i.path), e.id
+			FROM impact i
+			JOIN edges e ON e.fromclass = i.class AND e.fromname = i.name AND e.id <> i.edge
+			WHERE i.distance < $3 AND NOT i.cycle
+			  AND NOT EXISTS (
+			      SELECT 1 FROM NetworkAssets d
+			      WHERE e.toclass = 'NetworkAsset' AND d.name = e.toname AND d.markasdeleted
+			  )
+		),
+		limited AS (
+			SELECT * FROM impact WHERE distance > 0 LIMIT $4
+		)
+		SELECT l.class, l.name, COALESCE(host(n.address), ''), l.distance, l.type, l.path, l.cycle
+		FROM limited l
+		LEFT JOIN NetworkAssets n ON l.class = 'NetworkAsset' AND n.name = l.name`, fromCols, toCols)
+
+	rows, err := r.sql.Db.QueryContext(ctx, query, class, name, depth, maxPaths+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query impact: %w", err)
+	}
+	defer rows.Close()
+
+	result := &model.ImpactResult{
+		Class:     class,
+		Name:      name,
+		Direction: direction,
+		Depth:     depth,
+		Items:     []model.ImpactedCI{},
+	}
+
+	// Mỗi CI chỉ giữ đường đi ngắn nhất
+	seen := map[string]int{}
+	count := 0
+	for rows.Next() {
+		count++
+		if count > maxPaths {
+			result.Truncated = true
+			break
+		}
+
+		var item model.ImpactedCI
+		var cycle bool
+		if err := rows.Scan(&item.Class, &item.Name, &item.Address, &item.Distance,
+			&item.RelationshipType, pq.Array(&item.Path), &cycle); err != nil {
+			return nil, fmt.Errorf("failed to scan impact: %w", err)
+		}
+
+		if cycle {
+			result.Cycles = append(result.Cycles, item.Path)
+			continue
+		}
+		key := item.Class + ":" + item.Name
+		if i, ok := seen[key]; ok {
+			if result.Items[i].Distance <= item.Distance {
+				continue
+			}
+			result.Items[i] = item
+			continue
+		}
+		seen[key] = len(result.Items)
+		result.Items = append(result.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,504 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sllpklls/template-backend-go/db"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/ipaddr"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type SubnetRepoImpl struct {
+	sql *db.Sql
+}
+
+func NewSubnetRepo(sql *db.Sql) *SubnetRepoImpl {
+	return &SubnetRepoImpl{sql: sql}
+}
+
+// subnetHostCondition: địa chỉ asset a nằm trong dải host của subnet s (ipaddr.HostRange), tức là
+// không tính network và broadcast của subnet IPv4 từ /30 trở xuống
+const subnetHostCondition = `a.address <<= s.cidr
+	        AND (family(s.cidr) = 6 OR masklen(s.cidr) > 30
+	             OR a.address NOT IN (host(network(s.cidr))::inet, host(broadcast(s.cidr))::inet))`
+
+// subnetQuery đọc subnet kèm các số liệu để tính utilization, chỉ tính asset cùng VRF nằm trong dải host
+const subnetQuery = `
+	SELECT s.id, s.cidr::text AS cidr, s.name, s.description, COALESCE(host(s.gateway), '') AS gateway,
+	       s.parentid, s.datasetid, COALESCE(s.vrfid, 0) AS vrfid, COALESCE(s.vlanid, 0) AS vlanid,
+	       s.owner, s.createdate, s.modifieddate, s.lastmodifiedby,
+	       (SELECT COUNT(DISTINCT a.address) FROM NetworkAssets a
+	        WHERE ` + subnetHostCondition + `
+	          AND a.vrfid IS NOT DISTINCT FROM s.vrfid AND a.markasdeleted = false) AS used,
+	       (SELECT COUNT(DISTINCT a.address) FROM SubnetReservations r
+	        JOIN NetworkAssets a ON a.address BETWEEN r.startaddress AND r.endaddress
+	             AND ` + subnetHostCondition + `
+	             AND a.vrfid IS NOT DISTINCT FROM s.vrfid AND a.markasdeleted = false
+	        WHERE r.subnetid = s.id) AS usedreserved,
+	       EXISTS (SELECT 1 FROM NetworkAssets a
+	               WHERE a.address = s.gateway AND a.vrfid IS NOT DISTINCT FROM s.vrfid
+	                 AND a.markasdeleted = false) AS gatewayused,
+	       EXISTS (SELECT 1 FROM SubnetReservations r
+	               WHERE r.subnetid = s.id AND s.gateway BETWEEN r.startaddress AND r.endaddress) AS gatewayreserved
+	FROM Subnets s`
+
+const reservationColumns = `id, subnetid, host(startaddress) AS startaddress, host(endaddress) AS endaddress,
+	description, createdby, createdate`
+
+type subnetRow struct {
+	model.Subnet
+	Used            int64 `db:"used"`
+	UsedReserved    int64 `db:"usedreserved"`
+	GatewayUsed     bool  `db:"gatewayused"`
+	GatewayReserved bool  `db:"gatewayreserved"`
+}
+
+func (r *SubnetRepoImpl) GetSubnets(ctx context.Context, filter model.SubnetFilter) ([]model.Subnet, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Vrf != "" {
+		args = append(args, filter.Vrf)
+		conditions = append(conditions, vrfCondition("s.vrfid", len(args)))
+	}
+	if filter.VlanId > 0 {
+		args = append(args, filter.VlanId)
+		conditions = append(conditions, fmt.Sprintf("s.vlanid = $%d", len(args)))
+	}
+	query := subnetQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var rows []subnetRow
+	if err := r.sql.Db.SelectContext(ctx, &rows, query+" ORDER BY COALESCE(s.vrfid, 0), s.cidr", args...); err != nil {
+		return nil, fmt.Errorf("failed to query subnets: %w", err)
+	}
+
+	var reservations []model.SubnetReservation
+	query = "SELECT " + reservationColumns + " FROM SubnetReservations ORDER BY subnetid, startaddress"
+	if err := r.sql.Db.SelectContext(ctx, &reservations, query); err != nil {
+		return nil, fmt.Errorf("failed to query subnet reservations: %w", err)
+	}
+	bySubnet := map[int64][]model.SubnetReservation{}
+	for _, res := range reservations {
+		bySubnet[res.SubnetId] = append(bySubnet[res.SubnetId], res)
+	}
+
+	subnets := make([]model.Subnet, 0, len(rows))
+	for _, row := range rows {
+		subnets = append(subnets, row.toSubnet(bySubnet[row.Id]))
+	}
+	return subnets, nil
+}
+
+func (r *SubnetRepoImpl) GetSubnet(ctx context.Context, ref, vrf string) (*model.Subnet, error) {
+	where, args, err := subnetRefCondition(ref, vrf)
+	if err != nil {
+		return nil, err
+	}
+
+	var row subnetRow
+	if err := r.sql.Db.GetContext(ctx, &row, subnetQuery+" WHERE "+where, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.SubnetNotFound
+		}
+		return nil, fmt.Errorf("failed to get subnet: %w", err)
+	}
+
+	var reservations []model.SubnetReservation
+	query := "SELECT " + reservationColumns + " FROM SubnetReservations WHERE subnetid = $1 ORDER BY startaddress"
+	if err := r.sql.Db.SelectContext(ctx, &reservations, query, row.Id); err != nil {
+		return nil, fmt.Errorf("failed to query subnet reservations: %w", err)
+	}
+
+	subnet := row.toSubnet(reservations)
+	return &subnet, nil
+}
+
+// subnetRefCondition: ref là id hoặc CIDR, CIDR được tìm trong VRF có tên vrf (rỗng là global)
+func subnetRefCondition(ref, vrf string) (string, []interface{}, error) {
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return "s.id = $1", []interface{}{id}, nil
+	}
+	prefix, err := ipaddr.ParseCIDR(ref)
+	if err != nil {
+		return "", nil, errors.SubnetNotFound
+	}
+	if vrf == "" {
+		vrf = model.GlobalVrf
+	}
+	return "s.cidr = $1::cidr AND " + vrfCondition("s.vrfid", 2), []interface{}{prefix.String(), vrf}, nil
+}
+
+func (row subnetRow) toSubnet(reservations []model.SubnetReservation) model.Subnet {
+	subnet := row.Subnet
+	subnet.Reservations = reservations
+
+	prefix, err := netip.ParsePrefix(subnet.Cidr)
+	if err != nil {
+		return subnet
+	}
+
+	total := ipaddr.UsableSize(prefix)
+	used := big.NewInt(row.Used)
+
+	// Reserved chỉ tính địa chỉ chưa có asset và nằm trong dải host, để used + reserved + free = total
+	hosts := ipaddr.HostRange(prefix)
+	reserved := new(big.Int)
+	for _, res := range reservations {
+		start, err1 := netip.ParseAddr(res.StartAddress)
+		end, err2 := netip.ParseAddr(res.EndAddress)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if start.Less(hosts.Start) {
+			start = hosts.Start
+		}
+		if hosts.End.Less(end) {
+			end = hosts.End
+		}
+		if !end.Less(start) {
+			reserved.Add(reserved, ipaddr.RangeSize(start, end))
+		}
+	}
+	reserved.Sub(reserved, big.NewInt(row.UsedReserved))
+	if subnet.Gateway != "" && !row.GatewayUsed && !row.GatewayReserved {
+		reserved.Add(reserved, big.NewInt(1))
+	}
+
+	free := new(big.Int).Sub(total, used)
+	free.Sub(free, reserved)
+	if free.Sign() < 0 {
+		free.SetInt64(0)
+	}
+
+	percent := 0.0
+	if total.Sign() > 0 {
+		full := new(big.Float).SetInt(new(big.Int).Sub(total, free))
+		ratio, _ := full.Quo(full, new(big.Float).SetInt(total)).Float64()
+		percent = float64(int(ratio*10000+0.5)) / 100
+	}
+
+	subnet.Utilization = &model.SubnetUtilization{
+		Total:       total,
+		Used:        used,
+		Reserved:    reserved,
+		Free:        free,
+		PercentFull: percent,
+	}
+	return subnet
+}
+
+func (r *SubnetRepoImpl) CreateSubnet(ctx context.Context, subnet model.Subnet) (model.Subnet, error) {
+	err := withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		if err := resolveVrf(ctx, tx, &subnet.VrfId, subnet.VlanId); err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO Subnets (cidr, name, description, gateway, datasetid, owner, lastmodifiedby, vrfid, vlanid)
+			VALUES ($1::cidr, $2, $3, NULLIF($4, '')::inet, $5, $6, $7, NULLIF($8, 0), NULLIF($9, 0))
+			RETURNING id, createdate`
+
+		err := tx.QueryRowxContext(ctx, query,
+			subnet.Cidr,
+			subnet.Name,
+			subnet.Description,
+			subnet.Gateway,
+			subnet.DatasetId,
+			subnet.Owner,
+			subnet.LastModifiedBy,
+			subnet.VrfId,
+			subnet.VlanId,
+		).Scan(&subnet.Id, &subnet.CreateDate)
+		if err != nil {
+			if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+				return errors.SubnetConflict
+			}
+			return fmt.Errorf("failed to create subnet: %w", err)
+		}
+
+		return relinkSubnet(ctx, tx, subnet.Cidr, subnet.VrfId)
+	})
+	return subnet, err
+}
+
+func (r *SubnetRepoImpl) UpdateSubnet(ctx context.Context, id int64, subnet model.Subnet) error {
+	return withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		var oldCidr string
+		var oldVrf int
+		query := "SELECT cidr::text, COALESCE(vrfid, 0) FROM Subnets WHERE id = $1 FOR UPDATE"
+		if err := tx.QueryRowxContext(ctx, query, id).Scan(&oldCidr, &oldVrf); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.SubnetNotFound
+			}
+			return fmt.Errorf("failed to get subnet: %w", err)
+		}
+		if err := resolveVrf(ctx, tx, &subnet.VrfId, subnet.VlanId); err != nil {
+			return err
+		}
+
+		query = `
+			UPDATE Subnets SET
+				cidr = $1::cidr, name = $2, description = $3, gateway = NULLIF($4, '')::inet,
+				datasetid = $5, owner = $6, lastmodifiedby = $7, modifieddate = NOW(),
+				vrfid = NULLIF($9, 0), vlanid = NULLIF($10, 0)
+			WHERE id = $8`
+
+		_, err := tx.ExecContext(ctx, query,
+			subnet.Cidr,
+			subnet.Name,
+			subnet.Description,
+			subnet.Gateway,
+			subnet.DatasetId,
+			subnet.Owner,
+			subnet.LastModifiedBy,
+			id,
+			subnet.VrfId,
+			subnet.VlanId,
+		)
+		if err != nil {
+			if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+				return errors.SubnetConflict
+			}
+			return fmt.Errorf("failed to update subnet: %w", err)
+		}
+
+		if oldCidr != subnet.Cidr || oldVrf != subnet.VrfId {
+			if err := relinkSubnet(ctx, tx, oldCidr, oldVrf); err != nil {
+				return err
+			}
+		}
+		return relinkSubnet(ctx, tx, subnet.Cidr, subnet.VrfId)
+	})
+}
+
+func (r *SubnetRepoImpl) DeleteSubnet(ctx context.Context, id int64) error {
+	return withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		var cidr string
+		var vrfId int
+		query := "DELETE FROM Subnets WHERE id = $1 RETURNING cidr::text, COALESCE(vrfid, 0)"
+		if err := tx.QueryRowxContext(ctx, query, id).Scan(&cidr, &vrfId); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.SubnetNotFound
+			}
+			return fmt.Errorf("failed to delete subnet: %w", err)
+		}
+
+		// Subnet con và asset chuyển sang subnet cha
+		return relinkSubnet(ctx, tx, cidr, vrfId)
+	})
+}
+
+// relinkSubnet tính lại subnet cha của các subnet và subnet của các asset nằm trong cidr của VRF,
+// gọi sau mỗi thao tác thêm/sửa/xóa subnet
+func relinkSubnet(ctx context.Context, ex sqlx.ExecerContext, cidr string, vrfId int) error {
+	query := `
+		UPDATE Subnets s SET parentid = (
+			SELECT p.id FROM Subnets p
+			WHERE p.cidr >> s.cidr AND p.vrfid IS NOT DISTINCT FROM s.vrfid
+			ORDER BY masklen(p.cidr) DESC LIMIT 1
+		)
+		WHERE s.cidr <<= $1::cidr AND COALESCE(s.vrfid, 0) = $2`
+	if _, err := ex.ExecContext(ctx, query, cidr, vrfId); err != nil {
+		return fmt.Errorf("failed to relink subnets of %s: %w", cidr, err)
+	}
+
+	query = `
+		UPDATE NetworkAssets a SET subnetid = (` + containingSubnet("a.address", "a.vrfid") + `)
+		WHERE a.address <<= $1::cidr AND COALESCE(a.vrfid, 0) = $2`
+	if _, err := ex.ExecContext(ctx, query, cidr, vrfId); err != nil {
+		return fmt.Errorf("failed to relink network assets of %s: %w", cidr, err)
+	}
+	return nil
+}
+
+// containingSubnet là subquery trả về id subnet nhỏ nhất cùng VRF chứa địa chỉ,
+// vrf là biểu thức SQL cho vrfid (NULL là global)
+func containingSubnet(address, vrf string) string {
+	return `SELECT s.id FROM Subnets s WHERE s.cidr >>= ` + address + ` AND s.vrfid IS NOT DISTINCT FROM ` + vrf +
+		` ORDER BY masklen(s.cidr) DESC LIMIT 1`
+}
+
+func (r *SubnetRepoImpl) CreateReservation(ctx context.Context, reservation model.SubnetReservation) (model.SubnetReservation, error) {
+	err := withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		// Khóa subnet để kiểm tra chồng lấn và cấp phát địa chỉ không chạy song song
+		var exists int
+		if err := tx.QueryRowxContext(ctx, "SELECT 1 FROM Subnets WHERE id = $1 FOR UPDATE", reservation.SubnetId).Scan(&exists); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.SubnetNotFound
+			}
+			return fmt.Errorf("failed to lock subnet: %w", err)
+		}
+
+		var overlaps bool
+		query := `
+			SELECT EXISTS (
+				SELECT 1 FROM SubnetReservations
+				WHERE subnetid = $1 AND startaddress <= $3::inet AND endaddress >= $2::inet
+			)`
+		if err := tx.QueryRowxContext(ctx, query, reservation.SubnetId, reservation.StartAddress, reservation.EndAddress).Scan(&overlaps); err != nil {
+			return fmt.Errorf("failed to check reservation overlap: %w", err)
+		}
+		if overlaps {
+			return errors.ReservationConflict
+		}
+
+		query = `
+			INSERT INTO SubnetReservations (subnetid, startaddress, endaddress, description, createdby)
+			VALUES ($1, $2::inet, $3::inet, $4, $5)
+			RETURNING id, createdate`
+		err := tx.QueryRowxContext(ctx, query,
+			reservation.SubnetId,
+			reservation.StartAddress,
+			reservation.EndAddress,
+			reservation.Description,
+			reservation.CreatedBy,
+		).Scan(&reservation.Id, &reservation.CreateDate)
+		if err != nil {
+			return fmt.Errorf("failed to create reservation: %w", err)
+		}
+		return nil
+	})
+	return reservation, err
+}
+
+func (r *SubnetRepoImpl) DeleteReservation(ctx context.Context, subnetId, id int64) error {
+	result, err := r.sql.Db.ExecContext(ctx, "DELETE FROM SubnetReservations WHERE id = $1 AND subnetid = $2", id, subnetId)
+	if err != nil {
+		return fmt.Errorf("failed to delete reservation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.ReservationNotFound
+	}
+	return nil
+}
+
+// AllocateAddresses cấp count địa chỉ liên tiếp trong subnet và tạo NetworkAsset cho từng địa chỉ
+// trong VRF của subnet. Mọi subnet chồng lấn cùng VRF (cha và con) bị khóa FOR UPDATE theo thứ tự id,
+// nên hai request đồng thời không bao giờ nhận cùng một địa chỉ.
+func (r *SubnetRepoImpl) AllocateAddresses(ctx context.Context, subnetId int64, start string, count int, template model.NetworkAsset, actor string) ([]model.NetworkAsset, error) {
+	var assets []model.NetworkAsset
+	err := withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		var cidr string
+		var vrfId, vlanId int
+		query := "SELECT cidr::text, COALESCE(vrfid, 0), COALESCE(vlanid, 0) FROM Subnets WHERE id = $1"
+		if err := tx.QueryRowxContext(ctx, query, subnetId).Scan(&cidr, &vrfId, &vlanId); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.SubnetNotFound
+			}
+			return fmt.Errorf("failed to get subnet: %w", err)
+		}
+		query = "SELECT id FROM Subnets WHERE cidr && $1::cidr AND COALESCE(vrfid, 0) = $2 ORDER BY id FOR UPDATE"
+		if _, err := tx.ExecContext(ctx, query, cidr, vrfId); err != nil {
+			return fmt.Errorf("failed to lock subnet: %w", err)
+		}
+
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid subnet cidr %s: %w", cidr, err)
+		}
+		taken, err := takenRanges(ctx, tx, cidr, vrfId)
+		if err != nil {
+			return err
+		}
+
+		var first netip.Addr
+		if start != "" {
+			first, err = netip.ParseAddr(start)
+			if err != nil || !ipaddr.IsFree(prefix, taken, first, count) {
+				return errors.AddressUnavailable
+			}
+		} else {
+			var ok bool
+			if first, ok = ipaddr.FindFree(prefix, taken, count); !ok {
+				return errors.SubnetFull
+			}
+		}
+
+		addr := first
+		for i := 0; i < count; i++ {
+			asset := template
+			if count > 1 {
+				asset.Name = fmt.Sprintf("%s-%d", template.Name, i+1)
+			}
+			asset.Address = addr.String()
+			asset.SubnetMask = strconv.Itoa(prefix.Bits())
+			asset.VrfId = vrfId
+			if asset.VlanId == 0 {
+				asset.VlanId = vlanId
+			}
+			asset.AddressType = ""
+			if fieldErrs := asset.Validate(); len(fieldErrs) > 0 {
+				return fmt.Errorf("invalid allocated asset %s: %s %s", asset.Name, fieldErrs[0].Field, fieldErrs[0].Message)
+			}
+
+			if err := insertNetworkAsset(ctx, tx, asset, actor, false); err != nil {
+				if stderrors.Is(err, errors.NetworkAssetConflict) {
+					return errors.NetworkAssetConflict
+				}
+				if stderrors.Is(err, errors.AddressConflict) {
+					return errors.AddressUnavailable
+				}
+				return err
+			}
+			asset.SubnetId = int(subnetId)
+			assets = append(assets, asset)
+			addr = addr.Next()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return assets, nil
+}
+
+// takenRanges là các địa chỉ không được cấp trong cidr của VRF: asset chưa xóa, reservation và gateway
+// của mọi subnet chồng lấn
+func takenRanges(ctx context.Context, q sqlx.QueryerContext, cidr string, vrfId int) ([]ipaddr.Range, error) {
+	query := `
+		SELECT host(address) AS startaddress, host(address) AS endaddress
+		FROM NetworkAssets
+		WHERE address <<= $1::cidr AND COALESCE(vrfid, 0) = $2 AND markasdeleted = false
+		UNION ALL
+		SELECT host(r.startaddress), host(r.endaddress)
+		FROM SubnetReservations r JOIN Subnets s ON s.id = r.subnetid
+		WHERE s.cidr && $1::cidr AND COALESCE(s.vrfid, 0) = $2
+		UNION ALL
+		SELECT host(gateway), host(gateway)
+		FROM Subnets WHERE cidr && $1::cidr AND COALESCE(vrfid, 0) = $2 AND gateway IS NOT NULL`
+
+	var rows []struct {
+		StartAddress string `db:"startaddress"`
+		EndAddress   string `db:"endaddress"`
+	}
+	if err := sqlx.SelectContext(ctx, q, &rows, query, cidr, vrfId); err != nil {
+		return nil, fmt.Errorf("failed to query used addresses: %w", err)
+	}
+
+	ranges := make([]ipaddr.Range, 0, len(rows))
+	for _, row := range rows {
+		start, err1 := netip.ParseAddr(row.StartAddress)
+		end, err2 := netip.ParseAddr(row.EndAddress)
+		if err1 == nil && err2 == nil {
+			ranges = append(ranges, ipaddr.Range{Start: start, End: end})
+		}
+	}
+	return ranges, nil
+}
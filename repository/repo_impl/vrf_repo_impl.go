@@ -0,0 +1,261 @@
+package repo_impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sllpklls/template-backend-go/db"
+	"github.com/sllpklls/template-backend-go/errors"
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type VrfRepoImpl struct {
+	sql *db.Sql
+}
+
+func NewVrfRepo(sql *db.Sql) *VrfRepoImpl {
+	return &VrfRepoImpl{sql: sql}
+}
+
+const vrfColumns = `id, name, routedistinguisher, description, createdate, modifieddate, lastmodifiedby`
+
+const vlanColumns = `vlanid, name, description, COALESCE(vrfid, 0) AS vrfid, createdate, modifieddate, lastmodifiedby`
+
+// vrfCondition so khớp cột vrfid với VRF có tên là tham số $arg, model.GlobalVrf là bảng định tuyến global
+func vrfCondition(column string, arg int) string {
+	return fmt.Sprintf("(CASE WHEN $%[2]d = '%[3]s' THEN %[1]s IS NULL ELSE %[1]s = (SELECT id FROM Vrfs WHERE name = $%[2]d) END)",
+		column, arg, model.GlobalVrf)
+}
+
+// resolveVrf kiểm tra VRF và VLAN của một bản ghi trước khi ghi: VLAN phải tồn tại, bản ghi không
+// chọn VRF thì lấy VRF của VLAN, chọn VRF khác VRF của VLAN là lỗi
+func resolveVrf(ctx context.Context, q sqlx.QueryerContext, vrfId *int, vlanId int) error {
+	if vlanId != 0 {
+		var vlanVrf int
+		err := q.QueryRowxContext(ctx, "SELECT COALESCE(vrfid, 0) FROM Vlans WHERE vlanid = $1", vlanId).Scan(&vlanVrf)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.VlanNotFound
+			}
+			return fmt.Errorf("failed to get vlan %d: %w", vlanId, err)
+		}
+		if *vrfId == 0 {
+			*vrfId = vlanVrf
+		} else if vlanVrf != 0 && vlanVrf != *vrfId {
+			return errors.VlanVrfMismatch
+		}
+	}
+
+	if *vrfId != 0 {
+		var exists int
+		if err := q.QueryRowxContext(ctx, "SELECT 1 FROM Vrfs WHERE id = $1", *vrfId).Scan(&exists); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.VrfNotFound
+			}
+			return fmt.Errorf("failed to get vrf %d: %w", *vrfId, err)
+		}
+	}
+	return nil
+}
+
+func (r *VrfRepoImpl) GetVrfs(ctx context.Context) ([]model.Vrf, error) {
+	vrfs := []model.Vrf{}
+	if err := r.sql.Db.SelectContext(ctx, &vrfs, "SELECT "+vrfColumns+" FROM Vrfs ORDER BY name"); err != nil {
+		return nil, fmt.Errorf("failed to query vrfs: %w", err)
+	}
+	return vrfs, nil
+}
+
+func (r *VrfRepoImpl) GetVrf(ctx context.Context, id int64) (*model.Vrf, error) {
+	var vrf model.Vrf
+	if err := r.sql.Db.GetContext(ctx, &vrf, "SELECT "+vrfColumns+" FROM Vrfs WHERE id = $1", id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.VrfNotFound
+		}
+		return nil, fmt.Errorf("failed to get vrf: %w", err)
+	}
+	return &vrf, nil
+}
+
+func (r *VrfRepoImpl) CreateVrf(ctx context.Context, vrf model.Vrf) (model.Vrf, error) {
+	query := `
+		INSERT INTO Vrfs (name, routedistinguisher, description, lastmodifiedby)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, createdate`
+
+	err := r.sql.Db.QueryRowxContext(ctx, query,
+		vrf.Name,
+		vrf.RouteDistinguisher,
+		vrf.Description,
+		vrf.LastModifiedBy,
+	).Scan(&vrf.Id, &vrf.CreateDate)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return vrf, errors.VrfConflict
+		}
+		return vrf, fmt.Errorf("failed to create vrf: %w", err)
+	}
+	return vrf, nil
+}
+
+func (r *VrfRepoImpl) UpdateVrf(ctx context.Context, id int64, vrf model.Vrf) error {
+	query := `
+		UPDATE Vrfs SET
+			name = $1, routedistinguisher = $2, description = $3,
+			lastmodifiedby = $4, modifieddate = NOW()
+		WHERE id = $5`
+
+	result, err := r.sql.Db.ExecContext(ctx, query,
+		vrf.Name,
+		vrf.RouteDistinguisher,
+		vrf.Description,
+		vrf.LastModifiedBy,
+		id,
+	)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return errors.VrfConflict
+		}
+		return fmt.Errorf("failed to update vrf: %w", err)
+	}
+	return requireAffected(result, errors.VrfNotFound)
+}
+
+// DeleteVrf chỉ xóa được VRF không còn VLAN, subnet hoặc asset nào (kể cả asset đã xóa mềm)
+func (r *VrfRepoImpl) DeleteVrf(ctx context.Context, id int64) error {
+	result, err := r.sql.Db.ExecContext(ctx, "DELETE FROM Vrfs WHERE id = $1", id)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return errors.VrfInUse
+		}
+		return fmt.Errorf("failed to delete vrf: %w", err)
+	}
+	return requireAffected(result, errors.VrfNotFound)
+}
+
+func (r *VrfRepoImpl) GetVlans(ctx context.Context, vrf string) ([]model.Vlan, error) {
+	query := "SELECT " + vlanColumns + " FROM Vlans"
+	var args []interface{}
+	if vrf != "" {
+		query += " WHERE " + vrfCondition("vrfid", 1)
+		args = append(args, vrf)
+	}
+	query += " ORDER BY vlanid"
+
+	vlans := []model.Vlan{}
+	if err := r.sql.Db.SelectContext(ctx, &vlans, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to query vlans: %w", err)
+	}
+	return vlans, nil
+}
+
+func (r *VrfRepoImpl) GetVlan(ctx context.Context, vlanId int) (*model.Vlan, error) {
+	var vlan model.Vlan
+	if err := r.sql.Db.GetContext(ctx, &vlan, "SELECT "+vlanColumns+" FROM Vlans WHERE vlanid = $1", vlanId); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.VlanNotFound
+		}
+		return nil, fmt.Errorf("failed to get vlan: %w", err)
+	}
+	return &vlan, nil
+}
+
+func (r *VrfRepoImpl) CreateVlan(ctx context.Context, vlan model.Vlan) (model.Vlan, error) {
+	query := `
+		INSERT INTO Vlans (vlanid, name, description, vrfid, lastmodifiedby)
+		VALUES ($1, $2, $3, NULLIF($4, 0), $5)
+		RETURNING createdate`
+
+	err := r.sql.Db.QueryRowxContext(ctx, query,
+		vlan.VlanId,
+		vlan.Name,
+		vlan.Description,
+		vlan.VrfId,
+		vlan.LastModifiedBy,
+	).Scan(&vlan.CreateDate)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			switch err.Code.Name() {
+			case "unique_violation":
+				return vlan, errors.VlanConflict
+			case "foreign_key_violation":
+				return vlan, errors.VrfNotFound
+			}
+		}
+		return vlan, fmt.Errorf("failed to create vlan: %w", err)
+	}
+	return vlan, nil
+}
+
+// UpdateVlan không cho đổi VLAN sang VRF khác khi đang có subnet hoặc asset của VRF cũ dùng VLAN
+func (r *VrfRepoImpl) UpdateVlan(ctx context.Context, vlanId int, vlan model.Vlan) error {
+	return withTx(ctx, r.sql.Db, func(tx *sqlx.Tx) error {
+		var exists int
+		if err := tx.QueryRowxContext(ctx, "SELECT 1 FROM Vlans WHERE vlanid = $1 FOR UPDATE", vlanId).Scan(&exists); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.VlanNotFound
+			}
+			return fmt.Errorf("failed to get vlan: %w", err)
+		}
+
+		if vlan.VrfId != 0 {
+			var mismatch bool
+			query := `
+				SELECT EXISTS (SELECT 1 FROM NetworkAssets WHERE vlanid = $1 AND vrfid IS DISTINCT FROM $2)
+				    OR EXISTS (SELECT 1 FROM Subnets WHERE vlanid = $1 AND vrfid IS DISTINCT FROM $2)`
+			if err := tx.QueryRowxContext(ctx, query, vlanId, int64(vlan.VrfId)).Scan(&mismatch); err != nil {
+				return fmt.Errorf("failed to check vlan members: %w", err)
+			}
+			if mismatch {
+				return errors.VlanVrfMismatch
+			}
+		}
+
+		query := `
+			UPDATE Vlans SET
+				name = $1, description = $2, vrfid = NULLIF($3, 0),
+				lastmodifiedby = $4, modifieddate = NOW()
+			WHERE vlanid = $5`
+
+		_, err := tx.ExecContext(ctx, query,
+			vlan.Name,
+			vlan.Description,
+			vlan.VrfId,
+			vlan.LastModifiedBy,
+			vlanId,
+		)
+		if err != nil {
+			if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+				return errors.VrfNotFound
+			}
+			return fmt.Errorf("failed to update vlan: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *VrfRepoImpl) DeleteVlan(ctx context.Context, vlanId int) error {
+	result, err := r.sql.Db.ExecContext(ctx, "DELETE FROM Vlans WHERE vlanid = $1", vlanId)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return errors.VlanInUse
+		}
+		return fmt.Errorf("failed to delete vlan: %w", err)
+	}
+	return requireAffected(result, errors.VlanNotFound)
+}
+
+// requireAffected trả về notFound nếu câu lệnh không tác động dòng nào
+func requireAffected(result sql.Result, notFound error) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return notFound
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type SubnetRepo interface {
+	GetSubnets(ctx context.Context, filter model.SubnetFilter) ([]model.Subnet, error)
+	// GetSubnet tìm theo id hoặc CIDR, CIDR được tìm trong VRF có tên vrf (rỗng là global)
+	GetSubnet(ctx context.Context, ref, vrf string) (*model.Subnet, error)
+	CreateSubnet(ctx context.Context, subnet model.Subnet) (model.Subnet, error)
+	UpdateSubnet(ctx context.Context, id int64, subnet model.Subnet) error
+	DeleteSubnet(ctx context.Context, id int64) error
+
+	CreateReservation(ctx context.Context, reservation model.SubnetReservation) (model.SubnetReservation, error)
+	DeleteReservation(ctx context.Context, subnetId, id int64) error
+
+	// AllocateAddresses cấp count địa chỉ liên tiếp (từ start nếu có) và tạo asset theo template
+	AllocateAddresses(ctx context.Context, subnetId int64, start string, count int, template model.NetworkAsset, actor string) ([]model.NetworkAsset, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sllpklls/template-backend-go/model"
+)
+
+type VrfRepo interface {
+	GetVrfs(ctx context.Context) ([]model.Vrf, error)
+	GetVrf(ctx context.Context, id int64) (*model.Vrf, error)
+	CreateVrf(ctx context.Context, vrf model.Vrf) (model.Vrf, error)
+	UpdateVrf(ctx context.Context, id int64, vrf model.Vrf) error
+	DeleteVrf(ctx context.Context, id int64) error
+
+	// GetVlans trả về mọi VLAN, lọc theo VRF nếu vrf khác rỗng
+	GetVlans(ctx context.Context, vrf string) ([]model.Vlan, error)
+	GetVlan(ctx context.Context, vlanId int) (*model.Vlan, error)
+	CreateVlan(ctx context.Context, vlan model.Vlan) (model.Vlan, error)
+	UpdateVlan(ctx context.Context, vlanId int, vlan model.Vlan) error
+	DeleteVlan(ctx context.Context, vlanId int) error
+}
@@ -4,12 +4,22 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/sllpklls/template-backend-go/handler"
 	"github.com/sllpklls/template-backend-go/middleware"
+	"github.com/sllpklls/template-backend-go/model"
 )
 
 type API struct {
-	Echo                *echo.Echo
-	UserHandler         handler.UserHandler
-	NetworkAssetHandler handler.NetworkAssetHandler
+	Echo                  *echo.Echo
+	UserHandler           handler.UserHandler
+	NetworkAssetHandler   handler.NetworkAssetHandler
+	CIHandler             handler.CIHandler
+	RelationshipHandler   handler.RelationshipHandler
+	ReconciliationHandler handler.ReconciliationHandler
+	SubnetHandler         handler.SubnetHandler
+	VrfHandler            handler.VrfHandler
+	DNSCheckHandler       handler.DNSCheckHandler
+	ConnectorHandler      handler.ConnectorHandler
+	EtlHandler            handler.EtlHandler
+	MappingHandler        handler.MappingHandler
 }
 
 func (api *API) SetupRouter() {
@@ -27,10 +37,95 @@ func (api *API) SetupRouter() {
 	v1.GET("/network-assets", api.NetworkAssetHandler.GetAllNetworkAssets)
 	v1.GET("/network-assets/search", api.NetworkAssetHandler.SearchNetworkAssets)
 	v1.GET("/network-assets/search-dns", api.NetworkAssetHandler.SearchByDNSHostName)
+	v1.GET("/network-assets/export", api.NetworkAssetHandler.ExportNetworkAssets)
 	v1.GET("/network-assets/:name", api.NetworkAssetHandler.GetNetworkAssetByName)
 	v1.POST("/network-assets", api.NetworkAssetHandler.CreateNetworkAsset)
+	v1.POST("/network-assets/import", api.NetworkAssetHandler.ImportNetworkAssets)
 	v1.PUT("/network-assets/:name", api.NetworkAssetHandler.UpdateNetworkAsset)
 	v1.DELETE("/network-assets/:name", api.NetworkAssetHandler.DeleteNetworkAsset)
+	v1.POST("/network-assets/:name/restore", api.NetworkAssetHandler.RestoreNetworkAsset)
+	v1.GET("/network-assets/:name/history", api.NetworkAssetHandler.GetNetworkAssetHistory)
+	v1.GET("/network-assets/:name/ports", api.NetworkAssetHandler.GetNetworkAssetPorts)
+
+	v1.GET("/network-assets/:name/relationships", api.RelationshipHandler.GetRelationships(model.NetworkAssetClass))
+	v1.POST("/network-assets/:name/relationships", api.RelationshipHandler.CreateRelationship(model.NetworkAssetClass))
+	v1.DELETE("/network-assets/:name/relationships/:id", api.RelationshipHandler.DeleteRelationship(model.NetworkAssetClass))
+	v1.GET("/network-assets/:name/impact", api.RelationshipHandler.GetImpact(model.NetworkAssetClass))
+
+	v1.GET("/reports/ip-conflicts", api.NetworkAssetHandler.GetIPConflicts)
+
+	v1.POST("/import/zone", api.NetworkAssetHandler.ImportZone)
+	v1.POST("/import/dhcp", api.NetworkAssetHandler.ImportLeases)
+	v1.POST("/import/nmap", api.NetworkAssetHandler.ImportNmap)
+	v1.GET("/export/zone", api.NetworkAssetHandler.ExportZone)
+
+	v1.GET("/checks/dns", api.DNSCheckHandler.GetDNSCheckResults)
+	v1.POST("/checks/dns", api.DNSCheckHandler.RunDNSCheck)
+
+	v1.GET("/connectors/remedy", api.ConnectorHandler.GetRemedyState)
+	v1.POST("/connectors/remedy/run", api.ConnectorHandler.RunRemedy)
+
+	// ETL job đọc file và gọi Remedy bằng quyền của server nên chỉ ADMIN được tạo, sửa, xóa và chạy
+	adminOnly := middleware.RequireRole(model.ADMIN.String())
+	v1.GET("/etl/jobs", api.EtlHandler.GetJobs)
+	v1.POST("/etl/jobs", api.EtlHandler.CreateJob, adminOnly)
+	v1.GET("/etl/jobs/:id", api.EtlHandler.GetJob)
+	v1.PUT("/etl/jobs/:id", api.EtlHandler.UpdateJob, adminOnly)
+	v1.DELETE("/etl/jobs/:id", api.EtlHandler.DeleteJob, adminOnly)
+	v1.POST("/etl/jobs/:id/run", api.EtlHandler.RunJob, adminOnly)
+	v1.GET("/etl/jobs/:id/runs", api.EtlHandler.GetRunsByJob)
+	v1.GET("/etl/runs/:id", api.EtlHandler.GetRun)
+	v1.GET("/etl/mappings", api.MappingHandler.GetMappings)
+	v1.GET("/etl/mappings/:id", api.MappingHandler.GetMapping)
+	v1.POST("/etl/mappings/:id/preview", api.MappingHandler.PreviewMapping)
+
+	v1.GET("/reconciliation/jobs", api.ReconciliationHandler.GetJobs)
+	v1.POST("/reconciliation/jobs", api.ReconciliationHandler.CreateJob)
+	v1.GET("/reconciliation/jobs/:id", api.ReconciliationHandler.GetJob)
+	v1.PUT("/reconciliation/jobs/:id", api.ReconciliationHandler.UpdateJob)
+	v1.DELETE("/reconciliation/jobs/:id", api.ReconciliationHandler.DeleteJob)
+	v1.POST("/reconciliation/jobs/:id/run", api.ReconciliationHandler.RunJob)
+	v1.GET("/reconciliation/jobs/:id/runs", api.ReconciliationHandler.GetRunsByJob)
+	v1.GET("/reconciliation/runs/:id", api.ReconciliationHandler.GetRun)
+
+	v1.GET("/subnets", api.SubnetHandler.GetSubnets)
+	v1.GET("/subnets/tree", api.SubnetHandler.GetSubnetTree)
+	v1.POST("/subnets", api.SubnetHandler.CreateSubnet)
+	v1.GET("/subnets/:cidr", api.SubnetHandler.GetSubnet)
+	v1.PUT("/subnets/:cidr", api.SubnetHandler.UpdateSubnet)
+	v1.DELETE("/subnets/:cidr", api.SubnetHandler.DeleteSubnet)
+	v1.POST("/subnets/:cidr/allocate", api.SubnetHandler.AllocateAddresses)
+	v1.POST("/subnets/:cidr/reservations", api.SubnetHandler.CreateReservation)
+	v1.DELETE("/subnets/:cidr/reservations/:id", api.SubnetHandler.DeleteReservation)
+
+	v1.GET("/vrfs", api.VrfHandler.GetVrfs)
+	v1.POST("/vrfs", api.VrfHandler.CreateVrf)
+	v1.GET("/vrfs/:id", api.VrfHandler.GetVrf)
+	v1.PUT("/vrfs/:id", api.VrfHandler.UpdateVrf)
+	v1.DELETE("/vrfs/:id", api.VrfHandler.DeleteVrf)
+	v1.GET("/vlans", api.VrfHandler.GetVlans)
+	v1.POST("/vlans", api.VrfHandler.CreateVlan)
+	v1.GET("/vlans/:vlan_id", api.VrfHandler.GetVlan)
+	v1.PUT("/vlans/:vlan_id", api.VrfHandler.UpdateVlan)
+	v1.DELETE("/vlans/:vlan_id", api.VrfHandler.DeleteVlan)
+
+	// Route sinh tự động cho mọi CI class trong registry
+	v1.GET("/classes", api.CIHandler.GetClasses)
+	for _, class := range api.CIHandler.Registry.Classes() {
+		g := v1.Group("/" + class.Path)
+		g.GET("", api.CIHandler.GetAll(class))
+		g.GET("/:name", api.CIHandler.GetByName(class))
+		g.POST("", api.CIHandler.Create(class))
+		g.PUT("/:name", api.CIHandler.Update(class))
+		g.DELETE("/:name", api.CIHandler.Delete(class))
+		g.GET("/:name/relationships", api.RelationshipHandler.GetRelationships(class.Name))
+		g.POST("/:name/relationships", api.RelationshipHandler.CreateRelationship(class.Name))
+		g.DELETE("/:name/relationships/:id", api.RelationshipHandler.DeleteRelationship(class.Name))
+		g.GET("/:name/impact", api.RelationshipHandler.GetImpact(class.Name))
+	}
+
+	admin := v1.Group("/admin", adminOnly)
+	admin.POST("/network-assets/purge", api.NetworkAssetHandler.PurgeNetworkAssets)
 
 	public := api.Echo.Group("/api/public")
 	public.GET("/ip-endpoint/check-dns", api.NetworkAssetHandler.CheckExistByDNSHostName)
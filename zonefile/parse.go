@@ -0,0 +1,299 @@
+// Package zonefile đọc và ghi zone file theo định dạng master file của BIND (RFC 1035 mục 5).
+// Tên miền luôn ở dạng FQDN chữ thường có dấu chấm cuối ("web.local.").
+package zonefile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var classes = map[string]bool{"IN": true, "CH": true, "HS": true, "CS": true}
+
+// Các loại bản ghi có dữ liệu là tên miền, được chuẩn hóa thành FQDN khi đọc
+var nameDataTypes = map[string]bool{"PTR": true, "NS": true, "CNAME": true, "DNAME": true}
+
+type Record struct {
+	Line  int    // dòng bắt đầu bản ghi trong file
+	Name  string // owner, FQDN
+	TTL   uint32
+	Class string
+	Type  string
+	Data  []string
+}
+
+// ParseError là lỗi cú pháp tại một dòng của zone file
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Parse đọc mọi bản ghi trong zone file. origin là $ORIGIN ban đầu (có thể rỗng nếu file tự khai
+// báo $ORIGIN hoặc chỉ dùng FQDN). $INCLUDE và $GENERATE không được hỗ trợ.
+func Parse(r io.Reader, origin string) ([]Record, error) {
+	p := parser{origin: Fqdn(origin)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var (
+		tokens    []string
+		startLine int
+		indented  bool
+		depth     int
+	)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if depth == 0 {
+			startLine = line
+			indented = len(text) > 0 && unicode.IsSpace(rune(text[0]))
+		}
+
+		lineTokens, err := tokenize(text)
+		if err != nil {
+			return nil, &ParseError{Line: line, Message: err.Error()}
+		}
+		for _, tok := range lineTokens {
+			switch tok {
+			case "(":
+				depth++
+			case ")":
+				if depth == 0 {
+					return nil, &ParseError{Line: line, Message: "unbalanced parenthesis"}
+				}
+				depth--
+			default:
+				tokens = append(tokens, tok)
+			}
+		}
+		if depth > 0 {
+			continue
+		}
+
+		if len(tokens) > 0 {
+			if err := p.entry(startLine, tokens, indented); err != nil {
+				return nil, err
+			}
+		}
+		tokens = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read zone file: %w", err)
+	}
+	if depth > 0 {
+		return nil, &ParseError{Line: startLine, Message: "unbalanced parenthesis"}
+	}
+	return p.records, nil
+}
+
+type parser struct {
+	origin  string
+	ttl     uint32
+	hasTTL  bool
+	owner   string
+	records []Record
+}
+
+func (p *parser) entry(line int, tokens []string, indented bool) error {
+	fail := func(format string, args ...interface{}) error {
+		return &ParseError{Line: line, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if strings.HasPrefix(tokens[0], "$") {
+		directive := strings.ToUpper(tokens[0])
+		switch directive {
+		case "$ORIGIN":
+			if len(tokens) < 2 || !strings.HasSuffix(tokens[1], ".") {
+				return fail("$ORIGIN requires a fully qualified domain name")
+			}
+			p.origin = Fqdn(tokens[1])
+		case "$TTL":
+			if len(tokens) < 2 {
+				return fail("$TTL requires a value")
+			}
+			ttl, err := ParseTTL(tokens[1])
+			if err != nil {
+				return fail("%v", err)
+			}
+			p.ttl, p.hasTTL = ttl, true
+		default:
+			return fail("%s is not supported", directive)
+		}
+		return nil
+	}
+
+	record := Record{Line: line, Class: "IN"}
+	if indented {
+		if p.owner == "" {
+			return fail("record without owner name")
+		}
+		record.Name = p.owner
+	} else {
+		name, err := p.qualify(tokens[0])
+		if err != nil {
+			return fail("%v", err)
+		}
+		record.Name = name
+		tokens = tokens[1:]
+	}
+
+	// TTL và class là tùy chọn, theo thứ tự bất kỳ
+	ttlSet := false
+	for i := 0; i < 2 && len(tokens) > 0; i++ {
+		if classes[strings.ToUpper(tokens[0])] {
+			record.Class = strings.ToUpper(tokens[0])
+			tokens = tokens[1:]
+		} else if ttl, err := ParseTTL(tokens[0]); err == nil {
+			record.TTL, ttlSet = ttl, true
+			tokens = tokens[1:]
+		}
+	}
+	if len(tokens) == 0 {
+		return fail("missing record type")
+	}
+	record.Type = strings.ToUpper(tokens[0])
+	record.Data = tokens[1:]
+	if len(record.Data) == 0 {
+		return fail("%s record has no data", record.Type)
+	}
+
+	if !ttlSet {
+		record.TTL = p.ttl
+		// Không có $TTL thì dùng minimum của SOA (cách BIND xử lý file cũ)
+		if !p.hasTTL && record.Type == "SOA" && len(record.Data) == 7 {
+			if ttl, err := ParseTTL(record.Data[6]); err == nil {
+				record.TTL = ttl
+				p.ttl = ttl
+			}
+		}
+	} else if !p.hasTTL {
+		p.ttl = record.TTL
+	}
+
+	if nameDataTypes[record.Type] {
+		target, err := p.qualify(record.Data[0])
+		if err != nil {
+			return fail("%v", err)
+		}
+		record.Data[0] = target
+	}
+
+	p.owner = record.Name
+	p.records = append(p.records, record)
+	return nil
+}
+
+// qualify chuyển tên tương đối thành FQDN theo $ORIGIN hiện tại
+func (p *parser) qualify(name string) (string, error) {
+	if name == "@" {
+		if p.origin == "" {
+			return "", fmt.Errorf("@ used without $ORIGIN")
+		}
+		return p.origin, nil
+	}
+	if strings.HasSuffix(name, ".") {
+		return strings.ToLower(name), nil
+	}
+	if p.origin == "" {
+		return "", fmt.Errorf("relative name %q used without $ORIGIN", name)
+	}
+	if p.origin == "." {
+		return strings.ToLower(name) + ".", nil
+	}
+	return strings.ToLower(name) + "." + p.origin, nil
+}
+
+// tokenize tách một dòng thành các token, bỏ comment (;), giữ nguyên chuỗi trong ngoặc kép
+// và tách "(" ")" thành token riêng
+func tokenize(line string) ([]string, error) {
+	var tokens []string
+	var cur strings.Builder
+	inQuote := false
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case inQuote:
+			cur.WriteByte(ch)
+			if ch == '\\' && i+1 < len(line) {
+				i++
+				cur.WriteByte(line[i])
+			} else if ch == '"' {
+				inQuote = false
+			}
+		case ch == '"':
+			cur.WriteByte(ch)
+			inQuote = true
+		case ch == ';':
+			flush()
+			return tokens, nil
+		case ch == '(' || ch == ')':
+			flush()
+			tokens = append(tokens, string(ch))
+		case ch == ' ' || ch == '\t' || ch == '\r':
+			flush()
+		default:
+			cur.WriteByte(ch)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quoted string")
+	}
+	flush()
+	return tokens, nil
+}
+
+// ParseTTL nhận số giây ("3600") hoặc dạng BIND có đơn vị ("1h30m", "1w")
+func ParseTTL(s string) (uint32, error) {
+	if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return uint32(n), nil
+	}
+
+	units := map[byte]uint64{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	var total, num uint64
+	digits := false
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch >= '0' && ch <= '9' {
+			num = num*10 + uint64(ch-'0')
+			digits = true
+			continue
+		}
+		unit, ok := units[byte(unicode.ToLower(rune(ch)))]
+		if !ok || !digits {
+			return 0, fmt.Errorf("invalid TTL %q", s)
+		}
+		total += num * unit
+		num, digits = 0, false
+	}
+	if digits {
+		return 0, fmt.Errorf("invalid TTL %q", s)
+	}
+	if total == 0 || total > 1<<31-1 {
+		return 0, fmt.Errorf("invalid TTL %q", s)
+	}
+	return uint32(total), nil
+}
+
+// Fqdn chuẩn hóa tên miền thành chữ thường có dấu chấm cuối, rỗng vẫn là rỗng
+func Fqdn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
@@ -0,0 +1,190 @@
+package zonefile
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// recordString mô tả bản ghi gọn để so sánh: "owner ttl class type data"
+func recordString(r Record) string {
+	return fmt.Sprintf("%s %d %s %s %s", r.Name, r.TTL, r.Class, r.Type, strings.Join(r.Data, " "))
+}
+
+func recordStrings(records []Record) []string {
+	var out []string
+	for _, r := range records {
+		out = append(out, recordString(r))
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+		input  string
+		want   []string
+		err    string
+	}{
+		{
+			name: "directives, relative names and multi-line soa",
+			input: `
+$ORIGIN Local.
+$TTL 1h
+@	IN	SOA	ns1 hostmaster (
+		2024010101	; serial
+		3600 900 1209600 300 )
+	IN	NS	ns1
+ns1	IN	A	192.168.1.1
+web	300	IN	A	192.168.1.10
+	IN	AAAA	2001:db8::10 ; cùng owner với dòng trên
+www	IN	CNAME	web
+txt	IN	TXT	"a ; b" "c"`,
+			want: []string{
+				"local. 3600 IN SOA ns1 hostmaster 2024010101 3600 900 1209600 300",
+				"local. 3600 IN NS ns1.local.",
+				"ns1.local. 3600 IN A 192.168.1.1",
+				"web.local. 300 IN A 192.168.1.10",
+				"web.local. 3600 IN AAAA 2001:db8::10",
+				"www.local. 3600 IN CNAME web.local.",
+				`txt.local. 3600 IN TXT "a ; b" "c"`,
+			},
+		},
+		{
+			name:   "origin argument and ttl before class",
+			origin: "corp.test",
+			input: `
+db 60 IN A 10.0.0.1
+db IN 120 A 10.0.0.2
+10.0.0.10.in-addr.arpa. PTR db`,
+			want: []string{
+				"db.corp.test. 60 IN A 10.0.0.1",
+				"db.corp.test. 120 IN A 10.0.0.2",
+				"10.0.0.10.in-addr.arpa. 120 IN PTR db.corp.test.",
+			},
+		},
+		{
+			name: "soa minimum without $TTL",
+			input: `
+example. IN SOA ns.example. admin.example. 1 3600 900 1209600 600
+www.example. IN A 10.0.0.1`,
+			want: []string{
+				"example. 600 IN SOA ns.example. admin.example. 1 3600 900 1209600 600",
+				"www.example. 600 IN A 10.0.0.1",
+			},
+		},
+		{name: "relative name without origin", input: "web IN A 10.0.0.1", err: "line 1: relative name"},
+		{name: "record without owner", input: "\tIN A 10.0.0.1", err: "line 1: record without owner name"},
+		{name: "unbalanced parenthesis", input: "$ORIGIN local.\n@ IN SOA ns1 hm ( 1 2\n3 4 5", err: "line 2: unbalanced parenthesis"},
+		{name: "unsupported directive", input: "$INCLUDE other.zone", err: "line 1: $INCLUDE is not supported"},
+		{name: "unterminated quote", input: `$ORIGIN local.` + "\n" + `t IN TXT "abc`, err: "line 2: unterminated quoted string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := Parse(strings.NewReader(tt.input), tt.origin)
+			if tt.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want prefix %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := recordStrings(records); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("records:\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRoundTrip đọc zone file, ghi lại bằng Write rồi đọc lần nữa: bản ghi phải giữ nguyên
+// (đã sắp xếp, bỏ trùng), SOA và NS được sinh từ cấu hình zone
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+		input  string
+	}{
+		{
+			name:   "forward zone",
+			origin: "local.",
+			input: `
+$ORIGIN local.
+$TTL 300
+web	IN	A	192.168.1.10
+web	IN	A	192.168.1.10
+web	IN	AAAA	2001:db8::10
+db	60	IN	A	192.168.1.20
+mail.other.test.	IN	A	10.9.9.9
+@	IN	MX	10 mail.other.test.`,
+		},
+		{
+			name:   "reverse zone",
+			origin: "1.168.192.in-addr.arpa.",
+			input: `
+$ORIGIN 1.168.192.in-addr.arpa.
+$TTL 1d
+10	IN	PTR	web.local.
+20	IN	PTR	db.local.`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := Parse(strings.NewReader(tt.input), "")
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+
+			var buf bytes.Buffer
+			zone := Zone{Origin: tt.origin, TTL: 300, Serial: 42, PrimaryNS: "ns1.other.test", Hostmaster: "hostmaster.other.test"}
+			zone.Records = records
+			if err := Write(&buf, zone); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+
+			again, err := Parse(&buf, "")
+			if err != nil {
+				t.Fatalf("Parse written zone: %v\n%s", err, buf.String())
+			}
+			got := recordStrings(again)
+			want := []string{
+				fmt.Sprintf("%s 300 IN SOA ns1.other.test. hostmaster.other.test. 42 %d %d %d %d",
+					tt.origin, soaRefresh, soaRetry, soaExpire, soaMinimum),
+				tt.origin + " 300 IN NS ns1.other.test.",
+			}
+			seen := map[string]bool{}
+			for _, s := range recordStrings(sorted(records)) {
+				if !seen[s] {
+					seen[s] = true
+					want = append(want, s)
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip:\n got %q\nwant %q\nzone:\n%s", got, want, buf.String())
+			}
+		})
+	}
+}
+
+// sorted sắp bản ghi theo owner, type, data như Write
+func sorted(records []Record) []Record {
+	out := append([]Record{}, records...)
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return strings.Join(a.Data, " ") < strings.Join(b.Data, " ")
+	})
+	return out
+}
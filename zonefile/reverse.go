@@ -0,0 +1,99 @@
+package zonefile
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+const (
+	reverseV4 = "in-addr.arpa."
+	reverseV6 = "ip6.arpa."
+)
+
+// ReverseName trả về tên PTR của địa chỉ: 10.1.168.192.in-addr.arpa. hoặc dạng nibble trong ip6.arpa.
+func ReverseName(addr netip.Addr) string {
+	addr = addr.Unmap()
+	var labels []string
+	if addr.Is4() {
+		b := addr.As4()
+		for i := len(b) - 1; i >= 0; i-- {
+			labels = append(labels, strconv.Itoa(int(b[i])))
+		}
+		return strings.Join(labels, ".") + "." + reverseV4
+	}
+
+	b := addr.As16()
+	for i := len(b) - 1; i >= 0; i-- {
+		labels = append(labels, fmt.Sprintf("%x", b[i]&0x0f), fmt.Sprintf("%x", b[i]>>4))
+	}
+	return strings.Join(labels, ".") + "." + reverseV6
+}
+
+// AddrFromReverse là ngược lại của ReverseName, chỉ nhận tên đủ 4 octet hoặc 32 nibble
+func AddrFromReverse(name string) (netip.Addr, bool) {
+	prefix, ok := ReversePrefix(name)
+	if !ok || !prefix.IsSingleIP() {
+		return netip.Addr{}, false
+	}
+	return prefix.Addr(), true
+}
+
+// ReversePrefix trả về dải địa chỉ mà một reverse zone quản lý, ví dụ 1.168.192.in-addr.arpa.
+// là 192.168.1.0/24. Chỉ hỗ trợ zone theo ranh giới octet (IPv4) hoặc nibble (IPv6).
+func ReversePrefix(origin string) (netip.Prefix, bool) {
+	origin = Fqdn(origin)
+	switch {
+	case origin == reverseV4 || strings.HasSuffix(origin, "."+reverseV4):
+		labels := splitLabels(strings.TrimSuffix(origin, reverseV4))
+		if len(labels) > 4 {
+			return netip.Prefix{}, false
+		}
+		var b [4]byte
+		for i, label := range labels {
+			n, err := strconv.ParseUint(label, 10, 8)
+			if err != nil || strconv.Itoa(int(n)) != label {
+				return netip.Prefix{}, false
+			}
+			b[len(labels)-1-i] = byte(n)
+		}
+		return netip.PrefixFrom(netip.AddrFrom4(b), len(labels)*8), true
+
+	case origin == reverseV6 || strings.HasSuffix(origin, "."+reverseV6):
+		labels := splitLabels(strings.TrimSuffix(origin, reverseV6))
+		if len(labels) > 32 {
+			return netip.Prefix{}, false
+		}
+		var b [16]byte
+		for i, label := range labels {
+			n, err := strconv.ParseUint(label, 16, 4)
+			if err != nil || len(label) != 1 {
+				return netip.Prefix{}, false
+			}
+			pos := len(labels) - 1 - i // vị trí nibble tính từ đầu địa chỉ
+			if pos%2 == 0 {
+				b[pos/2] |= byte(n) << 4
+			} else {
+				b[pos/2] |= byte(n)
+			}
+		}
+		return netip.PrefixFrom(netip.AddrFrom16(b), len(labels)*4), true
+	}
+	return netip.Prefix{}, false
+}
+
+// IsReverseZone cho biết origin là zone in-addr.arpa hoặc ip6.arpa
+func IsReverseZone(origin string) bool {
+	origin = Fqdn(origin)
+	return origin == reverseV4 || origin == reverseV6 ||
+		strings.HasSuffix(origin, "."+reverseV4) || strings.HasSuffix(origin, "."+reverseV6)
+}
+
+func splitLabels(name string) []string {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" {
+		return nil
+	}
+	return strings.Split(name, ".")
+}
@@ -0,0 +1,65 @@
+package zonefile
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestReverseName(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"192.168.1.10", "10.1.168.192.in-addr.arpa."},
+		{"::ffff:10.0.0.1", "1.0.0.10.in-addr.arpa."},
+		{"2001:db8::567:89ab", "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa."},
+	}
+	for _, tt := range tests {
+		addr := netip.MustParseAddr(tt.addr)
+		name := ReverseName(addr)
+		if name != tt.want {
+			t.Errorf("ReverseName(%s) = %s, want %s", tt.addr, name, tt.want)
+		}
+		back, ok := AddrFromReverse(name)
+		if !ok || back != addr.Unmap() {
+			t.Errorf("AddrFromReverse(%s) = %s, %v, want %s", name, back, ok, addr.Unmap())
+		}
+	}
+}
+
+func TestReversePrefix(t *testing.T) {
+	tests := []struct {
+		origin string
+		want   string // rỗng là origin không hợp lệ
+	}{
+		{"1.168.192.in-addr.arpa.", "192.168.1.0/24"},
+		{"168.192.IN-ADDR.ARPA", "192.168.0.0/16"},
+		{"10.in-addr.arpa.", "10.0.0.0/8"},
+		{"in-addr.arpa.", "0.0.0.0/0"},
+		{"8.b.d.0.1.0.0.2.ip6.arpa.", "2001:db8::/32"},
+		{"0.8.b.d.0.1.0.0.2.ip6.arpa.", "2001:db8::/36"},
+		{"ip6.arpa.", "::/0"},
+		{"256.168.192.in-addr.arpa.", ""},
+		{"01.168.192.in-addr.arpa.", ""},
+		{"0-63.1.168.192.in-addr.arpa.", ""},
+		{"5.4.3.2.1.in-addr.arpa.", ""},
+		{"10.8.b.d.0.1.0.0.2.ip6.arpa.", ""},
+		{"g.ip6.arpa.", ""},
+		{"local.", ""},
+	}
+	for _, tt := range tests {
+		prefix, ok := ReversePrefix(tt.origin)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("ReversePrefix(%s) = %s, want invalid", tt.origin, prefix)
+			}
+			continue
+		}
+		if !ok || prefix.String() != tt.want {
+			t.Errorf("ReversePrefix(%s) = %s, %v, want %s", tt.origin, prefix, ok, tt.want)
+		}
+		if !IsReverseZone(tt.origin) {
+			t.Errorf("IsReverseZone(%s) = false", tt.origin)
+		}
+	}
+}
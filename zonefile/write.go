@@ -0,0 +1,107 @@
+package zonefile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Zone là nội dung một zone file cần sinh, SOA và NS được sinh từ các field cấu hình
+type Zone struct {
+	Origin     string // FQDN
+	TTL        uint32
+	Serial     uint32
+	PrimaryNS  string // MNAME của SOA, cũng là bản ghi NS của zone
+	Hostmaster string // RNAME của SOA dạng tên miền (hostmaster.local.)
+	Records    []Record
+}
+
+// Giá trị SOA mặc định theo khuyến nghị RFC 1912
+const (
+	soaRefresh = 3600
+	soaRetry   = 900
+	soaExpire  = 1209600
+	soaMinimum = 300
+)
+
+var labelPattern = regexp.MustCompile(`^[a-z0-9_]([a-z0-9_-]{0,61}[a-z0-9_])?$`)
+
+// ValidName kiểm tra tên miền gồm các label hợp lệ để ghi vào zone file
+func ValidName(name string) bool {
+	name = strings.TrimSuffix(Fqdn(name), ".")
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !labelPattern.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// InZone cho biết name (FQDN) là origin hoặc nằm dưới origin
+func InZone(name, origin string) bool {
+	name, origin = Fqdn(name), Fqdn(origin)
+	return name == origin || strings.HasSuffix(name, "."+origin)
+}
+
+// Write ghi zone file: $ORIGIN, $TTL, SOA, NS rồi các bản ghi theo thứ tự owner, type, data.
+// Bản ghi trùng lặp được bỏ bớt, owner được ghi dạng tương đối so với origin.
+func Write(w io.Writer, zone Zone) error {
+	origin := Fqdn(zone.Origin)
+	if origin == "" {
+		return fmt.Errorf("origin is required")
+	}
+	buf := bufio.NewWriter(w)
+
+	fmt.Fprintf(buf, "$ORIGIN %s\n", origin)
+	fmt.Fprintf(buf, "$TTL %d\n", zone.TTL)
+	fmt.Fprintf(buf, "@\tIN\tSOA\t%s %s (\n", Fqdn(zone.PrimaryNS), Fqdn(zone.Hostmaster))
+	fmt.Fprintf(buf, "\t\t%d\t; serial\n", zone.Serial)
+	fmt.Fprintf(buf, "\t\t%d\t; refresh\n", soaRefresh)
+	fmt.Fprintf(buf, "\t\t%d\t; retry\n", soaRetry)
+	fmt.Fprintf(buf, "\t\t%d\t; expire\n", soaExpire)
+	fmt.Fprintf(buf, "\t\t%d )\t; minimum\n", soaMinimum)
+	fmt.Fprintf(buf, "@\tIN\tNS\t%s\n", Fqdn(zone.PrimaryNS))
+
+	records := append([]Record{}, zone.Records...)
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return strings.Join(a.Data, " ") < strings.Join(b.Data, " ")
+	})
+
+	var prev string
+	for _, r := range records {
+		line := fmt.Sprintf("%s\tIN\t%s\t%s", relative(r.Name, origin), r.Type, strings.Join(r.Data, " "))
+		if r.TTL != 0 {
+			line = fmt.Sprintf("%s\t%d\tIN\t%s\t%s", relative(r.Name, origin), r.TTL, r.Type, strings.Join(r.Data, " "))
+		}
+		if line == prev {
+			continue
+		}
+		prev = line
+		fmt.Fprintln(buf, line)
+	}
+	return buf.Flush()
+}
+
+func relative(name, origin string) string {
+	name = Fqdn(name)
+	if name == origin {
+		return "@"
+	}
+	if origin != "." && strings.HasSuffix(name, "."+origin) {
+		return strings.TrimSuffix(name, "."+origin)
+	}
+	return name
+}